@@ -0,0 +1,297 @@
+package dashreader
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	//DefaultABRFastHalfLife - Half-life (download time) of the fast throughput average
+	DefaultABRFastHalfLife = 2 * time.Second
+	//DefaultABRSlowHalfLife - Half-life (download time) of the slow throughput average
+	DefaultABRSlowHalfLife = 5 * time.Second
+	//DefaultABRSafetyFactor - Fraction of estimated throughput a Representation@bandwidth can use
+	DefaultABRSafetyFactor = 0.9
+	//DefaultABRMinBytes - Downloads smaller than this are not used for throughput
+	DefaultABRMinBytes = 16000
+	//DefaultBOLAMinBuffer - Buffer level below which BOLA selects the lowest bandwidth
+	DefaultBOLAMinBuffer = 10 * time.Second
+	//DefaultBOLABufferTarget - Buffer level from which BOLA selects the highest bandwidth
+	DefaultBOLABufferTarget = 30 * time.Second
+)
+
+//ABRFeedback - Measurements reported by the application for adaptive bitrate selection
+// RepresentationSelectors implementing it use the reports in the next SelectRepresentation,
+// which is done on every MakeDASHReaderContext call
+type ABRFeedback interface {
+	//ReportDownload - Media segment (or chunk) downloaded
+	// Parameters:
+	//   1: bytes received
+	//   2: time taken from request to last byte
+	ReportDownload(bytes int64, duration time.Duration)
+	//ReportBuffer - Media buffered ahead of the playhead
+	ReportBuffer(level time.Duration)
+}
+
+//sortByBandwidth - Representations ordered by increasing @bandwidth
+func sortByBandwidth(reps []*RepresentationType) []*RepresentationType {
+	ret := make([]*RepresentationType, 0, len(reps))
+	for _, r := range reps {
+		if r != nil {
+			ret = append(ret, r)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Bandwidth < ret[j].Bandwidth
+	})
+	return ret
+}
+
+//ewma - Exponentially weighted moving average, weight is the sample duration in seconds
+type ewma struct {
+	estimate    float64 //average without zero-bias correction
+	totalWeight float64 //sum of sample weights
+}
+
+//sample - Add value with weight to the average of given half-life (seconds)
+func (e *ewma) sample(halfLife float64, weight float64, value float64) {
+	alpha := math.Pow(math.Exp(math.Log(0.5)/halfLife), weight)
+	e.estimate = value*(1-alpha) + alpha*e.estimate
+	e.totalWeight += weight
+}
+
+//value - Average corrected for the initial ZERO estimate
+func (e *ewma) value(halfLife float64) float64 {
+	zeroFactor := 1 - math.Pow(math.Exp(math.Log(0.5)/halfLife), e.totalWeight)
+	if zeroFactor <= 0 {
+		return 0
+	}
+	return e.estimate / zeroFactor
+}
+
+//EWMARepresentationSelector - Throughput based selection
+//  * Throughput of downloads averaged with a fast and slow half-life
+//  * Lower of the two averages is the estimate, drops are followed fast and rises slowly
+//  * Highest Representation@bandwidth within SafetyFactor of the estimate is selected
+//  * Lowest Representation@bandwidth till throughput is known
+type EWMARepresentationSelector struct {
+	//FastHalfLife - DefaultABRFastHalfLife if ZERO
+	FastHalfLife time.Duration
+	//SlowHalfLife - DefaultABRSlowHalfLife if ZERO
+	SlowHalfLife time.Duration
+	//SafetyFactor - DefaultABRSafetyFactor if ZERO
+	SafetyFactor float64
+	//MinBytes - DefaultABRMinBytes if ZERO
+	MinBytes int64
+	//InitialEstimate - Throughput (bits/sec) before any download, lowest bandwidth selected if ZERO
+	InitialEstimate uint
+
+	mutex sync.Mutex //gaurd fields below
+	fast  ewma       //average of FastHalfLife
+	slow  ewma       //average of SlowHalfLife
+}
+
+//halfLives - FastHalfLife and SlowHalfLife in seconds, defaults applied
+func (s *EWMARepresentationSelector) halfLives() (float64, float64) {
+	fast, slow := s.FastHalfLife, s.SlowHalfLife
+	if fast <= 0 {
+		fast = DefaultABRFastHalfLife
+	}
+	if slow <= 0 {
+		slow = DefaultABRSlowHalfLife
+	}
+	return fast.Seconds(), slow.Seconds()
+}
+
+//ReportDownload - Add the throughput of a download to the averages
+func (s *EWMARepresentationSelector) ReportDownload(bytes int64, duration time.Duration) {
+	minBytes := s.MinBytes
+	if minBytes <= 0 {
+		minBytes = DefaultABRMinBytes
+	}
+	if bytes < minBytes || duration <= 0 {
+		//Too small to measure throughput
+		return
+	}
+	fast, slow := s.halfLives()
+	weight := duration.Seconds()
+	bitsPerSec := float64(bytes*8) / weight
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.fast.sample(fast, weight, bitsPerSec)
+	s.slow.sample(slow, weight, bitsPerSec)
+}
+
+//ReportBuffer - Buffer level is not used for throughput
+func (s *EWMARepresentationSelector) ReportBuffer(level time.Duration) {
+}
+
+//Estimate - Estimated throughput in bits/sec, InitialEstimate if nothing downloaded
+func (s *EWMARepresentationSelector) Estimate() uint {
+	fast, slow := s.halfLives()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.fast.totalWeight <= 0 {
+		return s.InitialEstimate
+	}
+	return uint(math.Min(s.fast.value(fast), s.slow.value(slow)))
+}
+
+//SelectRepresentation - Highest bandwidth within the estimated throughput
+func (s *EWMARepresentationSelector) SelectRepresentation(reps []*RepresentationType) *RepresentationType {
+	sorted := sortByBandwidth(reps)
+	if len(sorted) <= 0 {
+		return nil
+	}
+	safety := s.SafetyFactor
+	if safety <= 0 {
+		safety = DefaultABRSafetyFactor
+	}
+	available := float64(s.Estimate()) * safety
+	ret := sorted[0]
+	for _, r := range sorted[1:] {
+		if float64(r.Bandwidth) > available {
+			break
+		}
+		ret = r
+	}
+	return ret
+}
+
+//BOLARepresentationSelector - Buffer based selection (BOLA)
+//  * Utility of Representation is ln(bandwidth/lowest bandwidth)
+//  * Selected Representation maximizes (V * (utility + gp) - buffer level) / bandwidth
+//  * V and gp make lowest bandwidth selected till MinBuffer, highest near BufferTarget
+//  * Lowest Representation@bandwidth till buffer level is reported
+type BOLARepresentationSelector struct {
+	//MinBuffer - DefaultBOLAMinBuffer if ZERO
+	MinBuffer time.Duration
+	//BufferTarget - DefaultBOLABufferTarget if ZERO
+	BufferTarget time.Duration
+
+	mutex  sync.Mutex    //gaurd fields below
+	buffer time.Duration //last reported buffer level
+}
+
+//ReportDownload - Throughput is not used for buffer based selection
+func (s *BOLARepresentationSelector) ReportDownload(bytes int64, duration time.Duration) {
+}
+
+//ReportBuffer - Buffer level for next selection
+func (s *BOLARepresentationSelector) ReportBuffer(level time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.buffer = level
+}
+
+//BufferLevel - Last reported buffer level
+func (s *BOLARepresentationSelector) BufferLevel() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buffer
+}
+
+//SelectRepresentation - Representation of highest BOLA score for the buffer level
+func (s *BOLARepresentationSelector) SelectRepresentation(reps []*RepresentationType) *RepresentationType {
+	sorted := sortByBandwidth(reps)
+	if len(sorted) <= 0 {
+		return nil
+	}
+	lowest := math.Max(float64(sorted[0].Bandwidth), 1)
+	highestUtility := math.Log(math.Max(float64(sorted[len(sorted)-1].Bandwidth), 1) / lowest)
+	if highestUtility <= 0 {
+		//All of same bandwidth
+		return sorted[0]
+	}
+	minBuffer, target := s.MinBuffer, s.BufferTarget
+	if minBuffer <= 0 {
+		minBuffer = DefaultBOLAMinBuffer
+	}
+	if target <= 0 {
+		target = DefaultBOLABufferTarget
+	}
+	if target <= minBuffer {
+		target = minBuffer + minBuffer
+	}
+	//Utilities shifted by 1, lowest has utility 1
+	gp := highestUtility / (target.Seconds()/minBuffer.Seconds() - 1)
+	v := minBuffer.Seconds() / gp
+	level := s.BufferLevel().Seconds()
+	ret := sorted[0]
+	bestScore := math.Inf(-1)
+	for _, r := range sorted {
+		bandwidth := math.Max(float64(r.Bandwidth), 1)
+		utility := math.Log(bandwidth/lowest) + 1
+		score := (v*(utility+gp) - level) / bandwidth
+		if score >= bestScore {
+			bestScore = score
+			ret = r
+		}
+	}
+	return ret
+}
+
+//HybridRepresentationSelector - Throughput at low buffer, BOLA once buffer is built up
+//  * Throughput (EWMA) selection till buffer level reaches SwitchOnBuffer
+//  * BOLA selection till buffer level falls below SwitchOffBuffer
+//  * Reports are passed to both, switching keeps their history
+type HybridRepresentationSelector struct {
+	//Throughput - Selector at low buffer
+	Throughput EWMARepresentationSelector
+	//Buffer - Selector once buffer is built up
+	Buffer BOLARepresentationSelector
+	//SwitchOnBuffer - Buffer level to use BOLA, Buffer.MinBuffer (or DefaultBOLAMinBuffer) if ZERO
+	SwitchOnBuffer time.Duration
+	//SwitchOffBuffer - Buffer level to use throughput again, half of SwitchOnBuffer if ZERO
+	SwitchOffBuffer time.Duration
+
+	mutex   sync.Mutex //gaurd fields below
+	useBOLA bool       //BOLA in use
+}
+
+//ReportDownload - Passed to Throughput and Buffer selectors
+func (s *HybridRepresentationSelector) ReportDownload(bytes int64, duration time.Duration) {
+	s.Throughput.ReportDownload(bytes, duration)
+	s.Buffer.ReportDownload(bytes, duration)
+}
+
+//ReportBuffer - Passed to Throughput and Buffer selectors, switches between them
+func (s *HybridRepresentationSelector) ReportBuffer(level time.Duration) {
+	s.Throughput.ReportBuffer(level)
+	s.Buffer.ReportBuffer(level)
+	on := s.SwitchOnBuffer
+	if on <= 0 {
+		on = s.Buffer.MinBuffer
+	}
+	if on <= 0 {
+		on = DefaultBOLAMinBuffer
+	}
+	off := s.SwitchOffBuffer
+	if off <= 0 {
+		off = on / 2
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.useBOLA && level < off {
+		s.useBOLA = false
+	} else if !s.useBOLA && level >= on {
+		s.useBOLA = true
+	}
+}
+
+//UsingBOLA - BOLA selection in use
+func (s *HybridRepresentationSelector) UsingBOLA() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.useBOLA
+}
+
+//SelectRepresentation - Selection of Throughput or Buffer as per buffer level
+func (s *HybridRepresentationSelector) SelectRepresentation(reps []*RepresentationType) *RepresentationType {
+	if s.UsingBOLA() {
+		return s.Buffer.SelectRepresentation(reps)
+	}
+	return s.Throughput.SelectRepresentation(reps)
+}
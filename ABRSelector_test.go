@@ -0,0 +1,236 @@
+package dashreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//idSelector - Selects Representation@id, first if not found
+type idSelector struct {
+	id string
+}
+
+func (s *idSelector) SelectRepresentation(reps []*dashreader.RepresentationType) *dashreader.RepresentationType {
+	for _, r := range reps {
+		if string(r.Id) == s.id {
+			return r
+		}
+	}
+	if len(reps) > 0 {
+		return reps[0]
+	}
+	return nil
+}
+
+//abrReps - Representations not in bandwidth order
+func abrReps() []*dashreader.RepresentationType {
+	return []*dashreader.RepresentationType{
+		{Id: "V1000", Bandwidth: 1000000},
+		{Id: "V300", Bandwidth: 300000},
+		{Id: "V3000", Bandwidth: 3000000},
+	}
+}
+
+//selectedID - Representation@id selected, empty if none
+func selectedID(s dashreader.RepresentationSelector) string {
+	rep := s.SelectRepresentation(abrReps())
+	if rep == nil {
+		return ""
+	}
+	return string(rep.Id)
+}
+
+func TestABRSelectors(t *testing.T) {
+	if id := selectedID(dashreader.MaxBWRepresentationSelector{}); id != "V3000" {
+		t.Errorf("MaxBW Exp: V3000 Act: %v", id)
+	}
+	if id := selectedID(dashreader.MinBWRepresentationSelector{}); id != "V300" {
+		t.Errorf("MinBW Exp: V300 Act: %v", id)
+	}
+
+	//Throughput
+	ewma := &dashreader.EWMARepresentationSelector{}
+	if id := selectedID(ewma); id != "V300" {
+		t.Errorf("EWMA without downloads Exp: V300 Act: %v", id)
+	}
+	for i := 0; i < 3; i++ {
+		//8 Mbps
+		ewma.ReportDownload(1000000, time.Second)
+	}
+	if est := ewma.Estimate(); est < 7900000 || est > 8100000 {
+		t.Errorf("EWMA estimate Exp: 8Mbps Act: %v", est)
+	}
+	if id := selectedID(ewma); id != "V3000" {
+		t.Errorf("EWMA 8Mbps Exp: V3000 Act: %v", id)
+	}
+	//Too small to measure
+	ewma.ReportDownload(1000, time.Millisecond)
+	if id := selectedID(ewma); id != "V3000" {
+		t.Errorf("EWMA small download Exp: V3000 Act: %v", id)
+	}
+	//Drop to 1.2 Mbps followed fast
+	ewma.ReportDownload(300000, 2*time.Second)
+	ewma.ReportDownload(300000, 2*time.Second)
+	if id := selectedID(ewma); id != "V1000" {
+		t.Errorf("EWMA drop Exp: V1000 Act: %v (estimate %v)", id, ewma.Estimate())
+	}
+
+	//Buffer
+	bola := &dashreader.BOLARepresentationSelector{}
+	testCases := []struct {
+		buffer time.Duration
+		exp    string
+	}{
+		{0, "V300"},
+		{5 * time.Second, "V300"},
+		{20 * time.Second, "V1000"},
+		{30 * time.Second, "V3000"},
+		{60 * time.Second, "V3000"},
+	}
+	for _, tc := range testCases {
+		bola.ReportBuffer(tc.buffer)
+		if id := selectedID(bola); id != tc.exp {
+			t.Errorf("BOLA buffer %v Exp: %v Act: %v", tc.buffer, tc.exp, id)
+		}
+	}
+
+	//Hybrid - 8 Mbps, BOLA from 10s till below 5s
+	hybrid := &dashreader.HybridRepresentationSelector{}
+	for i := 0; i < 3; i++ {
+		hybrid.ReportDownload(1000000, time.Second)
+	}
+	testCases = []struct {
+		buffer time.Duration
+		exp    string
+	}{
+		{2 * time.Second, "V3000"},
+		{12 * time.Second, "V300"},
+		{6 * time.Second, "V300"},
+		{4 * time.Second, "V3000"},
+	}
+	for _, tc := range testCases {
+		hybrid.ReportBuffer(tc.buffer)
+		if id := selectedID(hybrid); id != tc.exp {
+			t.Errorf("Hybrid buffer %v Exp: %v Act: %v (BOLA %v)", tc.buffer, tc.exp, id, hybrid.UsingBOLA())
+		}
+	}
+	var _ dashreader.ABRFeedback = hybrid
+}
+
+func TestABRReselect(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	seg := func(s string, d time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = d
+		return u
+	}
+	mpd, err := dashreader.ReadMPDFromFile("test/live_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(ast.Add(10 * time.Second))}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	rdr.SetStatzAgg(events)
+
+	//SegmentTimeline - Throughput raises selection, timeline position kept
+	video := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	ewma := &dashreader.EWMARepresentationSelector{}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, video, ewma)
+	if err != nil {
+		t.Fatalf("video: Error getting context : %v", err)
+	}
+	for i, exp := range []string{"http://127.0.0.1/live/V300/init.mp4", "http://127.0.0.1/live/V300/t8000.m4s"} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil || chunkURL.ChunkURL.String() != exp {
+			t.Fatalf("video: URL %v Exp: %v Act: %v %v", i, exp, chunkURL, err)
+		}
+	}
+	ewma.ReportDownload(1000000, time.Second)
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, video, ewma)
+	if err != nil {
+		t.Fatalf("video: Error getting context : %v", err)
+	}
+	checkURLs(t, "video", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/V3000/init.mp4"),
+		seg("http://127.0.0.1/live/V3000/t10000.m4s", 2*time.Second),
+		seg("http://127.0.0.1/live/V3000/t12000.m4s", 2*time.Second),
+		seg("http://127.0.0.1/live/V3000/t14000.m4s", 2*time.Second),
+		seg("http://127.0.0.1/live/V3000/t16000.m4s", 2*time.Second),
+		seg("http://127.0.0.1/live/V3000/t18000.m4s", 2*time.Second),
+	})
+
+	//SegmentTemplate@duration - $Number$ continues
+	audio := dashreader.StreamSelector{ID: "1", ContentType: "audio"}
+	sel := &idSelector{id: "A64"}
+	readCtx, err = rdr.MakeDASHReaderContext(nil, audio, sel)
+	if err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	for i, exp := range []string{"http://127.0.0.1/live/A64/init.mp4", "http://127.0.0.1/live/A64/5.m4s"} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil || chunkURL.ChunkURL.String() != exp {
+			t.Fatalf("audio: URL %v Exp: %v Act: %v %v", i, exp, chunkURL, err)
+		}
+	}
+	//Same selection, nothing repeated
+	if readCtx, err = rdr.MakeDASHReaderContext(readCtx, audio, sel); err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	sel.id = "A128"
+	if readCtx, err = rdr.MakeDASHReaderContext(readCtx, audio, sel); err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	for i, exp := range []string{"http://127.0.0.1/live/A128/init.mp4", "http://127.0.0.1/live/A128/6.m4s"} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil || chunkURL.ChunkURL.String() != exp {
+			t.Fatalf("audio: URL %v Exp: %v Act: %v %v", i, exp, chunkURL, err)
+		}
+	}
+	if evts := events.find(dashreader.EvtRepresentationSwitch); len(evts) != 2 {
+		t.Errorf("Expected 2 %v events, got %v", dashreader.EvtRepresentationSwitch, len(evts))
+	}
+}
+
+func TestABRReselectStatic(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	video := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	sel := &idSelector{id: "V300"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, video, sel)
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	for i, exp := range []string{"http://127.0.0.1/vod/V300/init.mp4", "http://127.0.0.1/vod/V300/t0.m4s", "http://127.0.0.1/vod/V300/t2000.m4s"} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil || chunkURL.ChunkURL.String() != exp {
+			t.Fatalf("URL %v Exp: %v Act: %v %v", i, exp, chunkURL, err)
+		}
+	}
+	sel.id = "V1000"
+	if readCtx, err = rdr.MakeDASHReaderContext(readCtx, video, sel); err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	seg := func(s string) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		return u
+	}
+	checkURLs(t, "static", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/vod/V1000/init.mp4"),
+		seg("http://127.0.0.1/vod/V1000/t4000.m4s"),
+		seg("http://127.0.0.1/vod/V1000/t6000.m4s"),
+	})
+}
@@ -0,0 +1,160 @@
+package dashreader
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+)
+
+const (
+	//DVBDefaultPriority - BaseURL@dvb:priority when absent
+	DVBDefaultPriority = 1
+	//DVBDefaultWeight - BaseURL@dvb:weight when absent
+	DVBDefaultWeight = 1
+)
+
+//BaseURLCandidate - One alternative location of content
+// MPD, Period, AdaptationSet and Representation BaseURLs combined
+type BaseURLCandidate struct {
+	URL             url.URL //BaseURL resolved till this level
+	ServiceLocation string  //@serviceLocation, empty if not present
+	Priority        uint    //@dvb:priority, lower value is preferred
+	Weight          uint    //@dvb:weight, share among same Priority
+}
+
+//location - Key identifying the location for failover
+// BaseURLs with the same @serviceLocation fail together
+func (b *BaseURLCandidate) location() string {
+	if len(b.ServiceLocation) > 0 {
+		return b.ServiceLocation
+	}
+	return b.URL.String()
+}
+
+//resolveBaseURLs - BaseURL set of a level from the set of its parent level
+// Relative BaseURL is resolved against every parent BaseURL and
+// inherits @serviceLocation, @dvb:priority and @dvb:weight unless present.
+// Absolute BaseURL stands alone with its own values.
+// Parameters:
+//   1: BaseURL set of parent level
+//   2: BaseURL elements of this level
+// Return:
+//   1: BaseURL set of this level, parent set if no BaseURL elements
+//   2: error
+func resolveBaseURLs(parent []BaseURLCandidate, baseURLs []BaseURLType) ([]BaseURLCandidate, error) {
+	if len(baseURLs) <= 0 {
+		return parent, nil
+	}
+	ret := make([]BaseURLCandidate, 0, len(baseURLs)*len(parent))
+	seen := make(map[string]bool)
+	add := func(b BaseURLCandidate) {
+		key := b.URL.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		ret = append(ret, b)
+	}
+	for i := range baseURLs {
+		baseURL := &baseURLs[i]
+		u, err := url.Parse(baseURL.Value)
+		if err != nil {
+			return nil, fmt.Errorf("BaseURL(%v) not correct: %w", baseURL.Value, err)
+		}
+		if u.IsAbs() {
+			add(BaseURLCandidate{
+				URL:             *u,
+				ServiceLocation: baseURL.ServiceLocation,
+				Priority:        dvbPriority(baseURL.Priority),
+				Weight:          dvbWeight(baseURL.Weight),
+			})
+			continue
+		}
+		for _, p := range parent {
+			b := p
+			b.URL = *p.URL.ResolveReference(u)
+			if len(baseURL.ServiceLocation) > 0 {
+				b.ServiceLocation = baseURL.ServiceLocation
+			}
+			if baseURL.Priority != 0 {
+				b.Priority = baseURL.Priority
+			}
+			if baseURL.Weight != 0 {
+				b.Weight = baseURL.Weight
+			}
+			add(b)
+		}
+	}
+	return ret, nil
+}
+
+//dvbPriority - @dvb:priority with default
+func dvbPriority(priority uint) uint {
+	if priority == 0 {
+		return DVBDefaultPriority
+	}
+	return priority
+}
+
+//dvbWeight - @dvb:weight with default
+func dvbWeight(weight uint) uint {
+	if weight == 0 {
+		return DVBDefaultWeight
+	}
+	return weight
+}
+
+//SelectBaseURL - Select BaseURL as per DVB-DASH (ETSI TS 103 285) 10.8.2.1
+//  * BaseURLs whose location failed are not considered
+//  * Only the first BaseURL of each @serviceLocation is considered
+//  * Lowest @dvb:priority is selected
+//  * Among the same @dvb:priority, random selection in ratio of @dvb:weight
+// Parameters:
+//   1: BaseURL set
+//   2: failed locations (@serviceLocation, BaseURL if absent), can be nil
+//   3: random source, package default if nil
+// Return:
+//   1: index of selected BaseURL, -1 if none available
+func SelectBaseURL(candidates []BaseURLCandidate, excluded map[string]bool, rnd *rand.Rand) int {
+	var eligible []int
+	var totalWeight uint
+	seen := make(map[string]bool)
+	for i := range candidates {
+		location := candidates[i].location()
+		if excluded[location] || seen[location] {
+			continue
+		}
+		seen[location] = true
+		if len(eligible) > 0 {
+			priority := candidates[eligible[0]].Priority
+			if candidates[i].Priority > priority {
+				continue
+			}
+			if candidates[i].Priority < priority {
+				eligible = eligible[:0]
+				totalWeight = 0
+			}
+		}
+		eligible = append(eligible, i)
+		totalWeight += candidates[i].Weight
+	}
+	if len(eligible) <= 0 {
+		return -1
+	}
+	if len(eligible) == 1 || totalWeight == 0 {
+		return eligible[0]
+	}
+	var pick uint
+	if rnd != nil {
+		pick = uint(rnd.Int63n(int64(totalWeight)))
+	} else {
+		pick = uint(rand.Int63n(int64(totalWeight)))
+	}
+	for _, i := range eligible {
+		if pick < candidates[i].Weight {
+			return i
+		}
+		pick -= candidates[i].Weight
+	}
+	return eligible[len(eligible)-1]
+}
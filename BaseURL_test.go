@@ -0,0 +1,128 @@
+package dashreader_test
+
+import (
+	"math/rand"
+	"net/url"
+	"testing"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestSelectBaseURL(t *testing.T) {
+	candidate := func(s string, location string, priority uint, weight uint) dashreader.BaseURLCandidate {
+		u, _ := url.Parse(s)
+		return dashreader.BaseURLCandidate{URL: *u, ServiceLocation: location, Priority: priority, Weight: weight}
+	}
+	candidates := []dashreader.BaseURLCandidate{
+		candidate("http://cdn-c.example.com/", "c", 2, 1),
+		candidate("http://cdn-a.example.com/", "a", 1, 3),
+		candidate("http://cdn-b.example.com/", "b", 1, 1),
+		candidate("http://cdn-a2.example.com/", "a", 1, 100),
+	}
+	rnd := rand.New(rand.NewSource(1))
+	count := make(map[int]int)
+	for i := 0; i < 4000; i++ {
+		count[dashreader.SelectBaseURL(candidates, nil, rnd)]++
+	}
+	//Only lowest priority, first of each serviceLocation, in ratio of weight 3:1
+	if count[0] != 0 || count[3] != 0 || count[1] < 2700 || count[1] > 3300 || count[2] < 700 || count[2] > 1300 {
+		t.Errorf("Selection not as per priority/weight : %v", count)
+	}
+	excluded := map[string]bool{"a": true}
+	if v := dashreader.SelectBaseURL(candidates, excluded, rnd); v != 2 {
+		t.Errorf("Exp: 2 Act: %v", v)
+	}
+	excluded["b"] = true
+	if v := dashreader.SelectBaseURL(candidates, excluded, rnd); v != 0 {
+		t.Errorf("Exp: 0 Act: %v", v)
+	}
+	excluded["c"] = true
+	if v := dashreader.SelectBaseURL(candidates, excluded, rnd); v != -1 {
+		t.Errorf("Exp: -1 Act: %v", v)
+	}
+}
+
+func TestBaseURLFailover(t *testing.T) {
+	file := "test/static_multicdn.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	mpdURL, _ := url.Parse("http://127.0.0.1/vod/manifest.mpd")
+	resolved, err := dashreader.ResolveMPD(mpd, *mpdURL)
+	if err != nil {
+		t.Fatalf("Error resolving : %v", err)
+	}
+	rp := resolved.Periods[0].AdaptationSets[0].Representations[0]
+	expBaseURLs := []string{
+		"http://cdn-b.example.com/content/video/",
+		"http://cdn-a.example.com/vod/video/",
+		"http://cdn-b2.example.com/content/video/",
+	}
+	if len(rp.BaseURLs) != len(expBaseURLs) {
+		t.Fatalf("BaseURLs Exp: %v Act: %v", len(expBaseURLs), len(rp.BaseURLs))
+	}
+	for i, exp := range expBaseURLs {
+		if rp.BaseURLs[i].URL.String() != exp {
+			t.Errorf("BaseURL %v Exp: %v Act: %v", i, exp, rp.BaseURLs[i].URL.String())
+		}
+	}
+	if rp.BaseURLs[1].ServiceLocation != "a" || rp.BaseURLs[1].Priority != 1 || rp.BaseURLs[1].Weight != 1 {
+		t.Errorf("BaseURL 1 attributes not inherited : %+v", rp.BaseURLs[1])
+	}
+
+	events := &eventCapture{}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", mpdURL.String(), mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	rdr.SetStatzAgg(events)
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	next := func(exp string) dashreader.ChunkURL {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("%v: error : %v", exp, err)
+		}
+		if chunkURL.ChunkURL.String() != exp {
+			t.Errorf("Exp: %v Act: %v", exp, chunkURL.ChunkURL.String())
+		}
+		return *chunkURL
+	}
+	//Lowest @dvb:priority first
+	next("http://cdn-a.example.com/vod/video/V300/init.mp4")
+	failed := next("http://cdn-a.example.com/vod/video/V300/1.m4s")
+	if err := readCtx.FetchFailed(failed); err != nil {
+		t.Fatalf("FetchFailed : %v", err)
+	}
+	if len(events.find(dashreader.EvtBaseURLFailover)) != 1 {
+		t.Errorf("Expected %v event", dashreader.EvtBaseURLFailover)
+	}
+	//Failure reported on a copy leaves the context unchanged
+	copyCtx, err := rdr.MakeDASHReaderContext(readCtx, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error copying context : %v", err)
+	}
+	copyURL, err := copyCtx.NextURL()
+	if err != nil {
+		t.Fatalf("Copy NextURL error : %v", err)
+	}
+	if err := copyCtx.FetchFailed(*copyURL); err == nil {
+		t.Errorf("Expected error when all BaseURLs of copy failed")
+	}
+	if err := readCtx.FetchFailed(failed); err != nil {
+		t.Errorf("Failures of copy seen by context : %v", err)
+	}
+	//Next location, cdn-b2 shares serviceLocation with cdn-b
+	failed = next("http://cdn-b.example.com/content/video/V300/2.m4s")
+	if err := readCtx.FetchFailed(failed); err == nil {
+		t.Errorf("Expected error when all BaseURLs failed")
+	}
+	next("http://cdn-b.example.com/content/video/V300/3.m4s")
+	if err := readCtx.FetchFailed(mustURL(t, "http://other.example.com/1.m4s")); err == nil {
+		t.Errorf("Expected error for url not from BaseURLs")
+	}
+}
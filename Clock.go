@@ -0,0 +1,66 @@
+package dashreader
+
+import (
+	"sync"
+	"time"
+)
+
+//Clock - Source of the local WallClock
+// Readers, ReaderContexts and ClockSync read the time only through Clock,
+// a FakeClock makes live timing deterministic
+type Clock interface {
+	//Now - Current local time
+	Now() time.Time
+}
+
+//SystemClock - Clock of the system (time.Now)
+type SystemClock struct{}
+
+//Now - Current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+//FakeClock - Clock that moves only when Set or Advance is called
+// Used to replay a recorded sequence of MPD updates at the recorded instants
+type FakeClock struct {
+	mutex sync.RWMutex //gaurd fields below
+	now   time.Time    //current time
+}
+
+//NewFakeClock - FakeClock starting at given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+//Now - Current time of the FakeClock
+func (f *FakeClock) Now() time.Time {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.now
+}
+
+//Set - Move the FakeClock to given time
+func (f *FakeClock) Set(now time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = now
+}
+
+//Advance - Move the FakeClock forward by given duration
+// Return:
+//   1: time after the move
+func (f *FakeClock) Advance(d time.Duration) time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+	return f.now
+}
+
+//timeNow - Current time of clock, system clock if nil
+func timeNow(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
@@ -0,0 +1,94 @@
+package dashreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestFakeClockReplay(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := dashreader.NewFakeClock(ast.Add(5 * time.Second))
+	at := func(s string, fetchAt time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.FetchAt = ast.Add(fetchAt)
+		return u
+	}
+	seg := func(s string, fetchAt time.Duration) dashreader.ChunkURL {
+		u := at(s, fetchAt)
+		u.Duration = 2 * time.Second
+		return u
+	}
+	mpd, err := dashreader.ReadMPDFromFile("test/live_seglist.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{Clock: clock}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	rdr.SetStatzAgg(events)
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	//AST+5s, segment 2 is the latest available
+	for i, exp := range []dashreader.ChunkURL{
+		at("http://127.0.0.1/live/v/init.mp4", 5*time.Second),
+		seg("http://127.0.0.1/live/v/2.m4s", 4*time.Second),
+		seg("http://127.0.0.1/live/v/3.m4s", 6*time.Second),
+	} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("URL %v error : %v", i, err)
+		}
+		if chunkURL.ChunkURL.String() != exp.ChunkURL.String() || !chunkURL.FetchAt.Equal(exp.FetchAt) {
+			t.Errorf("URL %v Exp: %v %v Act: %v %v", i, exp.ChunkURL.String(), exp.FetchAt.UTC(),
+				chunkURL.ChunkURL.String(), chunkURL.FetchAt.UTC())
+		}
+	}
+	//AST+14s, recorded update
+	clock.Set(ast.Add(14 * time.Second))
+	mpdUpd, err := dashreader.ReadMPDFromFile("test/live_seglist_upd.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	if updated, err := rdr.Update(mpdUpd); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context after update : %v", err)
+	}
+	checkURLs(t, "update", readCtx, []dashreader.ChunkURL{
+		seg("http://127.0.0.1/live/v/4.m4s", 8*time.Second),
+		seg("http://127.0.0.1/live/v/5.m4s", 10*time.Second),
+		seg("http://127.0.0.1/live/v/6.m4s", 12*time.Second),
+		seg("http://127.0.0.1/live/v/7.m4s", 14*time.Second),
+	})
+	//New context joins at the live point of the clock
+	readCtx, err = rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	checkURLs(t, "join", readCtx, []dashreader.ChunkURL{
+		at("http://127.0.0.1/live/v/init.mp4", 14*time.Second),
+		seg("http://127.0.0.1/live/v/7.m4s", 14*time.Second),
+	})
+	//Event timestamps from the clock
+	clock.Advance(time.Second)
+	if updated, _ := rdr.Update(mpd); updated {
+		t.Errorf("Older MPD accepted")
+	}
+	evts := events.find(dashreader.EvtMPDPublishTimeOld)
+	if len(evts) != 1 {
+		t.Fatalf("Expected 1 %v event, got %v", dashreader.EvtMPDPublishTimeOld, len(evts))
+	}
+	if !evts[0].EventClock.Equal(ast.Add(15 * time.Second)) {
+		t.Errorf("EventClock %v, expected %v", evts[0].EventClock.UTC(), ast.Add(15*time.Second))
+	}
+}
@@ -0,0 +1,169 @@
+package dashreader
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+//Inband events of urn:mpeg:dash:event:2012
+const (
+	MPDEventScheme        = "urn:mpeg:dash:event:2012" //DASH MPD events
+	MPDEventValidityValue = "1"                        //MPD validity expiration, message_data is new MPD@publishTime
+	MPDEventPatchValue    = "2"                        //MPD validity expiration, message_data is MPD Patch
+	MPDEventUpdateValue   = "3"                        //MPD validity expiration, message_data is the MPD
+)
+
+const (
+	emsgDurationUnknown = 0xFFFFFFFF //emsg event_duration not known
+)
+
+//InbandEvent - emsg event of a media segment
+type InbandEvent struct {
+	SchemeIdUri      string            //emsg scheme_id_uri
+	Value            string            //emsg value
+	ID               uint32            //emsg id
+	Version          uint8             //emsg version
+	PresentationTime time.Duration     //From Period start, Representation @presentationTimeOffset removed
+	Duration         time.Duration     //ZERO if unknown
+	MessageData      []byte            //emsg message_data
+	Stream           *EventStreamType  //InbandEventStream declared for the scheme
+	SCTE35           *SCTE35SpliceInfo //Cue of SCTE-35 schemes, nil if not decoded
+	MPDExpiry        bool              //urn:mpeg:dash:event:2012, MPD to be refreshed before PresentationTime
+	MPDPublishTime   time.Time         //MPD@publishTime expected after refresh, ZERO if not known
+}
+
+//InbandEvents - emsg events of a media segment of the Representation
+// Events of schemes not declared in InbandEventStream are dropped
+// Parameters:
+//   media segment bytes (emsg before moof)
+// Return:
+//   1: events in segment order
+//   2: error
+func (r *ResolvedRepresentation) InbandEvents(segment []byte) ([]InbandEvent, error) {
+	timescale, pto := r.timescale()
+	return ParseInbandEvents(segment, r.InbandEventStream, timescale, pto)
+}
+
+//timescale - @timescale and @presentationTimeOffset of effective segment information
+func (r *ResolvedRepresentation) timescale() (uint, uint64) {
+	switch r.addressing() {
+	case addressingSegmentList:
+		return r.SegmentList.Timescale, r.SegmentList.PresentationTimeOffset
+	case addressingTemplateTimeline, addressingTemplateDuration:
+		return r.SegmentTemplate.Timescale, r.SegmentTemplate.PresentationTimeOffset
+	}
+	return r.SegmentBase.Timescale, r.SegmentBase.PresentationTimeOffset
+}
+
+//segmentEmsg - emsg of a media segment with the tfdt of the moof following it
+type segmentEmsg struct {
+	*emsgBox
+	bmdt      uint64 //baseMediaDecodeTime of the moof following emsg
+	tfdtFound bool   //moof following emsg has tfdt
+}
+
+//ParseInbandEvents - emsg events of a media segment mapped to InbandEventStreams
+//  * v0 presentation_time_delta is from the baseMediaDecodeTime (tfdt) of the moof following emsg (chunks of CMAF)
+//  * v1 presentation_time is media presentation time
+//  * Scanning stops at a box extending beyond data (partial segment)
+// Parameters:
+//   1: media segment bytes
+//   2: InbandEventStreams of AdaptationSet and Representation
+//   3: Representation @timescale (tfdt, @presentationTimeOffset)
+//   4: Representation @presentationTimeOffset
+// Return:
+//   1: events in segment order, v0 emsg without tfdt left out
+//   2: error, events of the other emsg are still returned if only tfdt is missing
+func ParseInbandEvents(segment []byte, streams []EventStreamType, timescale uint, pto uint64) ([]InbandEvent, error) {
+	var boxes []segmentEmsg
+	waiting := 0 //first emsg waiting for moof
+	var offset uint64
+	for offset < uint64(len(segment)) {
+		box, err := readISOBox(segment[offset:])
+		if err != nil {
+			if offset == 0 {
+				return nil, err
+			}
+			break
+		}
+		switch box.boxType {
+		case "emsg":
+			emsg, err := parseEmsg(box)
+			if err != nil {
+				return nil, err
+			}
+			boxes = append(boxes, segmentEmsg{emsgBox: emsg})
+		case "moof":
+			bmdt, err := parseTfdt(box)
+			for ; waiting < len(boxes); waiting++ {
+				boxes[waiting].bmdt = bmdt
+				boxes[waiting].tfdtFound = err == nil
+			}
+		}
+		offset += box.size
+	}
+	var noTfdt []uint32
+	var ret []InbandEvent
+	for _, emsg := range boxes {
+		stream := findInbandEventStream(streams, emsg.schemeIdUri, emsg.value)
+		if stream == nil {
+			continue
+		}
+		var presentationTime time.Duration
+		if emsg.version == 0 {
+			if !emsg.tfdtFound {
+				noTfdt = append(noTfdt, emsg.id)
+				continue
+			}
+			presentationTime = ticksToDuration(emsg.bmdt, timescale) + ticksToDuration(uint64(emsg.presentationTimeDelta), uint(emsg.timescale))
+		} else {
+			presentationTime = ticksToDuration(emsg.presentationTime, uint(emsg.timescale))
+		}
+		evt := InbandEvent{
+			SchemeIdUri:      emsg.schemeIdUri,
+			Value:            emsg.value,
+			ID:               emsg.id,
+			Version:          emsg.version,
+			PresentationTime: presentationTime - ticksToDuration(pto, timescale),
+			MessageData:      emsg.messageData,
+			Stream:           stream,
+		}
+		if emsg.eventDuration != emsgDurationUnknown {
+			evt.Duration = ticksToDuration(uint64(emsg.eventDuration), uint(emsg.timescale))
+		}
+		if IsSCTE35Scheme(evt.SchemeIdUri) {
+			//Not decoded cue is left nil
+			evt.SCTE35, _ = ParseSCTE35(evt.MessageData)
+		}
+		if evt.SchemeIdUri == MPDEventScheme {
+			evt.MPDExpiry = true
+			if evt.Value == MPDEventValidityValue {
+				if t, err := parseUTCTimingDate(bytes.TrimRight(evt.MessageData, "\x00")); err == nil {
+					evt.MPDPublishTime = t
+				}
+			}
+		}
+		ret = append(ret, evt)
+	}
+	if len(noTfdt) > 0 {
+		return ret, fmt.Errorf("emsg(%v) v0 needs tfdt of the moof following it", noTfdt)
+	}
+	return ret, nil
+}
+
+//findInbandEventStream - InbandEventStream for scheme and value
+// InbandEventStream without @value matches all values
+func findInbandEventStream(streams []EventStreamType, schemeIdUri string, value string) *EventStreamType {
+	for i := range streams {
+		stream := &streams[i]
+		if stream.SchemeIdUri != schemeIdUri {
+			continue
+		}
+		if len(stream.Value) > 0 && stream.Value != value {
+			continue
+		}
+		return stream
+	}
+	return nil
+}
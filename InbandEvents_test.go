@@ -0,0 +1,198 @@
+package dashreader_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//isoBox - ISOBMFF box of type with payload
+func isoBox(boxType string, payload ...[]byte) []byte {
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	ret := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(ret[0:4], uint32(size))
+	copy(ret[4:8], boxType)
+	for _, p := range payload {
+		ret = append(ret, p...)
+	}
+	return ret
+}
+
+//u32 - big endian bytes of v
+func u32(v uint32) []byte {
+	ret := make([]byte, 4)
+	binary.BigEndian.PutUint32(ret, v)
+	return ret
+}
+
+//u64 - big endian bytes of v
+func u64(v uint64) []byte {
+	ret := make([]byte, 8)
+	binary.BigEndian.PutUint64(ret, v)
+	return ret
+}
+
+//cstr - null terminated string
+func cstr(s string) []byte {
+	return append([]byte(s), 0)
+}
+
+func TestInbandEvents(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/live_inband.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	mpdURL, _ := url.Parse("http://127.0.0.1/live/manifest.mpd")
+	resolved, err := dashreader.ResolveMPD(mpd, *mpdURL)
+	if err != nil {
+		t.Fatalf("Error resolving : %v", err)
+	}
+	rep := &resolved.Periods[0].AdaptationSets[0].Representations[0]
+	if len(rep.InbandEventStream) != 2 {
+		t.Fatalf("InbandEventStream Exp: 2 Act: %v", len(rep.InbandEventStream))
+	}
+	cue, _ := base64.StdEncoding.DecodeString(scte35SpliceInsert)
+	segment := append([]byte{}, isoBox("styp", []byte("msdh"), u32(0))...)
+	//v0 - 1s after tfdt
+	segment = append(segment, isoBox("emsg", u32(0), cstr(dashreader.SCTE35SchemeBin2013), cstr(""),
+		u32(90000), u32(90000), u32(180000), u32(7), cue)...)
+	//v1 - at 12s, duration unknown
+	segment = append(segment, isoBox("emsg", u32(1<<24), u32(1000), u64(12000), u32(0xFFFFFFFF), u32(8),
+		cstr(dashreader.MPDEventScheme), cstr(dashreader.MPDEventValidityValue), []byte("2020-01-01T00:00:20Z"))...)
+	//Not declared in MPD
+	segment = append(segment, isoBox("emsg", u32(0), cstr("https://aomedia.org/emsg/ID3"), cstr(""),
+		u32(1000), u32(0), u32(0), u32(9), []byte("ID3"))...)
+	noTfdt := segment
+	//tfdt v1 at 10s
+	moof := isoBox("moof", isoBox("mfhd", u32(0), u32(1)), isoBox("traf", isoBox("tfhd", u32(0), u32(1)), isoBox("tfdt", u32(1<<24), u64(10000))))
+	segment = append(append([]byte{}, segment...), moof...)
+	//mdat partially downloaded
+	mdat := isoBox("mdat", make([]byte, 100))
+	segment = append(segment, mdat[:50]...)
+
+	events, err := rep.InbandEvents(segment)
+	if err != nil {
+		t.Fatalf("Error parsing : %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Events Exp: 2 Act: %v", len(events))
+	}
+	//Presentation times from Period start, @presentationTimeOffset 2s removed
+	evt := events[0]
+	if evt.ID != 7 || evt.Version != 0 || evt.PresentationTime != 9*time.Second || evt.Duration != 2*time.Second ||
+		evt.Stream == nil || evt.Stream.SchemeIdUri != dashreader.SCTE35SchemeBin2013 || evt.MPDExpiry {
+		t.Errorf("SCTE-35 event %+v", evt)
+	}
+	if evt.SCTE35 == nil || evt.SCTE35.SpliceInsert == nil || evt.SCTE35.SpliceInsert.SpliceEventID != 0x4800008F {
+		t.Errorf("SCTE-35 cue %+v", evt.SCTE35)
+	}
+	evt = events[1]
+	if evt.ID != 8 || evt.Version != 1 || evt.PresentationTime != 10*time.Second || evt.Duration != 0 ||
+		!evt.MPDExpiry || !evt.MPDPublishTime.Equal(time.Date(2020, 1, 1, 0, 0, 20, 0, time.UTC)) {
+		t.Errorf("MPD expiry event %+v", evt)
+	}
+
+	//v0 without tfdt, v1 still returned
+	events, err = rep.InbandEvents(noTfdt)
+	if err == nil {
+		t.Errorf("Expected error for emsg v0 without tfdt")
+	}
+	if len(events) != 1 || events[0].ID != 8 {
+		t.Errorf("Events without tfdt %+v", events)
+	}
+	//Chunks of CMAF, v0 from tfdt of the moof following it
+	chunk := func(id uint32, bmdt uint64) []byte {
+		emsg := isoBox("emsg", u32(0), cstr(dashreader.SCTE35SchemeBin2013), cstr(""),
+			u32(1000), u32(500), u32(0), u32(id), cue)
+		moof := isoBox("moof", isoBox("mfhd", u32(0), u32(1)), isoBox("traf", isoBox("tfhd", u32(0), u32(1)), isoBox("tfdt", u32(1<<24), u64(bmdt))))
+		return append(append(emsg, moof...), isoBox("mdat", make([]byte, 10))...)
+	}
+	chunked := append(chunk(20, 10000), chunk(21, 12000)...)
+	events, err = rep.InbandEvents(chunked)
+	if err != nil {
+		t.Fatalf("Error parsing chunks : %v", err)
+	}
+	if len(events) != 2 || events[0].PresentationTime != 8500*time.Millisecond || events[1].PresentationTime != 10500*time.Millisecond {
+		t.Errorf("Chunk events %+v", events)
+	}
+	//moof of second chunk truncated, only its emsg fails
+	truncated := append(chunk(20, 10000), isoBox("emsg", u32(0), cstr(dashreader.SCTE35SchemeBin2013), cstr(""),
+		u32(1000), u32(500), u32(0), u32(21), cue)...)
+	truncated = append(truncated, isoBox("moof", isoBox("mfhd", u32(0), u32(2)), isoBox("traf", isoBox("tfhd", u32(0), u32(1)), isoBox("tfdt", u32(1<<24))))...)
+	events, err = rep.InbandEvents(truncated)
+	if err == nil {
+		t.Errorf("Expected error for emsg v0 with truncated tfdt")
+	}
+	if len(events) != 1 || events[0].ID != 20 || events[0].PresentationTime != 8500*time.Millisecond {
+		t.Errorf("Events with truncated tfdt %+v", events)
+	}
+	//emsg timescale ZERO
+	if _, err := rep.InbandEvents(isoBox("emsg", u32(1<<24), u32(0), u64(0), u32(0), u32(1), cstr(dashreader.MPDEventScheme), cstr("1"))); err == nil {
+		t.Errorf("Expected error for emsg timescale ZERO")
+	}
+}
+
+func TestMPDRefresherInbandExpiry(t *testing.T) {
+	server := newMPDServer(t)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	fetcher := &dashreader.MPDFetcher{URL: ts.URL + "/live/manifest.mpd", Client: ts.Client()}
+	mpd, mpdURL, err := fetcher.Fetch(context.TODO())
+	if err != nil {
+		t.Fatalf("Fetch failed %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", mpdURL.String(), mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	refresher := &dashreader.MPDRefresher{Fetcher: fetcher, Reader: rdr, StatzAgg: events}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		//Schedule far beyond the test
+		done <- refresher.Run(ctx, time.Hour)
+	}()
+	//Version 2 has PublishTime 12s
+	server.next("")
+	expiry := []dashreader.InbandEvent{{
+		SchemeIdUri:    dashreader.MPDEventScheme,
+		Value:          dashreader.MPDEventValidityValue,
+		MPDExpiry:      true,
+		MPDPublishTime: time.Date(2020, 1, 1, 0, 0, 12, 0, time.UTC),
+	}}
+	if !refresher.HandleInbandEvents(expiry) {
+		t.Fatalf("Early refresh not triggered")
+	}
+	//Triggered till the MPD with expected PublishTime is fetched
+	for refresher.HandleInbandEvents(expiry) {
+		if ctx.Err() != nil {
+			t.Fatalf("MPD not refreshed early")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if refresher.HandleInbandEvents([]dashreader.InbandEvent{{SchemeIdUri: dashreader.SCTE35SchemeBin2013}}) {
+		t.Errorf("Early refresh triggered for other scheme")
+	}
+	cancel()
+	<-done
+	if len(events.find(dashreader.EvtMPDExpired)) < 1 {
+		t.Errorf("Expected %v event", dashreader.EvtMPDExpired)
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if len(server.requests) < 2 {
+		t.Errorf("Requests Exp: atleast 2 Act: %v", server.requests)
+	}
+}
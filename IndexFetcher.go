@@ -0,0 +1,73 @@
+package dashreader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+//IndexFetcher - Fetches bytes (e.g. sidx) the readers need to build URLs
+type IndexFetcher interface {
+	//FetchRange - Fetch the bytes of a resource
+	// Parameters:
+	//   1: URL of the resource
+	//   2: byte range "first-last", empty for full resource
+	// Return:
+	//   1: bytes read
+	//   2: error
+	FetchRange(url.URL, string) ([]byte, error)
+}
+
+//HTTPIndexFetcher - IndexFetcher using HTTP Range requests
+type HTTPIndexFetcher struct {
+	//Client - http.Client to use, http.DefaultClient if nil
+	Client *http.Client
+}
+
+//FetchRange - Fetch the bytes of a resource
+// Parameters:
+//   1: URL of the resource
+//   2: byte range "first-last", empty for full resource
+// Return:
+//   1: bytes read
+//   2: error
+func (h HTTPIndexFetcher) FetchRange(u url.URL, byteRange string) ([]byte, error) {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("request for %v failed: %w", u.String(), err)
+	}
+	if len(byteRange) > 0 {
+		req.Header.Set("Range", "bytes="+byteRange)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %v (%v) failed: %w", u.String(), byteRange, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	default:
+		return nil, fmt.Errorf("fetch %v (%v) failed: %v", u.String(), byteRange, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %v (%v) failed: %w", u.String(), byteRange, err)
+	}
+	if resp.StatusCode == http.StatusOK && len(byteRange) > 0 {
+		//Server ignored the Range header
+		first, last, err := parseByteRange(byteRange)
+		if err != nil {
+			return nil, err
+		}
+		if last >= uint64(len(data)) {
+			return nil, fmt.Errorf("fetch %v (%v) returned only %v bytes", u.String(), byteRange, len(data))
+		}
+		data = data[first : last+1]
+	}
+	return data, nil
+}
@@ -0,0 +1,170 @@
+package dashreader
+
+import (
+	"fmt"
+	"time"
+)
+
+//LiveStartPolicy - Where a new live ReaderContext starts
+type LiveStartPolicy int
+
+const (
+	//LiveStartEdge - Latest segment available (default)
+	LiveStartEdge LiveStartPolicy = iota
+	//LiveStartDelay - Segment at WallClock - target latency
+	// Target latency is ContextOptions.TargetLatency,
+	// MPD@suggestedPresentationDelay if ZERO, MPD@minBufferTime if that is absent
+	LiveStartDelay
+	//LiveStartSegmentsBehind - ContextOptions.SegmentsBehind segments before the latest available
+	LiveStartSegmentsBehind
+	//LiveStartAt - Segment at ContextOptions.StartAt (DVR / start-over)
+	// Clamped to the time-shift window, SeekOutOfWindowError if outside
+	LiveStartAt
+)
+
+//SeekOutOfWindowError - Requested start is outside the time-shift window
+// ReaderContext returned with it is positioned at the nearest window edge
+type SeekOutOfWindowError struct {
+	Requested   time.Time //WallClock requested
+	WindowStart time.Time //Earliest WallClock available (now - TimeShiftBufferDepth)
+	WindowEnd   time.Time //Latest WallClock available (now)
+}
+
+//Error - error interface
+func (e *SeekOutOfWindowError) Error() string {
+	return fmt.Sprintf("Seek to %v outside window %v - %v", e.Requested.UTC(), e.WindowStart.UTC(), e.WindowEnd.UTC())
+}
+
+//ContextOptions - Options for MakeDASHReaderContext
+// Apply to live ReaderContexts, start is computed with the (synced) WallClock
+type ContextOptions struct {
+	LiveStart      LiveStartPolicy //Where a new ReaderContext starts
+	TargetLatency  time.Duration   //Latency for LiveStartDelay, from MPD if ZERO
+	SegmentsBehind uint            //Segments for LiveStartSegmentsBehind
+	StartAt        time.Time       //WallClock for LiveStartAt, AST + StartOffset if ZERO
+	StartOffset    time.Duration   //Presentation time for LiveStartAt, from MPD@availabilityStartTime
+}
+
+//ContextOption - Sets a field of ContextOptions
+type ContextOption func(*ContextOptions)
+
+//WithLiveStart - Start policy of new live ReaderContext
+func WithLiveStart(policy LiveStartPolicy) ContextOption {
+	return func(o *ContextOptions) {
+		o.LiveStart = policy
+	}
+}
+
+//WithTargetLatency - Start new live ReaderContext at WallClock - latency
+func WithTargetLatency(latency time.Duration) ContextOption {
+	return func(o *ContextOptions) {
+		o.LiveStart = LiveStartDelay
+		o.TargetLatency = latency
+	}
+}
+
+//WithSegmentsBehind - Start new live ReaderContext given segments before the latest available
+func WithSegmentsBehind(segments uint) ContextOption {
+	return func(o *ContextOptions) {
+		o.LiveStart = LiveStartSegmentsBehind
+		o.SegmentsBehind = segments
+	}
+}
+
+//WithSeek - Start new live ReaderContext at the WallClock inside time-shift window
+func WithSeek(wallClock time.Time) ContextOption {
+	return func(o *ContextOptions) {
+		o.LiveStart = LiveStartAt
+		o.StartAt = wallClock
+	}
+}
+
+//WithSeekPresentationTime - Start new live ReaderContext at presentation time
+// (from MPD@availabilityStartTime) inside time-shift window
+func WithSeekPresentationTime(offset time.Duration) ContextOption {
+	return func(o *ContextOptions) {
+		o.LiveStart = LiveStartAt
+		o.StartAt = time.Time{}
+		o.StartOffset = offset
+	}
+}
+
+//makeContextOptions - ContextOptions with options applied
+func makeContextOptions(opts []ContextOption) ContextOptions {
+	var ret ContextOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&ret)
+		}
+	}
+	return ret
+}
+
+//liveLatency - Latency of start from live edge as per options
+// Return:
+//   ZERO unless LiveStartDelay
+func (o *ContextOptions) liveLatency(curMpd *MPDtype) time.Duration {
+	if o.LiveStart != LiveStartDelay {
+		return 0
+	}
+	if o.TargetLatency > 0 {
+		return o.TargetLatency
+	}
+	if v, err := ParseDuration(curMpd.SuggestedPresentationDelay); err == nil && v > 0 {
+		return v
+	}
+	if v, err := ParseDuration(curMpd.MinBufferTime); err == nil && v > 0 {
+		return v
+	}
+	return 0
+}
+
+//liveStartTime - WallClock a new live ReaderContext starts at
+// Parameters:
+//   1: Current MPD
+//   2: WallClock now
+// Return:
+//   1: WallClock, clamped to time-shift window for LiveStartAt
+//   2: *SeekOutOfWindowError if clamped
+func (o *ContextOptions) liveStartTime(curMpd *MPDtype, now time.Time) (time.Time, error) {
+	if o.LiveStart != LiveStartAt {
+		return now.Add(-1 * o.liveLatency(curMpd)), nil
+	}
+	requested := o.StartAt
+	if requested.IsZero() {
+		requested = curMpd.AvailabilityStartTime.Add(o.StartOffset)
+	}
+	windowStart := curMpd.AvailabilityStartTime
+	if tsb, err := ParseDuration(curMpd.TimeShiftBufferDepth); err == nil && tsb > 0 && now.Add(-1*tsb).After(windowStart) {
+		windowStart = now.Add(-1 * tsb)
+	}
+	if requested.Before(windowStart) {
+		return windowStart, &SeekOutOfWindowError{Requested: requested, WindowStart: windowStart, WindowEnd: now}
+	}
+	if requested.After(now) {
+		return now, &SeekOutOfWindowError{Requested: requested, WindowStart: windowStart, WindowEnd: now}
+	}
+	return requested, nil
+}
+
+//liveStartIndex - Index of segment to start with as per options
+// Parameters:
+//   1: index of latest available segment
+//   2: index of segment at WallClock - latency (or seek time)
+// Return:
+//   index, never beyond latest or below ZERO
+func (o *ContextOptions) liveStartIndex(latest int, delayed int) int {
+	ret := latest
+	switch o.LiveStart {
+	case LiveStartDelay, LiveStartAt:
+		if delayed < ret {
+			ret = delayed
+		}
+	case LiveStartSegmentsBehind:
+		ret = latest - int(o.SegmentsBehind)
+	}
+	if ret < 0 {
+		ret = 0
+	}
+	return ret
+}
@@ -0,0 +1,122 @@
+package dashreader_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestLiveStart(t *testing.T) {
+	ast2020 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ast1970 := time.Unix(0, 0).UTC()
+	testCases := []struct {
+		name  string
+		file  string
+		spd   string
+		now   time.Time
+		opts  []dashreader.ContextOption
+		media string
+	}{
+		//SegmentList - 2s segments available at 2s, 4s ... 10s
+		{"list edge", "test/live_seglist.mpd", "", ast2020.Add(9 * time.Second), nil, "v/4.m4s"},
+		{"list latency", "test/live_seglist.mpd", "", ast2020.Add(9 * time.Second), []dashreader.ContextOption{dashreader.WithTargetLatency(5 * time.Second)}, "v/3.m4s"},
+		{"list spd", "test/live_seglist.mpd", "PT6S", ast2020.Add(9 * time.Second), []dashreader.ContextOption{dashreader.WithLiveStart(dashreader.LiveStartDelay)}, "v/2.m4s"},
+		{"list mbt", "test/live_seglist.mpd", "", ast2020.Add(9 * time.Second), []dashreader.ContextOption{dashreader.WithLiveStart(dashreader.LiveStartDelay)}, "v/4.m4s"},
+		{"list behind", "test/live_seglist.mpd", "", ast2020.Add(9 * time.Second), []dashreader.ContextOption{dashreader.WithSegmentsBehind(2)}, "v/2.m4s"},
+		{"list behind all", "test/live_seglist.mpd", "", ast2020.Add(9 * time.Second), []dashreader.ContextOption{dashreader.WithSegmentsBehind(10)}, "v/1.m4s"},
+		//SegmentTimeline - p0 has 2s segments from 0s till 10s
+		{"timeline edge", "test/live_multiperiod.mpd", "", ast2020.Add(8 * time.Second), nil, "p0/V300/t6000.m4s"},
+		{"timeline latency", "test/live_multiperiod.mpd", "", ast2020.Add(8 * time.Second), []dashreader.ContextOption{dashreader.WithTargetLatency(5 * time.Second)}, "p0/V300/t2000.m4s"},
+		{"timeline behind", "test/live_multiperiod.mpd", "", ast2020.Add(8 * time.Second), []dashreader.ContextOption{dashreader.WithSegmentsBehind(1)}, "p0/V300/t4000.m4s"},
+		//SegmentTemplate@duration - 2s segments from Number 0
+		{"number edge", "test/live_noManupd.mpd", "", ast1970.Add(100 * time.Second), nil, "V300/49.m4s"},
+		{"number latency", "test/live_noManupd.mpd", "", ast1970.Add(100 * time.Second), []dashreader.ContextOption{dashreader.WithTargetLatency(6 * time.Second)}, "V300/47.m4s"},
+		{"number spd", "test/live_noManupd.mpd", "PT10S", ast1970.Add(100 * time.Second), []dashreader.ContextOption{dashreader.WithLiveStart(dashreader.LiveStartDelay)}, "V300/45.m4s"},
+		{"number behind", "test/live_noManupd.mpd", "", ast1970.Add(100 * time.Second), []dashreader.ContextOption{dashreader.WithSegmentsBehind(3)}, "V300/46.m4s"},
+	}
+	for _, tc := range testCases {
+		mpd, err := dashreader.ReadMPDFromFile(tc.file)
+		if err != nil {
+			t.Fatalf("%v: Error reading : %v", tc.name, err)
+		}
+		mpd.SuggestedPresentationDelay = tc.spd
+		factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(tc.now)}
+		rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+		if err != nil {
+			t.Fatalf("%v: Error getting reader : %v", tc.name, err)
+		}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{}, tc.opts...)
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", tc.name, err)
+		}
+		if _, err := readCtx.NextURL(); err != nil {
+			t.Fatalf("%v: Error getting init : %v", tc.name, err)
+		}
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("%v: Error getting URL : %v", tc.name, err)
+		}
+		if !strings.HasSuffix(chunkURL.ChunkURL.String(), "/"+tc.media) {
+			t.Errorf("%v: Exp: %v Act: %v", tc.name, tc.media, chunkURL.ChunkURL.String())
+		}
+	}
+}
+
+func TestLiveSeek(t *testing.T) {
+	ast2020 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ast1970 := time.Unix(0, 0).UTC()
+	testCases := []struct {
+		name      string
+		file      string
+		now       time.Time
+		opt       dashreader.ContextOption
+		media     []string
+		outWindow bool
+	}{
+		//SegmentTemplate@duration - 2s segments from Number 0, TSB 5 minutes
+		{"number", "test/live_noManupd.mpd", ast1970.Add(1000 * time.Second), dashreader.WithSeek(ast1970.Add(801 * time.Second)), []string{"V300/400.m4s", "V300/401.m4s"}, false},
+		{"number presentation", "test/live_noManupd.mpd", ast1970.Add(1000 * time.Second), dashreader.WithSeekPresentationTime(800 * time.Second), []string{"V300/400.m4s", "V300/401.m4s"}, false},
+		{"number before window", "test/live_noManupd.mpd", ast1970.Add(1000 * time.Second), dashreader.WithSeek(ast1970.Add(600 * time.Second)), []string{"V300/350.m4s", "V300/351.m4s"}, true},
+		{"number after window", "test/live_noManupd.mpd", ast1970.Add(1000 * time.Second), dashreader.WithSeek(ast1970.Add(2000 * time.Second)), []string{"V300/499.m4s"}, true},
+		//SegmentTimeline - p0 has 2s segments from 0s till 10s
+		{"timeline", "test/live_multiperiod.mpd", ast2020.Add(8 * time.Second), dashreader.WithSeek(ast2020.Add(3 * time.Second)), []string{"p0/V300/t2000.m4s", "p0/V300/t4000.m4s"}, false},
+		{"timeline after window", "test/live_multiperiod.mpd", ast2020.Add(8 * time.Second), dashreader.WithSeekPresentationTime(time.Minute), []string{"p0/V300/t6000.m4s"}, true},
+		//SegmentList - 2s segments available at 2s, 4s ... 10s
+		{"list", "test/live_seglist.mpd", ast2020.Add(9 * time.Second), dashreader.WithSeek(ast2020.Add(5 * time.Second)), []string{"v/3.m4s", "v/4.m4s"}, false},
+		{"list before window", "test/live_seglist.mpd", ast2020.Add(9 * time.Second), dashreader.WithSeek(ast2020.Add(-5 * time.Second)), []string{"v/1.m4s", "v/2.m4s"}, true},
+	}
+	for _, tc := range testCases {
+		mpd, err := dashreader.ReadMPDFromFile(tc.file)
+		if err != nil {
+			t.Fatalf("%v: Error reading : %v", tc.name, err)
+		}
+		factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(tc.now)}
+		rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+		if err != nil {
+			t.Fatalf("%v: Error getting reader : %v", tc.name, err)
+		}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{}, tc.opt)
+		var windowErr *dashreader.SeekOutOfWindowError
+		if tc.outWindow != errors.As(err, &windowErr) {
+			t.Errorf("%v: SeekOutOfWindowError expected %v got %v", tc.name, tc.outWindow, err)
+		}
+		if err != nil && windowErr == nil {
+			t.Fatalf("%v: Error getting context : %v", tc.name, err)
+		}
+		if _, err := readCtx.NextURL(); err != nil {
+			t.Fatalf("%v: Error getting init : %v", tc.name, err)
+		}
+		for i, media := range tc.media {
+			chunkURL, err := readCtx.NextURL()
+			if err != nil {
+				t.Fatalf("%v: Error getting URL %v : %v", tc.name, i, err)
+			}
+			if !strings.HasSuffix(chunkURL.ChunkURL.String(), "/"+media) {
+				t.Errorf("%v: URL %v Exp: %v Act: %v", tc.name, i, media, chunkURL.ChunkURL.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package dashreader_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestLowLatency(t *testing.T) {
+	ast2020 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ast1970 := time.Unix(0, 0).UTC()
+	llTimeline := func(doc string) string {
+		return strings.Replace(doc, "<SegmentTemplate ", `<SegmentTemplate availabilityTimeComplete="false" availabilityTimeOffset="1.5" `, 1)
+	}
+	type expURL struct {
+		media      string
+		fetchAt    time.Duration
+		completeAt time.Duration
+		chunked    bool
+	}
+	testCases := []struct {
+		name   string
+		file   string
+		modify func(string) string
+		now    time.Time
+		opts   []dashreader.ContextOption
+		exp    []expURL
+	}{
+		//8s segments, ATO 7s, chunks of first segment available at 97s
+		{"number edge", "test/live_ChunkedLLLowRate.mpd", nil, ast1970.Add(100500 * time.Millisecond), nil, []expURL{
+			{"V300/12.m4s", 97 * time.Second, 104 * time.Second, true},
+			{"V300/13.m4s", 105 * time.Second, 112 * time.Second, true},
+		}},
+		{"number behind", "test/live_ChunkedLLLowRate.mpd", nil, ast1970.Add(100500 * time.Millisecond), []dashreader.ContextOption{dashreader.WithSegmentsBehind(2)}, []expURL{
+			{"V300/10.m4s", 81 * time.Second, 88 * time.Second, false},
+			{"V300/11.m4s", 89 * time.Second, 96 * time.Second, false},
+			{"V300/12.m4s", 97 * time.Second, 104 * time.Second, true},
+		}},
+		//Not low latency, complete when available
+		{"number", "test/live_noManupd.mpd", nil, ast1970.Add(100 * time.Second), nil, []expURL{
+			{"V300/49.m4s", 100 * time.Second, 100 * time.Second, false},
+		}},
+		//2s segments, ATO 1.5s, segment at 8s has first chunk at 8.5s
+		{"timeline edge", "test/live_multiperiod.mpd", llTimeline, ast2020.Add(8600 * time.Millisecond), nil, []expURL{
+			{"p0/V300/t8000.m4s", 8500 * time.Millisecond, 10 * time.Second, true},
+		}},
+		{"timeline behind", "test/live_multiperiod.mpd", llTimeline, ast2020.Add(8600 * time.Millisecond), []dashreader.ContextOption{dashreader.WithSegmentsBehind(1)}, []expURL{
+			{"p0/V300/t6000.m4s", 6500 * time.Millisecond, 8 * time.Second, false},
+			{"p0/V300/t8000.m4s", 8500 * time.Millisecond, 10 * time.Second, true},
+		}},
+	}
+	for _, tc := range testCases {
+		data, err := os.ReadFile(tc.file)
+		if err != nil {
+			t.Fatalf("%v: Error reading : %v", tc.name, err)
+		}
+		doc := string(data)
+		if tc.modify != nil {
+			doc = tc.modify(doc)
+		}
+		mpd, err := dashreader.ReadMPDFromStream(strings.NewReader(doc))
+		if err != nil {
+			t.Fatalf("%v: Error decoding : %v", tc.name, err)
+		}
+		ast := mpd.AvailabilityStartTime
+		factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(tc.now)}
+		rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+		if err != nil {
+			t.Fatalf("%v: Error getting reader : %v", tc.name, err)
+		}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{}, tc.opts...)
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", tc.name, err)
+		}
+		if _, err := readCtx.NextURL(); err != nil {
+			t.Fatalf("%v: Error getting init : %v", tc.name, err)
+		}
+		for i, exp := range tc.exp {
+			chunkURL, err := readCtx.NextURL()
+			if err != nil {
+				t.Fatalf("%v: Error getting URL %v : %v", tc.name, i, err)
+			}
+			if !strings.HasSuffix(chunkURL.ChunkURL.String(), "/"+exp.media) {
+				t.Errorf("%v: URL %v Exp: %v Act: %v", tc.name, i, exp.media, chunkURL.ChunkURL.String())
+			}
+			if !chunkURL.FetchAt.Equal(ast.Add(exp.fetchAt)) || !chunkURL.CompleteAt.Equal(ast.Add(exp.completeAt)) || chunkURL.Chunked != exp.chunked {
+				t.Errorf("%v: URL %v Exp: %v %v %v Act: %v %v %v", tc.name, i,
+					ast.Add(exp.fetchAt).UTC(), ast.Add(exp.completeAt).UTC(), exp.chunked,
+					chunkURL.FetchAt.UTC(), chunkURL.CompleteAt.UTC(), chunkURL.Chunked)
+			}
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package dashreader
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eswarantg/statzagg"
+)
+
+const (
+	//DefaultEventMaxWait - Longest wait of EventDispatcher.Run between checks of the Clock
+	DefaultEventMaxWait = time.Second
+)
+
+//MPDEvent - Event of a Period EventStream with WallClock timing
+type MPDEvent struct {
+	SchemeIdUri      string            //EventStream@schemeIdUri
+	Value            string            //EventStream@value
+	ID               uint              //Event@id
+	PeriodID         string            //Period@id of the EventStream
+	PresentationTime time.Duration     //Event@presentationTime - EventStream@presentationTimeOffset, from Period start
+	Duration         time.Duration     //Event@duration, ZERO if absent
+	Start            time.Time         //WallClock event starts
+	End              time.Time         //WallClock event ends, Start if no duration
+	MessageData      string            //Event@messageData
+	Event            *EventType        //Event of the MPD
+	SCTE35           *SCTE35SpliceInfo //Cue of SCTE-35 schemes, nil if not decoded
+}
+
+//EventSubscriber - Receives MPDEvents from EventDispatcher
+// Callbacks are made from EventDispatcher.Poll, never with locks held
+type EventSubscriber interface {
+	//EventStart - WallClock reached MPDEvent.Start
+	EventStart(MPDEvent)
+	//EventEnd - WallClock reached MPDEvent.End, always after EventStart
+	EventEnd(MPDEvent)
+}
+
+//mpdEventKey - Events with same key are one event across MPD updates
+type mpdEventKey struct {
+	periodID    string
+	schemeIdUri string
+	value       string
+	id          uint
+}
+
+//mpdEventState - Event known to EventDispatcher
+type mpdEventState struct {
+	event   MPDEvent
+	started bool //EventStart delivered (or skipped)
+	ended   bool //EventEnd delivered (or skipped)
+}
+
+//eventSubscription - Subscriber and its EventStream@schemeIdUri
+type eventSubscription struct {
+	schemeIdUri string //ALL schemes if empty
+	subscriber  EventSubscriber
+}
+
+//EventDispatcher - Delivers Period EventStream events of the MPDs to subscribers
+//  * Events read from every MPD the Reader is updated with (ReaderFactory.EventDispatcher)
+//  * Same Period@id, EventStream@schemeIdUri, @value and Event@id is one event across updates
+//  * EventStart / EventEnd when the (synced) WallClock reaches Start / End
+//  * SCTE-35 cues decoded once, failures posted as events
+//  * Events that ended before they were first seen are not delivered
+type EventDispatcher struct {
+	//ID - ID for the events
+	ID string
+	//StatzAgg - Statz Agg for events
+	StatzAgg statzagg.StatzAgg
+	//Clock - Local clock, ReaderFactory.Clock if nil when the Reader is made
+	Clock Clock
+	//ClockSync - Server clock, ReaderFactory.ClockSync if nil when the Reader is made
+	ClockSync *ClockSync
+	//MaxWait - Longest wait of Run between checks, DefaultEventMaxWait if ZERO
+	MaxWait time.Duration
+
+	mutex       sync.Mutex                     //gaurd fields below
+	subscribers []eventSubscription            //Subscribers in order of Subscribe
+	events      map[mpdEventKey]*mpdEventState //Events known
+	wake        chan struct{}                  //Wakes Run on update
+}
+
+//Subscribe - Add subscriber for the events of EventStream@schemeIdUri
+// Parameters:
+//   1: EventStream@schemeIdUri, events of all schemes if empty
+//   2: subscriber
+func (d *EventDispatcher) Subscribe(schemeIdUri string, subscriber EventSubscriber) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.subscribers = append(d.subscribers, eventSubscription{schemeIdUri: schemeIdUri, subscriber: subscriber})
+}
+
+//Events - Events known, ordered by Start
+func (d *EventDispatcher) Events() []MPDEvent {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	ret := make([]MPDEvent, 0, len(d.events))
+	for _, state := range d.events {
+		ret = append(ret, state.event)
+	}
+	sortMPDEvents(ret)
+	return ret
+}
+
+//Poll - Deliver events whose Start or End is reached
+// Return:
+//   1: WallClock of next Start or End, ZERO if none pending
+func (d *EventDispatcher) Poll() time.Time {
+	type delivery struct {
+		event MPDEvent
+		start bool
+	}
+	now := d.now()
+	var deliveries []delivery
+	var next time.Time
+	d.mutex.Lock()
+	subscribers := d.subscribers
+	for _, state := range d.events {
+		if !state.started {
+			if state.event.Start.After(now) {
+				next = earliest(next, state.event.Start)
+				continue
+			}
+			state.started = true
+			deliveries = append(deliveries, delivery{event: state.event, start: true})
+		}
+		if !state.ended {
+			if state.event.End.After(now) {
+				next = earliest(next, state.event.End)
+				continue
+			}
+			state.ended = true
+			deliveries = append(deliveries, delivery{event: state.event, start: false})
+		}
+	}
+	d.mutex.Unlock()
+	//Starts before ends, in WallClock order
+	sort.Slice(deliveries, func(i, j int) bool {
+		ti, tj := deliveries[i].event.End, deliveries[j].event.End
+		if deliveries[i].start {
+			ti = deliveries[i].event.Start
+		}
+		if deliveries[j].start {
+			tj = deliveries[j].event.Start
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		if deliveries[i].start != deliveries[j].start {
+			return deliveries[i].start
+		}
+		return deliveries[i].event.ID < deliveries[j].event.ID
+	})
+	for _, dlv := range deliveries {
+		name := EvtMPDEventEnd
+		if dlv.start {
+			name = EvtMPDEventStart
+		}
+		d.postEvent(name, dlv.event.SchemeIdUri, dlv.event.ID, dlv.event.Start)
+		for _, sub := range subscribers {
+			if len(sub.schemeIdUri) > 0 && sub.schemeIdUri != dlv.event.SchemeIdUri {
+				continue
+			}
+			if dlv.start {
+				sub.subscriber.EventStart(dlv.event)
+			} else {
+				sub.subscriber.EventEnd(dlv.event)
+			}
+		}
+	}
+	return next
+}
+
+//Run - Poll on time till context is done
+// Waits on system timers for the next Start or End as per Clock,
+// atmost MaxWait so that a FakeClock or ClockSync change is seen
+// Return:
+//   1: context error
+func (d *EventDispatcher) Run(ctx context.Context) error {
+	maxWait := d.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultEventMaxWait
+	}
+	wake := d.wakeChan()
+	for {
+		next := d.Poll()
+		wait := maxWait
+		if !next.IsZero() {
+			if w := next.Sub(d.now()); w < wait {
+				wait = w
+			}
+		}
+		if wait < 0 {
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+//update - Add the events of the MPD not known already
+// Events ended and absent in the MPD are forgotten
+// Parameters:
+//   1: MPD the Reader is updated with
+//   2: WallClock timing of the Periods of the MPD
+func (d *EventDispatcher) update(mpd *MPDtype, timings []periodTiming) {
+	now := d.now()
+	added := 0
+	var failed []MPDEvent
+	var errs []error
+	d.mutex.Lock()
+	if d.events == nil {
+		d.events = make(map[mpdEventKey]*mpdEventState)
+	}
+	present := make(map[mpdEventKey]bool)
+	for _, timing := range timings {
+		for _, event := range makeMPDEvents(&mpd.Period[timing.index], timing.start) {
+			key := mpdEventKey{periodID: event.PeriodID, schemeIdUri: event.SchemeIdUri, value: event.Value, id: event.ID}
+			present[key] = true
+			if _, ok := d.events[key]; ok {
+				continue
+			}
+			if IsSCTE35Scheme(event.SchemeIdUri) {
+				cue, err := DecodeSCTE35Event(event.Event)
+				if err != nil {
+					failed = append(failed, event)
+					errs = append(errs, err)
+				}
+				event.SCTE35 = cue
+			}
+			state := &mpdEventState{event: event}
+			if event.End.Before(now) {
+				//Missed, kept only to ignore it in later updates
+				state.started = true
+				state.ended = true
+			}
+			d.events[key] = state
+			added++
+		}
+	}
+	for key, state := range d.events {
+		if state.ended && !present[key] {
+			delete(d.events, key)
+		}
+	}
+	wake := d.wake
+	d.mutex.Unlock()
+	for i, event := range failed {
+		d.postEvent(EvtSCTE35DecodeFailed, event.SchemeIdUri, event.ID, errs[i].Error())
+	}
+	if added > 0 && wake != nil {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+//wakeChan - Channel Run waits on for updates
+func (d *EventDispatcher) wakeChan() chan struct{} {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.wake == nil {
+		d.wake = make(chan struct{}, 1)
+	}
+	return d.wake
+}
+
+//now - Current WallClock as per server clock
+func (d *EventDispatcher) now() time.Time {
+	offset, _ := d.ClockSync.Offset()
+	return timeNow(d.Clock).Add(offset)
+}
+
+//postEvent - Post dispatcher event
+func (d *EventDispatcher) postEvent(name string, values ...interface{}) {
+	if d.StatzAgg == nil {
+		return
+	}
+	d.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+		EventClock: timeNow(d.Clock),
+		ID:         d.ID,
+		Name:       name,
+		Values:     values,
+	})
+}
+
+//makeMPDEvents - Events of the EventStreams of Period
+// Parameters:
+//   1: Period
+//   2: WallClock start of Period
+// Return:
+//   Events in MPD order
+func makeMPDEvents(period *PeriodType, periodStart time.Time) []MPDEvent {
+	var ret []MPDEvent
+	for i := range period.EventStream {
+		stream := &period.EventStream[i]
+		for j := range stream.Event {
+			event := &stream.Event[j]
+			//@presentationTime before @presentationTimeOffset is before Period start
+			presentationTime := ticksToDuration(event.PresentationTime, stream.Timescale) - ticksToDuration(stream.PresentationTimeOffset, stream.Timescale)
+			duration := ticksToDuration(event.Duration, stream.Timescale)
+			start := periodStart.Add(presentationTime)
+			ret = append(ret, MPDEvent{
+				SchemeIdUri:      stream.SchemeIdUri,
+				Value:            stream.Value,
+				ID:               event.Id,
+				PeriodID:         period.Id,
+				PresentationTime: presentationTime,
+				Duration:         duration,
+				Start:            start,
+				End:              start.Add(duration),
+				MessageData:      event.MessageData,
+				Event:            event,
+			})
+		}
+	}
+	return ret
+}
+
+//earliest - Earlier of the times, ZERO time is ignored
+func earliest(a time.Time, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+//sortMPDEvents - Order events by Start, then scheme and id
+func sortMPDEvents(events []MPDEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		if events[i].SchemeIdUri != events[j].SchemeIdUri {
+			return events[i].SchemeIdUri < events[j].SchemeIdUri
+		}
+		return events[i].ID < events[j].ID
+	})
+}
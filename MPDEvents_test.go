@@ -0,0 +1,130 @@
+package dashreader_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//eventRecorder - Records EventStart/EventEnd as "start:scheme:id@offset"
+type eventRecorder struct {
+	ast      time.Time
+	received []string
+}
+
+func (r *eventRecorder) EventStart(evt dashreader.MPDEvent) {
+	r.received = append(r.received, fmt.Sprintf("start:%v:%v@%v", evt.SchemeIdUri, evt.ID, evt.Start.Sub(r.ast)))
+}
+
+func (r *eventRecorder) EventEnd(evt dashreader.MPDEvent) {
+	r.received = append(r.received, fmt.Sprintf("end:%v:%v@%v", evt.SchemeIdUri, evt.ID, evt.End.Sub(r.ast)))
+}
+
+//take - Recorded so far, cleared
+func (r *eventRecorder) take() string {
+	ret := strings.Join(r.received, ",")
+	r.received = nil
+	return ret
+}
+
+func TestMPDEvents(t *testing.T) {
+	const scte = "urn:scte:scte35:2013:xml"
+	const blackout = "urn:example:blackout"
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mpd, err := dashreader.ReadMPDFromFile("test/live_events.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	if mpd.Period[0].EventStream[0].PresentationTimeOffset != 1000 {
+		t.Fatalf("EventStream@presentationTimeOffset Exp: 1000 Act: %v", mpd.Period[0].EventStream[0].PresentationTimeOffset)
+	}
+	clock := dashreader.NewFakeClock(ast.Add(10 * time.Second))
+	statz := &eventCapture{}
+	dispatcher := &dashreader.EventDispatcher{StatzAgg: statz}
+	all := &eventRecorder{ast: ast}
+	blackouts := &eventRecorder{ast: ast}
+	dispatcher.Subscribe("", all)
+	dispatcher.Subscribe(blackout, blackouts)
+	factory := dashreader.ReaderFactory{Clock: clock, EventDispatcher: dispatcher}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	if dispatcher.Clock != clock {
+		t.Errorf("EventDispatcher.Clock not taken from ReaderFactory")
+	}
+	events := dispatcher.Events()
+	if len(events) != 4 {
+		t.Fatalf("Events Exp: 4 Act: %v", len(events))
+	}
+	//Sorted by Start, timing from Period start and presentationTimeOffset
+	evt := events[1]
+	if evt.SchemeIdUri != scte || evt.ID != 2 || evt.PeriodID != "p0" || evt.MessageData != "ad2" ||
+		evt.PresentationTime != 10*time.Second || evt.Duration != 4*time.Second ||
+		!evt.Start.Equal(ast.Add(10*time.Second)) || !evt.End.Equal(ast.Add(14*time.Second)) {
+		t.Errorf("Event %+v", evt)
+	}
+
+	//Event 1 ended before the MPD was seen
+	if next := dispatcher.Poll(); !next.Equal(ast.Add(12 * time.Second)) {
+		t.Errorf("Next Exp: 12s Act: %v", next.Sub(ast))
+	}
+	if act, exp := all.take(), "start:"+scte+":2@10s"; act != exp {
+		t.Errorf("10s Exp: %v Act: %v", exp, act)
+	}
+	//Event without duration starts and ends together
+	clock.Set(ast.Add(12 * time.Second))
+	dispatcher.Poll()
+	if act, exp := all.take(), "start:"+scte+":3@12s,end:"+scte+":3@12s"; act != exp {
+		t.Errorf("12s Exp: %v Act: %v", exp, act)
+	}
+
+	//Update repeats known events, changed event 2 ignored, event 4 added
+	clock.Set(ast.Add(13 * time.Second))
+	mpd2, err := dashreader.ReadMPDFromFile("test/live_events.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	mpd2.PublishTime = ast.Add(13 * time.Second)
+	stream := &mpd2.Period[0].EventStream[0]
+	stream.Event[1].Duration = 1000
+	stream.Event = append(stream.Event, dashreader.EventType{Id: 4, PresentationTime: 19000, Duration: 1000})
+	if updated, err := rdr.Update(mpd2); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	if len(dispatcher.Events()) != 5 {
+		t.Errorf("Events after update Exp: 5 Act: %v", len(dispatcher.Events()))
+	}
+	dispatcher.Poll()
+	if act := all.take(); act != "" {
+		t.Errorf("13s Exp: none Act: %v", act)
+	}
+	clock.Set(ast.Add(14 * time.Second))
+	dispatcher.Poll()
+	if act, exp := all.take(), "end:"+scte+":2@14s"; act != exp {
+		t.Errorf("14s Exp: %v Act: %v", exp, act)
+	}
+	clock.Set(ast.Add(20 * time.Second))
+	if next := dispatcher.Poll(); !next.IsZero() {
+		t.Errorf("Next Exp: none Act: %v", next.Sub(ast))
+	}
+	exp := strings.Join([]string{
+		"start:" + blackout + ":1@16s",
+		"start:" + scte + ":4@18s",
+		"end:" + scte + ":4@19s",
+		"end:" + blackout + ":1@20s",
+	}, ",")
+	if act := all.take(); act != exp {
+		t.Errorf("20s Exp: %v Act: %v", exp, act)
+	}
+	exp = "start:" + blackout + ":1@16s,end:" + blackout + ":1@20s"
+	if act := blackouts.take(); act != exp {
+		t.Errorf("Scheme subscriber Exp: %v Act: %v", exp, act)
+	}
+	if len(statz.find(dashreader.EvtMPDEventStart)) != 4 || len(statz.find(dashreader.EvtMPDEventEnd)) != 4 {
+		t.Errorf("Expected 4 %v and %v events", dashreader.EvtMPDEventStart, dashreader.EvtMPDEventEnd)
+	}
+}
@@ -0,0 +1,504 @@
+package dashreader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eswarantg/statzagg"
+)
+
+const (
+	//DefaultRefreshMinBackoff - First retry delay after MPD fetch failure
+	DefaultRefreshMinBackoff = 500 * time.Millisecond
+	//DefaultRefreshMaxBackoff - Retry delay after repeated MPD fetch failures
+	DefaultRefreshMaxBackoff = 30 * time.Second
+	//DefaultRefreshMinInterval - Refresh interval when MPD@minimumUpdatePeriod is ZERO
+	DefaultRefreshMinInterval = 500 * time.Millisecond
+	//DefaultRefreshMaxWait - Longest wait of MPDRefresher.Run between checks of the Clock
+	DefaultRefreshMaxWait = time.Second
+)
+
+//MPDFetcher - Fetches MPD over HTTP
+//  * Conditional GET with ETag / Last-Modified of previous response
+//  * HTTP redirects followed, MPD url is the final url
+//  * MPD.Location used for the following fetches
+//  * MPD.PatchLocation used instead of the full MPD while valid (ttl),
+//    full MPD fetched if the MPD Patch fails
+type MPDFetcher struct {
+	//URL - url of the MPD to start with
+	URL string
+	//Client - http.Client to use, http.DefaultClient if nil
+	Client *http.Client
+	//NoPatch - Always fetch the full MPD, MPD.PatchLocation ignored
+	NoPatch bool
+	//ID - ID for the events
+	ID string
+	//StatzAgg - Statz Agg for events
+	StatzAgg statzagg.StatzAgg
+	//Clock - Clock for PatchLocation@ttl and event timestamps, MPDRefresher.Clock if nil when run by it
+	Clock Clock
+
+	mutex        sync.Mutex //gaurd fields below
+	curURL       *url.URL   //url for next fetch
+	etag         string     //ETag of last response
+	lastModified string     //Last-Modified of last response
+	last         *MPDtype   //copy of last MPD, PatchLocation and @publishTime
+	lastDoc      []byte     //document of last MPD, base of MPD Patch
+	lastURL      url.URL    //url last MPD was read from
+}
+
+//GetURL - url the next fetch uses
+func (f *MPDFetcher) GetURL() (url.URL, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.getURL()
+}
+
+//getURL - url the next fetch uses, mutex to be held
+func (f *MPDFetcher) getURL() (url.URL, error) {
+	if f.curURL == nil {
+		u, err := url.Parse(f.URL)
+		if err != nil {
+			return url.URL{}, fmt.Errorf("MPDFetcher URL(%v) not correct: %w", f.URL, err)
+		}
+		f.curURL = u
+	}
+	return *f.curURL, nil
+}
+
+//Fetch - Fetch the MPD
+// Parameters:
+//   context for cancellation
+// Return:
+//   1: MPD read, nil if not modified since last fetch
+//   2: url the MPD was read from (after redirects)
+//   3: error
+func (f *MPDFetcher) Fetch(ctx context.Context) (*MPDtype, url.URL, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if patchURL, ok := f.patchURL(); ok {
+		mpd, err := f.fetchPatch(ctx, patchURL)
+		if err == nil {
+			if mpd != nil {
+				f.postEvent(EvtMPDPatchApplied, patchURL.String(), mpd.PublishTime)
+			}
+			return mpd, f.lastURL, nil
+		}
+		if ctx.Err() != nil {
+			return nil, f.lastURL, err
+		}
+		f.postEvent(EvtMPDPatchFailed, patchURL.String(), err.Error())
+	}
+	return f.fetchFull(ctx)
+}
+
+//fetchFull - Fetch the full MPD, mutex to be held
+func (f *MPDFetcher) fetchFull(ctx context.Context) (*MPDtype, url.URL, error) {
+	reqURL, err := f.getURL()
+	if err != nil {
+		return nil, reqURL, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), nil)
+	if err != nil {
+		return nil, reqURL, fmt.Errorf("request for %v failed: %w", reqURL.String(), err)
+	}
+	if len(f.etag) > 0 {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	if len(f.lastModified) > 0 {
+		req.Header.Set("If-Modified-Since", f.lastModified)
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, reqURL, fmt.Errorf("fetch %v failed: %w", reqURL.String(), err)
+	}
+	defer resp.Body.Close()
+	finalURL := *resp.Request.URL
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, finalURL, nil
+	default:
+		return nil, finalURL, fmt.Errorf("fetch %v failed: %v", reqURL.String(), resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, finalURL, fmt.Errorf("reading %v failed: %w", finalURL.String(), err)
+	}
+	mpd, err := ReadMPDFromStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, finalURL, fmt.Errorf("reading %v failed: %w", finalURL.String(), err)
+	}
+	nextURL, err := locationURL(mpd, finalURL)
+	if err != nil {
+		return nil, finalURL, err
+	}
+	if nextURL.String() != reqURL.String() {
+		//Validators belong to the old url
+		f.etag = ""
+		f.lastModified = ""
+	}
+	if nextURL.String() == finalURL.String() {
+		f.etag = resp.Header.Get("ETag")
+		f.lastModified = resp.Header.Get("Last-Modified")
+	}
+	f.curURL = &nextURL
+	f.keep(data, mpd, finalURL)
+	return mpd, finalURL, nil
+}
+
+//locationURL - url for next fetch, MPD.Location resolved with url MPD was read from
+func locationURL(mpd *MPDtype, mpdURL url.URL) (url.URL, error) {
+	if len(mpd.Location) <= 0 {
+		return mpdURL, nil
+	}
+	loc, err := url.Parse(mpd.Location[0])
+	if err != nil {
+		return mpdURL, fmt.Errorf("MPD.Location(%v) not correct: %w", mpd.Location[0], err)
+	}
+	return *mpdURL.ResolveReference(loc), nil
+}
+
+//keep - Keep a copy of MPD as base of the next MPD Patch, mutex to be held
+// MPD returned is modified by Reader (XLink), the copy is not
+// Parameters:
+//   1: document of the MPD
+//   2: MPD read from the document
+//   3: url MPD was read from
+func (f *MPDFetcher) keep(data []byte, mpd *MPDtype, mpdURL url.URL) {
+	f.last = nil
+	f.lastDoc = nil
+	f.lastURL = mpdURL
+	if f.NoPatch || len(mpd.PatchLocation) <= 0 {
+		return
+	}
+	if last, err := ReadMPDFromStream(bytes.NewReader(data)); err == nil {
+		f.last = last
+		f.lastDoc = data
+	}
+}
+
+//patchURL - url of MPD Patch for last MPD, mutex to be held
+// Return:
+//   1: url of PatchLocation resolved with url of last MPD
+//   2: false if no last MPD, no PatchLocation or its ttl is over
+func (f *MPDFetcher) patchURL() (url.URL, bool) {
+	if f.NoPatch || f.last == nil || len(f.last.PatchLocation) <= 0 {
+		return url.URL{}, false
+	}
+	loc := f.last.PatchLocation[0]
+	if loc.Ttl > 0 {
+		expiry := f.last.PublishTime.Add(time.Duration(loc.Ttl * float64(time.Second)))
+		if timeNow(f.Clock).After(expiry) {
+			return url.URL{}, false
+		}
+	}
+	ref, err := url.Parse(strings.TrimSpace(loc.Value))
+	if err != nil {
+		return url.URL{}, false
+	}
+	return *f.lastURL.ResolveReference(ref), true
+}
+
+//fetchPatch - Fetch MPD Patch and apply it on last MPD, mutex to be held
+// Return:
+//   1: MPD after the patch, nil if not modified
+//   2: error
+func (f *MPDFetcher) fetchPatch(ctx context.Context, patchURL url.URL) (*MPDtype, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, patchURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("request for %v failed: %w", patchURL.String(), err)
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %v failed: %w", patchURL.String(), err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotModified:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("fetch %v failed: %v", patchURL.String(), resp.Status)
+	}
+	patch, err := ReadMPDPatchFromStream(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %v failed: %w", patchURL.String(), err)
+	}
+	mpd, data, err := ApplyMPDPatch(f.lastDoc, patch)
+	if err != nil {
+		return nil, err
+	}
+	nextURL, err := locationURL(mpd, f.lastURL)
+	if err != nil {
+		return nil, err
+	}
+	if curURL, err := f.getURL(); err != nil || curURL.String() != nextURL.String() {
+		//Validators belong to the old url
+		f.etag = ""
+		f.lastModified = ""
+	}
+	f.curURL = &nextURL
+	f.keep(data, mpd, f.lastURL)
+	return mpd, nil
+}
+
+//postEvent - Post fetch event
+func (f *MPDFetcher) postEvent(name string, values ...interface{}) {
+	if f.StatzAgg == nil {
+		return
+	}
+	f.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+		EventClock: timeNow(f.Clock),
+		ID:         f.ID,
+		Name:       name,
+		Values:     values,
+	})
+}
+
+//MPDRefresher - Keeps the Reader updated with the MPD fetched
+//  * Refetch after MPD@minimumUpdatePeriod from previous fetch
+//  * Backoff on errors, MinBackoff doubled till MaxBackoff
+//  * Stops once MPD is static or has no MPD@minimumUpdatePeriod
+//  * RefreshNow / HandleInbandEvents refetch before the schedule (MPD validity expiration)
+type MPDRefresher struct {
+	//ID - ID for the events
+	ID string
+	//Fetcher - Fetcher of the MPD
+	Fetcher *MPDFetcher
+	//Reader - Reader to update
+	Reader Reader
+	//StatzAgg - Statz Agg for events
+	StatzAgg statzagg.StatzAgg
+	//MinBackoff - First retry delay after failure, DefaultRefreshMinBackoff if ZERO
+	MinBackoff time.Duration
+	//MaxBackoff - Maximum retry delay, DefaultRefreshMaxBackoff if ZERO
+	MaxBackoff time.Duration
+	//MinInterval - Refresh interval if MPD@minimumUpdatePeriod is ZERO, DefaultRefreshMinInterval if ZERO
+	MinInterval time.Duration
+	//Clock - Clock for the refresh schedule and event timestamps, ReaderFactory.Clock of the Reader if nil
+	Clock Clock
+	//MaxWait - Longest wait of Run between checks of the Clock, DefaultRefreshMaxWait if ZERO
+	MaxWait time.Duration
+
+	mutex       sync.Mutex    //gaurd fields below
+	trigger     chan struct{} //Wakes Run for early refresh
+	publishTime time.Time     //MPD@publishTime of last MPD fetched
+}
+
+//Run - Refresh the MPD till context is done or MPD needs no refresh
+// Parameters:
+//   1: context for cancellation
+//   2: MPD@minimumUpdatePeriod of MPD the Reader was made with
+// Return:
+//   1: error - context error if cancelled, nil if MPD needs no refresh
+func (r *MPDRefresher) Run(ctx context.Context, mup time.Duration) error {
+	minBackoff, maxBackoff, minInterval := r.limits()
+	if mup < minInterval {
+		mup = minInterval
+	}
+	clock := r.clock()
+	if r.Fetcher.Clock == nil {
+		r.Fetcher.Clock = clock
+	}
+	backoff := time.Duration(0)
+	due := timeNow(clock).Add(mup)
+	for {
+		if err := r.wait(ctx, due); err != nil {
+			return err
+		}
+		fetchStart := timeNow(clock)
+		done, next, err := r.refresh(ctx, mup)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if backoff <= 0 {
+				backoff = minBackoff
+			} else if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			r.postEvent(EvtMPDRefreshFailed, err.Error(), backoff)
+			due = timeNow(clock).Add(backoff)
+			continue
+		}
+		if done {
+			return nil
+		}
+		backoff = 0
+		mup = next
+		//Interval from start of the fetch
+		due = fetchStart.Add(mup)
+	}
+}
+
+//wait - Wait till Clock reaches due or early refresh
+// Waits on system timers atmost MaxWait so that a FakeClock change is seen
+// Parameters:
+//   1: context for cancellation
+//   2: Clock time of the refresh
+// Return:
+//   1: context error if cancelled
+func (r *MPDRefresher) wait(ctx context.Context, due time.Time) error {
+	maxWait := r.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRefreshMaxWait
+	}
+	clock := r.clock()
+	trigger := r.triggerChan()
+	for {
+		wait := due.Sub(timeNow(clock))
+		if wait <= 0 {
+			return nil
+		}
+		if wait > maxWait {
+			wait = maxWait
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-trigger:
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+//clock - Clock of the refresher, Clock of the Reader if not set
+func (r *MPDRefresher) clock() Clock {
+	if r.Clock != nil {
+		return r.Clock
+	}
+	if reader, ok := r.Reader.(interface{ localClock() Clock }); ok {
+		return reader.localClock()
+	}
+	return nil
+}
+
+//RefreshNow - Wake Run to refresh the MPD without waiting for the schedule
+func (r *MPDRefresher) RefreshNow() {
+	select {
+	case r.triggerChan() <- struct{}{}:
+	default:
+		//Refresh already pending
+	}
+}
+
+//HandleInbandEvents - Refresh early for MPD validity expiration events
+// Events expecting an MPD@publishTime already fetched are ignored
+// Parameters:
+//   events of a media segment
+// Return:
+//   early refresh triggered?
+func (r *MPDRefresher) HandleInbandEvents(events []InbandEvent) bool {
+	r.mutex.Lock()
+	publishTime := r.publishTime
+	r.mutex.Unlock()
+	for _, evt := range events {
+		if !evt.MPDExpiry {
+			continue
+		}
+		if !evt.MPDPublishTime.IsZero() && !evt.MPDPublishTime.After(publishTime) {
+			continue
+		}
+		r.postEvent(EvtMPDExpired, evt.Value, evt.MPDPublishTime, evt.PresentationTime)
+		r.RefreshNow()
+		return true
+	}
+	return false
+}
+
+//triggerChan - Channel Run waits on for early refresh
+func (r *MPDRefresher) triggerChan() chan struct{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.trigger == nil {
+		r.trigger = make(chan struct{}, 1)
+	}
+	return r.trigger
+}
+
+//limits - Backoff and interval limits with defaults
+// Return:
+//   1: MinBackoff
+//   2: MaxBackoff
+//   3: MinInterval
+func (r *MPDRefresher) limits() (time.Duration, time.Duration, time.Duration) {
+	minBackoff, maxBackoff, minInterval := r.MinBackoff, r.MaxBackoff, r.MinInterval
+	if minBackoff <= 0 {
+		minBackoff = DefaultRefreshMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRefreshMaxBackoff
+	}
+	if minInterval <= 0 {
+		minInterval = DefaultRefreshMinInterval
+	}
+	return minBackoff, maxBackoff, minInterval
+}
+
+//refresh - Fetch MPD once and update the Reader
+// Return:
+//   1: MPD needs no more refresh
+//   2: interval till next refresh
+//   3: error
+func (r *MPDRefresher) refresh(ctx context.Context, mup time.Duration) (bool, time.Duration, error) {
+	mpd, mpdURL, err := r.Fetcher.Fetch(ctx)
+	if err != nil {
+		return false, mup, err
+	}
+	if mpd == nil {
+		//Not modified
+		return false, mup, nil
+	}
+	if _, err := r.Reader.UpdateFrom(mpd, mpdURL.String()); err != nil {
+		return false, mup, err
+	}
+	r.mutex.Lock()
+	if mpd.PublishTime.After(r.publishTime) {
+		r.publishTime = mpd.PublishTime
+	}
+	r.mutex.Unlock()
+	if mpd.Type == "static" || !IsPresentDuration(mpd.MinimumUpdatePeriod) {
+		return true, 0, nil
+	}
+	v, err := ParseDuration(mpd.MinimumUpdatePeriod)
+	if err != nil {
+		return false, mup, fmt.Errorf("MPD.MinimumUpdatePeriod (\"%v\") MUST be valid : %w", mpd.MinimumUpdatePeriod, err)
+	}
+	if _, _, minInterval := r.limits(); v < minInterval {
+		v = minInterval
+	}
+	return false, v, nil
+}
+
+//postEvent - Post refresh event
+func (r *MPDRefresher) postEvent(name string, values ...interface{}) {
+	if r.StatzAgg == nil {
+		return
+	}
+	r.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+		EventClock: timeNow(r.clock()),
+		ID:         r.ID,
+		Name:       name,
+		Values:     values,
+	})
+}
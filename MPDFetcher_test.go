@@ -0,0 +1,237 @@
+package dashreader_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//mpdServer - Serves test/live_refresh.mpd with PublishTime moving on every version
+type mpdServer struct {
+	mutex    sync.Mutex
+	body     string
+	version  int
+	failures int
+	location string
+	moving   bool //new version on every fetch
+	requests []string
+}
+
+func newMPDServer(t *testing.T) *mpdServer {
+	data, err := ioutil.ReadFile("test/live_refresh.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	return &mpdServer{body: string(data), version: 1}
+}
+
+//next - New version of MPD
+func (s *mpdServer) next(location string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.version++
+	s.location = location
+}
+
+func (s *mpdServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, req.URL.Path+" "+req.Header.Get("If-None-Match"))
+	if req.URL.Path == "/old/manifest.mpd" {
+		http.Redirect(w, req, "/live/manifest.mpd", http.StatusFound)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if s.moving {
+		s.version++
+	}
+	etag := fmt.Sprintf("\"v%v\"", s.version)
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	publishTime := time.Date(2020, 1, 1, 0, 0, 10+s.version, 0, time.UTC).Format(time.RFC3339)
+	body := strings.Replace(s.body, "2020-01-01T00:00:10Z", publishTime, 1)
+	if len(s.location) > 0 {
+		body = strings.Replace(body, "<Period", "<Location>"+s.location+"</Location><Period", 1)
+	}
+	w.Header().Set("ETag", etag)
+	w.Write([]byte(body))
+}
+
+func TestMPDFetcher(t *testing.T) {
+	server := newMPDServer(t)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	fetcher := &dashreader.MPDFetcher{URL: ts.URL + "/old/manifest.mpd", Client: ts.Client()}
+	mpd, mpdURL, err := fetcher.Fetch(context.TODO())
+	if err != nil || mpd == nil {
+		t.Fatalf("Fetch failed %v %v", mpd, err)
+	}
+	//Redirect followed
+	if mpdURL.String() != ts.URL+"/live/manifest.mpd" {
+		t.Errorf("MPD url Exp: %v Act: %v", ts.URL+"/live/manifest.mpd", mpdURL.String())
+	}
+	//Conditional GET
+	mpd, _, err = fetcher.Fetch(context.TODO())
+	if err != nil || mpd != nil {
+		t.Errorf("Expected not modified, got %v %v", mpd, err)
+	}
+	//MPD.Location used for next fetch
+	server.next("/moved/manifest.mpd")
+	mpd, _, err = fetcher.Fetch(context.TODO())
+	if err != nil || mpd == nil {
+		t.Fatalf("Fetch failed %v %v", mpd, err)
+	}
+	if u, _ := fetcher.GetURL(); u.String() != ts.URL+"/moved/manifest.mpd" {
+		t.Errorf("Next url Exp: %v Act: %v", ts.URL+"/moved/manifest.mpd", u.String())
+	}
+	expRequests := []string{
+		"/old/manifest.mpd ",
+		"/live/manifest.mpd ",
+		"/live/manifest.mpd \"v1\"",
+		"/live/manifest.mpd \"v1\"",
+	}
+	if strings.Join(server.requests, ",") != strings.Join(expRequests, ",") {
+		t.Errorf("Requests Exp: %v Act: %v", expRequests, server.requests)
+	}
+}
+
+func TestMPDRefresher(t *testing.T) {
+	server := newMPDServer(t)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	fetcher := &dashreader.MPDFetcher{URL: ts.URL + "/live/manifest.mpd", Client: ts.Client()}
+	mpd, mpdURL, err := fetcher.Fetch(context.TODO())
+	if err != nil {
+		t.Fatalf("Fetch failed %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", mpdURL.String(), mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	refresher := &dashreader.MPDRefresher{
+		ID:          "client1",
+		Fetcher:     fetcher,
+		Reader:      rdr,
+		StatzAgg:    events,
+		MinBackoff:  10 * time.Millisecond,
+		MinInterval: 10 * time.Millisecond,
+	}
+	//Two failures with backoff, then moved to another location
+	server.mutex.Lock()
+	server.failures = 2
+	server.moving = true
+	server.location = "/cdn2/manifest.mpd"
+	server.mutex.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- refresher.Run(ctx, 50*time.Millisecond)
+	}()
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	for {
+		time.Sleep(20 * time.Millisecond)
+		readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("Error getting context : %v", err)
+		}
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("Error getting url : %v", err)
+		}
+		//BaseURL rebased on MPD.Location
+		if chunkURL.ChunkURL.String() == ts.URL+"/cdn2/media/v/init.mp4" {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("MPD url not rebased : %v", chunkURL.ChunkURL.String())
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run Exp: %v Act: %v", context.Canceled, err)
+	}
+	if len(events.find(dashreader.EvtMPDRefreshFailed)) != 2 {
+		t.Errorf("Expected 2 %v events, got %v", dashreader.EvtMPDRefreshFailed, len(events.find(dashreader.EvtMPDRefreshFailed)))
+	}
+}
+
+func TestMPDRefresherFakeClock(t *testing.T) {
+	server := newMPDServer(t)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	requests := func() int {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return len(server.requests)
+	}
+	fetcher := &dashreader.MPDFetcher{URL: ts.URL + "/live/manifest.mpd", Client: ts.Client()}
+	mpd, mpdURL, err := fetcher.Fetch(context.TODO())
+	if err != nil {
+		t.Fatalf("Fetch failed %v", err)
+	}
+	clock := dashreader.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 12, 0, time.UTC))
+	clockSync := &dashreader.ClockSync{}
+	xlink := &dashreader.XLinkResolver{}
+	factory := dashreader.ReaderFactory{Clock: clock, ClockSync: clockSync, XLinkResolver: xlink}
+	rdr, err := factory.GetDASHReader("client1", mpdURL.String(), mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	if clockSync.Clock != clock || xlink.Clock != clock {
+		t.Errorf("Clock not defaulted ClockSync:%v XLinkResolver:%v", clockSync.Clock, xlink.Clock)
+	}
+	//Clock of the Reader drives the schedule
+	refresher := &dashreader.MPDRefresher{
+		ID:          "client1",
+		Fetcher:     fetcher,
+		Reader:      rdr,
+		MinInterval: 10 * time.Millisecond,
+		MaxWait:     5 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- refresher.Run(ctx, 10*time.Second)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if n := requests(); n != 1 {
+		t.Errorf("Refreshed before Clock reached MPD@minimumUpdatePeriod, requests %v", n)
+	}
+	clock.Advance(10 * time.Second)
+	for requests() < 2 {
+		if ctx.Err() != nil {
+			t.Fatalf("Not refreshed after Clock reached MPD@minimumUpdatePeriod")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	//Next refresh after MPD@minimumUpdatePeriod of Clock
+	time.Sleep(50 * time.Millisecond)
+	if n := requests(); n != 2 {
+		t.Errorf("Refreshed before Clock moved, requests %v", n)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run Exp: %v Act: %v", context.Canceled, err)
+	}
+	if fetcher.Clock != clock {
+		t.Errorf("Fetcher Clock not defaulted %v", fetcher.Clock)
+	}
+}
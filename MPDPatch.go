@@ -0,0 +1,556 @@
+package dashreader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//MPDPatchOpAdd - RFC 5261 add, element content or attribute (@type) added
+	MPDPatchOpAdd = "add"
+	//MPDPatchOpReplace - RFC 5261 replace, element or attribute value replaced
+	MPDPatchOpReplace = "replace"
+	//MPDPatchOpRemove - RFC 5261 remove, element or attribute removed
+	MPDPatchOpRemove = "remove"
+)
+
+//MPDPatchType - MPD Patch document (PatchLocation)
+// Operations of RFC 5261 applied on the MPD it was made for
+type MPDPatchType struct {
+	MpdId               string              //@mpdId, MPD@id the patch applies to
+	OriginalPublishTime time.Time           //@originalPublishTime, MPD@publishTime the patch applies to
+	PublishTime         time.Time           //@publishTime, MPD@publishTime after the patch
+	Operations          []MPDPatchOperation //Operations in document order
+}
+
+//MPDPatchOperation - One RFC 5261 operation
+type MPDPatchOperation struct {
+	Op    string //MPDPatchOpAdd, MPDPatchOpReplace or MPDPatchOpRemove
+	Sel   string //@sel, XPath of the target element or attribute
+	Pos   string //@pos of add - prepend, before, after; append if empty
+	Type  string //@type of add - @name adds an attribute
+	Value string //text content, value of attribute added or replaced
+
+	content []*patchNode //child nodes of the operation
+}
+
+//patchNode - Element or text of a document being patched
+type patchNode struct {
+	name     xml.Name     //element name, Local empty for text
+	attr     []xml.Attr   //attributes, namespace declarations included
+	text     string       //text of text node
+	children []*patchNode //child elements and texts
+	parent   *patchNode   //nil for root
+}
+
+//ReadMPDPatchFromStream - Reads MPD Patch document from an io.Reader
+func ReadMPDPatchFromStream(r io.Reader) (*MPDPatchType, error) {
+	root, err := readPatchNode(r)
+	if err != nil {
+		return nil, fmt.Errorf("MPD Patch decode failed: %w", err)
+	}
+	if root.name.Local != "Patch" {
+		return nil, fmt.Errorf("MPD Patch root is %v, expected Patch", root.name.Local)
+	}
+	ret := &MPDPatchType{MpdId: root.attrValue("mpdId")}
+	for _, v := range []struct {
+		name string
+		t    *time.Time
+	}{
+		{"originalPublishTime", &ret.OriginalPublishTime},
+		{"publishTime", &ret.PublishTime},
+	} {
+		if err := (*xsdDateTime)(v.t).UnmarshalText([]byte(root.attrValue(v.name))); err != nil {
+			return nil, fmt.Errorf("MPD Patch @%v MUST be valid : %w", v.name, err)
+		}
+	}
+	for _, child := range root.children {
+		if len(child.name.Local) <= 0 {
+			continue
+		}
+		op := MPDPatchOperation{
+			Op:      child.name.Local,
+			Sel:     child.attrValue("sel"),
+			Pos:     child.attrValue("pos"),
+			Type:    child.attrValue("type"),
+			Value:   strings.TrimSpace(child.textValue()),
+			content: child.children,
+		}
+		switch op.Op {
+		case MPDPatchOpAdd, MPDPatchOpReplace, MPDPatchOpRemove:
+		default:
+			return nil, fmt.Errorf("MPD Patch operation %v not supported", op.Op)
+		}
+		if len(op.Sel) <= 0 {
+			return nil, fmt.Errorf("MPD Patch %v @sel MUST be present", op.Op)
+		}
+		ret.Operations = append(ret.Operations, op)
+	}
+	return ret, nil
+}
+
+//ReadMPDPatchFromFile - Reads MPD Patch document from a File
+func ReadMPDPatchFromFile(filename string) (*MPDPatchType, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadMPDPatchFromStream(bufio.NewReader(f))
+}
+
+//ApplyMPDPatch - Apply MPD Patch on the document of the MPD it was made for
+// Result is a new MPD, to be given to Reader.Update like a fetched MPD
+// Parameters:
+//   1: document of the MPD, not modified
+//   2: MPD Patch
+// Return:
+//   1: MPD after the patch
+//   2: document of MPD after the patch, base of the next MPD Patch
+//   3: error if patch is not for the MPD, an operation fails or
+//      MPD@publishTime after the patch is not Patch@publishTime
+func ApplyMPDPatch(data []byte, patch *MPDPatchType) (*MPDtype, []byte, error) {
+	mpd, err := ReadMPDFromStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("MPD before Patch decode failed: %w", err)
+	}
+	if len(mpd.Id) <= 0 {
+		return nil, nil, fmt.Errorf("MPD.Id MUST be present for MPD Patch")
+	}
+	if patch.MpdId != mpd.Id {
+		return nil, nil, fmt.Errorf("MPD Patch mpdId(%v) does not match MPD.Id(%v)", patch.MpdId, mpd.Id)
+	}
+	if !patch.OriginalPublishTime.Equal(mpd.PublishTime) {
+		return nil, nil, fmt.Errorf("MPD Patch originalPublishTime(%v) does not match MPD.PublishTime(%v)",
+			patch.OriginalPublishTime.UTC(), mpd.PublishTime.UTC())
+	}
+	if !patch.PublishTime.After(patch.OriginalPublishTime) {
+		return nil, nil, fmt.Errorf("MPD Patch publishTime(%v) MUST be after originalPublishTime(%v)",
+			patch.PublishTime.UTC(), patch.OriginalPublishTime.UTC())
+	}
+	root, err := readPatchNode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("MPD decode failed: %w", err)
+	}
+	for i := range patch.Operations {
+		root, err = patch.Operations[i].apply(root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("MPD Patch operation %v %v(%v): %w", i, patch.Operations[i].Op, patch.Operations[i].Sel, err)
+		}
+	}
+	var buf bytes.Buffer
+	root.write(&buf)
+	ret, err := ReadMPDFromStream(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("MPD after Patch decode failed: %w", err)
+	}
+	if ret.Id != mpd.Id {
+		return nil, nil, fmt.Errorf("MPD Patch changed MPD.Id(%v) to %v", mpd.Id, ret.Id)
+	}
+	if !ret.PublishTime.Equal(patch.PublishTime) {
+		return nil, nil, fmt.Errorf("MPD.PublishTime(%v) after Patch does not match publishTime(%v)",
+			ret.PublishTime.UTC(), patch.PublishTime.UTC())
+	}
+	return ret, buf.Bytes(), nil
+}
+
+//readPatchNode - Parse document into patchNode tree
+// Names are kept as written, with prefix and namespace declarations,
+// text made of white space only is dropped
+func readPatchNode(r io.Reader) (*patchNode, error) {
+	d := xml.NewDecoder(r)
+	var root, cur *patchNode
+	for {
+		token, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if cur == nil && root != nil {
+				return nil, fmt.Errorf("more than one root element")
+			}
+			node := &patchNode{name: t.Name, attr: t.Copy().Attr, parent: cur}
+			if cur == nil {
+				root = node
+			} else {
+				cur.children = append(cur.children, node)
+			}
+			cur = node
+		case xml.EndElement:
+			if cur == nil || cur.name != t.Name {
+				return nil, fmt.Errorf("unexpected end element %v", t.Name.Local)
+			}
+			cur = cur.parent
+		case xml.CharData:
+			if cur != nil && len(bytes.TrimSpace(t)) > 0 {
+				cur.children = append(cur.children, &patchNode{text: string(t), parent: cur})
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+//write - Write node as XML, names with the prefix they were read with
+func (n *patchNode) write(w *bytes.Buffer) {
+	if len(n.name.Local) <= 0 {
+		xml.EscapeText(w, []byte(n.text))
+		return
+	}
+	w.WriteString("<" + qualifiedName(n.name))
+	for _, a := range n.attr {
+		w.WriteString(" " + qualifiedName(a.Name) + `="`)
+		xml.EscapeText(w, []byte(a.Value))
+		w.WriteString(`"`)
+	}
+	if len(n.children) <= 0 {
+		w.WriteString("/>")
+		return
+	}
+	w.WriteString(">")
+	for _, child := range n.children {
+		child.write(w)
+	}
+	w.WriteString("</" + qualifiedName(n.name) + ">")
+}
+
+//qualifiedName - Name with prefix as read by xml.Decoder.RawToken
+func qualifiedName(name xml.Name) string {
+	if len(name.Space) > 0 {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+//clone - Deep copy of node, parent set to given node
+func (n *patchNode) clone(parent *patchNode) *patchNode {
+	ret := &patchNode{name: n.name, text: n.text, parent: parent}
+	ret.attr = append(ret.attr, n.attr...)
+	for _, child := range n.children {
+		ret.children = append(ret.children, child.clone(ret))
+	}
+	return ret
+}
+
+//attrIndex - Index of attribute with local name, -1 if absent
+func (n *patchNode) attrIndex(name string) int {
+	for i := range n.attr {
+		if n.attr[i].Name.Local == name {
+			return i
+		}
+	}
+	return -1
+}
+
+//attrValue - Value of attribute with local name, empty if absent
+func (n *patchNode) attrValue(name string) string {
+	if i := n.attrIndex(name); i >= 0 {
+		return n.attr[i].Value
+	}
+	return ""
+}
+
+//textValue - Text children joined
+func (n *patchNode) textValue() string {
+	var ret strings.Builder
+	for _, child := range n.children {
+		if len(child.name.Local) <= 0 {
+			ret.WriteString(child.text)
+		}
+	}
+	return ret.String()
+}
+
+//childIndex - Index of child node, -1 if not a child
+func (n *patchNode) childIndex(child *patchNode) int {
+	for i := range n.children {
+		if n.children[i] == child {
+			return i
+		}
+	}
+	return -1
+}
+
+//insert - Insert copies of nodes as children at index
+func (n *patchNode) insert(index int, nodes []*patchNode) {
+	copies := make([]*patchNode, 0, len(nodes))
+	for _, node := range nodes {
+		copies = append(copies, node.clone(n))
+	}
+	children := make([]*patchNode, 0, len(n.children)+len(copies))
+	children = append(children, n.children[:index]...)
+	children = append(children, copies...)
+	n.children = append(children, n.children[index:]...)
+}
+
+//apply - Apply operation on document
+// Return:
+//   1: root of document, changes only if root is replaced
+//   2: error
+func (op *MPDPatchOperation) apply(root *patchNode) (*patchNode, error) {
+	target, attr, err := selectPatchTarget(root, op.Sel)
+	if err != nil {
+		return root, err
+	}
+	switch op.Op {
+	case MPDPatchOpAdd:
+		if len(attr) > 0 {
+			return root, fmt.Errorf("add @sel MUST select an element")
+		}
+		if strings.HasPrefix(op.Type, "@") {
+			name := localPatchName(op.Type[1:])
+			if target.attrIndex(name) >= 0 {
+				return root, fmt.Errorf("attribute %v already present", name)
+			}
+			target.attr = append(target.attr, xml.Attr{Name: xml.Name{Local: name}, Value: op.Value})
+			return root, nil
+		}
+		if len(op.Type) > 0 {
+			return root, fmt.Errorf("add @type %v not supported", op.Type)
+		}
+		switch op.Pos {
+		case "":
+			target.insert(len(target.children), op.content)
+		case "prepend":
+			target.insert(0, op.content)
+		case "before", "after":
+			if target.parent == nil {
+				return root, fmt.Errorf("add @pos %v on root element", op.Pos)
+			}
+			index := target.parent.childIndex(target)
+			if op.Pos == "after" {
+				index++
+			}
+			target.parent.insert(index, op.content)
+		default:
+			return root, fmt.Errorf("add @pos %v not supported", op.Pos)
+		}
+	case MPDPatchOpReplace:
+		if len(attr) > 0 {
+			index := target.attrIndex(attr)
+			if index < 0 {
+				return root, fmt.Errorf("attribute %v not present", attr)
+			}
+			target.attr[index].Value = op.Value
+			return root, nil
+		}
+		var elements []*patchNode
+		for _, node := range op.content {
+			if len(node.name.Local) > 0 {
+				elements = append(elements, node)
+			}
+		}
+		if len(elements) != 1 {
+			return root, fmt.Errorf("replace has %v elements, ONE expected", len(elements))
+		}
+		if target.parent == nil {
+			return elements[0].clone(nil), nil
+		}
+		parent := target.parent
+		index := parent.childIndex(target)
+		parent.children = append(parent.children[:index], parent.children[index+1:]...)
+		parent.insert(index, elements)
+	case MPDPatchOpRemove:
+		if len(attr) > 0 {
+			index := target.attrIndex(attr)
+			if index < 0 {
+				return root, fmt.Errorf("attribute %v not present", attr)
+			}
+			target.attr = append(target.attr[:index], target.attr[index+1:]...)
+			return root, nil
+		}
+		if target.parent == nil {
+			return root, fmt.Errorf("remove of root element")
+		}
+		parent := target.parent
+		index := parent.childIndex(target)
+		parent.children = append(parent.children[:index], parent.children[index+1:]...)
+	}
+	return root, nil
+}
+
+//selectPatchTarget - Evaluate @sel on document
+// XPath subset: absolute path of element steps (name or *) with predicates
+//   [n] position, [@name='value'] attribute (joined with and);
+//   last step may be @name to select an attribute
+// Return:
+//   1: element selected, or element having the attribute
+//   2: attribute name, empty if element selected
+//   3: error unless exactly ONE node is selected
+func selectPatchTarget(root *patchNode, sel string) (*patchNode, string, error) {
+	if !strings.HasPrefix(sel, "/") {
+		return nil, "", fmt.Errorf("@sel MUST be an absolute path")
+	}
+	steps, err := splitPatchPath(sel[1:], '/')
+	if err != nil {
+		return nil, "", err
+	}
+	nodes := []*patchNode{{children: []*patchNode{root}}}
+	for i, step := range steps {
+		if strings.HasPrefix(step, "@") {
+			if i != len(steps)-1 {
+				return nil, "", fmt.Errorf("attribute step %v MUST be last", step)
+			}
+			if len(nodes) != 1 {
+				return nil, "", fmt.Errorf("@sel selects %v elements, ONE expected", len(nodes))
+			}
+			return nodes[0], localPatchName(step[1:]), nil
+		}
+		name, predicates, err := parsePatchStep(step)
+		if err != nil {
+			return nil, "", err
+		}
+		var next []*patchNode
+		for _, node := range nodes {
+			var matched []*patchNode
+			for _, child := range node.children {
+				if len(child.name.Local) > 0 && (name == "*" || child.name.Local == name) {
+					matched = append(matched, child)
+				}
+			}
+			for _, predicate := range predicates {
+				if matched, err = filterPatchNodes(matched, predicate); err != nil {
+					return nil, "", err
+				}
+			}
+			next = append(next, matched...)
+		}
+		nodes = next
+	}
+	if len(nodes) != 1 {
+		return nil, "", fmt.Errorf("@sel selects %v elements, ONE expected", len(nodes))
+	}
+	return nodes[0], "", nil
+}
+
+//splitPatchPath - Split on separator outside predicates and quotes
+func splitPatchPath(path string, sep byte) ([]string, error) {
+	var ret []string
+	depth := 0
+	var quote byte
+	begin := 0
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == sep && depth == 0:
+			ret = append(ret, path[begin:i])
+			begin = i + 1
+		}
+	}
+	if quote != 0 || depth != 0 {
+		return nil, fmt.Errorf("@sel %v not terminated", path)
+	}
+	ret = append(ret, path[begin:])
+	for _, step := range ret {
+		if len(step) <= 0 {
+			return nil, fmt.Errorf("@sel %v has empty step", path)
+		}
+	}
+	return ret, nil
+}
+
+//parsePatchStep - Element name and predicates of a step
+func parsePatchStep(step string) (string, []string, error) {
+	begin := strings.IndexByte(step, '[')
+	if begin < 0 {
+		return localPatchName(step), nil, nil
+	}
+	var predicates []string
+	var quote byte
+	for i := begin; i < len(step); i++ {
+		c := step[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			if i != begin {
+				return "", nil, fmt.Errorf("step %v not correct", step)
+			}
+		case c == ']':
+			predicates = append(predicates, strings.TrimSpace(step[begin+1:i]))
+			begin = i + 1
+			if begin < len(step) && step[begin] != '[' {
+				return "", nil, fmt.Errorf("step %v not correct", step)
+			}
+		}
+	}
+	if begin != len(step) {
+		return "", nil, fmt.Errorf("step %v not correct", step)
+	}
+	return localPatchName(step[:strings.IndexByte(step, '[')]), predicates, nil
+}
+
+//filterPatchNodes - Nodes matching predicate
+func filterPatchNodes(nodes []*patchNode, predicate string) ([]*patchNode, error) {
+	if pos, err := strconv.Atoi(predicate); err == nil {
+		if pos < 1 || pos > len(nodes) {
+			return nil, nil
+		}
+		return nodes[pos-1 : pos], nil
+	}
+	type condition struct {
+		name  string
+		value string
+	}
+	var conditions []condition
+	for _, term := range strings.Split(predicate, " and ") {
+		parts := strings.SplitN(strings.TrimSpace(term), "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "@") {
+			return nil, fmt.Errorf("predicate %v not supported", predicate)
+		}
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		conditions = append(conditions, condition{name: localPatchName(strings.TrimSpace(parts[0][1:])), value: value})
+	}
+	var ret []*patchNode
+	for _, node := range nodes {
+		matched := true
+		for _, c := range conditions {
+			if index := node.attrIndex(c.name); index < 0 || node.attr[index].Value != c.value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ret = append(ret, node)
+		}
+	}
+	return ret, nil
+}
+
+//localPatchName - Name without namespace prefix
+func localPatchName(name string) string {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
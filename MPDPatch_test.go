@@ -0,0 +1,215 @@
+package dashreader_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestMPDPatch(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	data, err := ioutil.ReadFile("test/live_patch.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	mpd, err := dashreader.ReadMPDFromFile("test/live_patch.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	patch, err := dashreader.ReadMPDPatchFromFile("test/live_patch.mpp")
+	if err != nil {
+		t.Fatalf("Error reading patch : %v", err)
+	}
+	if patch.MpdId != "live1" || !patch.PublishTime.Equal(ast.Add(14*time.Second)) || len(patch.Operations) != 6 {
+		t.Fatalf("Patch %+v", patch)
+	}
+	clock := dashreader.NewFakeClock(ast.Add(10 * time.Second))
+	factory := dashreader.ReaderFactory{Clock: clock}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	checkURLs(t, "before", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/V300/init.mp4"),
+		{ChunkURL: mustURL(t, "http://127.0.0.1/live/V300/t8000.m4s").ChunkURL, Duration: 2 * time.Second},
+	})
+
+	source := string(data)
+	patched, patchedData, err := dashreader.ApplyMPDPatch(data, patch)
+	if err != nil {
+		t.Fatalf("Error applying patch : %v", err)
+	}
+	if string(data) != source {
+		t.Errorf("Source MPD document modified")
+	}
+	//Document patched as written, not as MPDtype encodes it
+	for _, exp := range []string{`xmlns:xlink="http://www.w3.org/1999/xlink"`, `<ext:Info xmlns:ext="urn:example:ext" level="1"/>`, `presentationTimeOffset="0"`} {
+		if !strings.Contains(string(patchedData), exp) {
+			t.Errorf("Patched document does not have %v", exp)
+		}
+	}
+	if !patched.PublishTime.Equal(patch.PublishTime) || patched.SuggestedPresentationDelay != "PT4S" ||
+		len(patched.PatchLocation) != 1 || patched.PatchLocation[0].Value != "manifest.mpp" {
+		t.Errorf("Patched MPD %v %v %+v", patched.PublishTime, patched.SuggestedPresentationDelay, patched.PatchLocation)
+	}
+	adapt := patched.Period[0].AdaptationSet[0]
+	if adapt.Representation[0].Bandwidth != 350000 {
+		t.Errorf("Bandwidth Exp: 350000 Act: %v", adapt.Representation[0].Bandwidth)
+	}
+	expS := []dashreader.S{{T: 4000, D: 2000, R: 1}, {T: 8000, D: 2000}, {T: 10000, D: 2000, R: 1}}
+	actS := adapt.SegmentTemplate.SegmentTimeline.S
+	if len(actS) != len(expS) {
+		t.Fatalf("Timeline Exp: %+v Act: %+v", expS, actS)
+	}
+	for i := range expS {
+		if actS[i] != expS[i] {
+			t.Errorf("Timeline S %v Exp: %+v Act: %+v", i, expS[i], actS[i])
+		}
+	}
+
+	//Patched MPD goes through the normal Update
+	clock.Set(ast.Add(14 * time.Second))
+	if updated, err := rdr.Update(patched); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context after update : %v", err)
+	}
+	checkURLs(t, "after", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/V300/init.mp4"),
+		{ChunkURL: mustURL(t, "http://127.0.0.1/live/V300/t10000.m4s").ChunkURL, Duration: 2 * time.Second},
+		{ChunkURL: mustURL(t, "http://127.0.0.1/live/V300/t12000.m4s").ChunkURL, Duration: 2 * time.Second},
+	})
+}
+
+func TestMPDPatchInvalid(t *testing.T) {
+	data, err := ioutil.ReadFile("test/live_patch.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	header := `<Patch xmlns="urn:mpeg:dash:schema:mpd-patch:2020" mpdId="live1" originalPublishTime="2020-01-01T00:00:10Z" publishTime="2020-01-01T00:00:14Z">`
+	publish := `<replace sel="/MPD/@publishTime">2020-01-01T00:00:14Z</replace>`
+	testCases := []struct {
+		name  string
+		patch string
+		err   string
+	}{
+		{"mpdId", strings.Replace(header, "live1", "live2", 1) + publish + `</Patch>`, "mpdId"},
+		{"originalPublishTime", strings.Replace(header, "00:00:10Z", "00:00:08Z", 1) + publish + `</Patch>`, "originalPublishTime"},
+		{"publishTime not patched", header + `</Patch>`, "publishTime"},
+		{"no match", header + publish + `<remove sel="/MPD/Period[@id='p9']"/></Patch>`, "selects 0"},
+		{"many match", header + publish + `<remove sel="/MPD/Period/AdaptationSet/SegmentTemplate/SegmentTimeline/S"/></Patch>`, "selects 2"},
+		{"attribute exists", header + publish + `<add sel="/MPD" type="@type">static</add></Patch>`, "already present"},
+		{"remove root", header + publish + `<remove sel="/MPD"/></Patch>`, "root"},
+	}
+	for _, tc := range testCases {
+		patch, err := dashreader.ReadMPDPatchFromStream(strings.NewReader(tc.patch))
+		if err != nil {
+			t.Fatalf("%v: Error reading patch : %v", tc.name, err)
+		}
+		_, _, err = dashreader.ApplyMPDPatch(data, patch)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: Expected error with %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+//patchServer - Serves test/live_patch.mpd and its patch
+type patchServer struct {
+	mutex    sync.Mutex
+	mpd      string
+	patch    string
+	patchErr bool
+	requests []string
+}
+
+func (s *patchServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, req.URL.Path)
+	switch {
+	case req.URL.Path == "/live/manifest.mpd":
+		w.Write([]byte(s.mpd))
+	case req.URL.Path == "/live/manifest.mpp" && !s.patchErr:
+		w.Write([]byte(s.patch))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestMPDFetcherPatch(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mpdData, err := ioutil.ReadFile("test/live_patch.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	patchData, err := ioutil.ReadFile("test/live_patch.mpp")
+	if err != nil {
+		t.Fatalf("Error reading patch : %v", err)
+	}
+	server := &patchServer{mpd: string(mpdData), patch: string(patchData)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	events := &eventCapture{}
+	clock := dashreader.NewFakeClock(ast.Add(12 * time.Second))
+	fetcher := &dashreader.MPDFetcher{URL: ts.URL + "/live/manifest.mpd", Client: ts.Client(), StatzAgg: events, Clock: clock}
+	if _, _, err := fetcher.Fetch(context.TODO()); err != nil {
+		t.Fatalf("Fetch failed %v", err)
+	}
+	//Patch in place of the MPD
+	mpd, mpdURL, err := fetcher.Fetch(context.TODO())
+	if err != nil || mpd == nil {
+		t.Fatalf("Fetch with patch failed %v %v", mpd, err)
+	}
+	if !mpd.PublishTime.Equal(ast.Add(14*time.Second)) || mpdURL.String() != ts.URL+"/live/manifest.mpd" {
+		t.Errorf("Patched MPD %v from %v", mpd.PublishTime.UTC(), mpdURL.String())
+	}
+	if len(events.find(dashreader.EvtMPDPatchApplied)) != 1 {
+		t.Errorf("Expected 1 %v event", dashreader.EvtMPDPatchApplied)
+	}
+	//Patch for 10s does not apply to 14s, full MPD fetched
+	mpd, _, err = fetcher.Fetch(context.TODO())
+	if err != nil || mpd == nil || !mpd.PublishTime.Equal(ast.Add(10*time.Second)) {
+		t.Fatalf("Fetch after failed patch %v %v", mpd, err)
+	}
+	if len(events.find(dashreader.EvtMPDPatchFailed)) != 1 {
+		t.Errorf("Expected 1 %v event", dashreader.EvtMPDPatchFailed)
+	}
+	//Patch not available
+	server.mutex.Lock()
+	server.patchErr = true
+	server.mutex.Unlock()
+	if mpd, _, err = fetcher.Fetch(context.TODO()); err != nil || mpd == nil {
+		t.Fatalf("Fetch after missing patch %v %v", mpd, err)
+	}
+	//PatchLocation@ttl over
+	clock.Set(ast.Add(71 * time.Second))
+	if mpd, _, err = fetcher.Fetch(context.TODO()); err != nil || mpd == nil {
+		t.Fatalf("Fetch after ttl %v %v", mpd, err)
+	}
+	expRequests := []string{
+		"/live/manifest.mpd",
+		"/live/manifest.mpp",
+		"/live/manifest.mpp",
+		"/live/manifest.mpd",
+		"/live/manifest.mpp",
+		"/live/manifest.mpd",
+		"/live/manifest.mpd",
+	}
+	if strings.Join(server.requests, ",") != strings.Join(expRequests, ",") {
+		t.Errorf("Requests Exp: %v Act: %v", expRequests, server.requests)
+	}
+}
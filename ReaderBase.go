@@ -12,12 +12,17 @@ import (
 
 //readerBase - Fixed values created first time
 type readerBase struct {
-	ID       string            //ID for the Reader
-	baseTime time.Time         //WallClock time of start of period
-	baseURL  url.URL           //Base URL
-	isNumber bool              //Number pattern
-	isTime   bool              //Time pattern
-	StatzAgg statzagg.StatzAgg //Statz Agg
+	ID        string             //ID for the Reader
+	baseTime  time.Time          //WallClock time of start of period
+	baseURL   url.URL            //Base URL
+	baseURLs  []BaseURLCandidate //All MPD BaseURLs, baseURL first
+	mpdURL    url.URL            //MPD url
+	xlink     *XLinkResolver     //Resolver for remote elements, nil if not used
+	onRequest *onRequestPeriods  //Periods with onRequest elements resolved
+	clock     Clock              //Local clock, system clock if nil
+	clockSync *ClockSync         //Server clock offset, local clock if nil
+	events    *EventDispatcher   //Dispatcher of EventStream events, nil if not used
+	StatzAgg  statzagg.StatzAgg  //Statz Agg
 }
 
 //SetStatzAgg - Set StatzAgg for event forwarding
@@ -29,6 +34,70 @@ func (r *readerBase) SetStatzAgg(statzAgg statzagg.StatzAgg) {
 	r.StatzAgg = statzAgg
 }
 
+//SetClock - Set local clock for ReaderContexts made later and events
+// Parameters;
+//   Clock, SystemClock if nil
+// Return:
+//   NA
+func (r *readerBase) SetClock(clock Clock) {
+	r.clock = clock
+}
+
+//localClock - Local clock of the Reader, nil for system clock
+func (r *readerBase) localClock() Clock {
+	return r.clock
+}
+
+//loadOnRequest - Period with xlink:actuate="onRequest" elements resolved
+// Period of the shared MPD is not modified, the resolved copy is used instead
+// Parameters:
+//   1: Period of the current MPD
+// Return:
+//   1: Period to use, same Period if nothing to resolve
+func (r *readerBase) loadOnRequest(period *PeriodType) *PeriodType {
+	if r.xlink == nil || r.onRequest == nil {
+		return period
+	}
+	return r.onRequest.resolve(r.xlink, period, r.mpdURL)
+}
+
+//onRequestPeriods - Periods with onRequest elements resolved, shared by the ReaderContexts
+type onRequestPeriods struct {
+	mutex    sync.Mutex                  //gaurd fields below
+	resolved map[*PeriodType]*PeriodType //Period of MPD (and resolved copy) to resolved copy
+}
+
+//resolve - Resolved copy of Period, resolved once for an MPD
+func (o *onRequestPeriods) resolve(xlink *XLinkResolver, period *PeriodType, mpdURL url.URL) *PeriodType {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if ret, ok := o.resolved[period]; ok {
+		return ret
+	}
+	//Slices modified by resolution are copied
+	ret := *period
+	ret.AdaptationSet = append([]AdaptationSetType{}, period.AdaptationSet...)
+	for i := range ret.AdaptationSet {
+		ret.AdaptationSet[i].Representation = append([]RepresentationType{}, ret.AdaptationSet[i].Representation...)
+	}
+	ret.EventStream = append([]EventStreamType{}, period.EventStream...)
+	//Failures are posted as events, failed elements removed
+	_ = xlink.ResolveOnRequest(&ret, mpdURL)
+	if o.resolved == nil {
+		o.resolved = make(map[*PeriodType]*PeriodType)
+	}
+	o.resolved[period] = &ret
+	o.resolved[&ret] = &ret
+	return &ret
+}
+
+//reset - Periods of a new MPD to be resolved
+func (o *onRequestPeriods) reset() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.resolved = nil
+}
+
 //readerBaseExtn - Base functionality for all dash readers
 type readerBaseExtn struct {
 	readerBase
@@ -39,50 +108,131 @@ type readerBaseExtn struct {
 }
 
 //checkUpdate - Invoked by Client to
-func (r *readerBaseExtn) checkUpdate() (*MPDtype, int64) {
+// Return:
+//   1: Current MPD
+//   2: Update counter of Current MPD
+//   3: Reader fixed values, BaseURL as per Current MPD
+func (r *readerBaseExtn) checkUpdate() (*MPDtype, int64, readerBase) {
 	//Allow for parallel read and serialized writes
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
-	return r.curMpd, r.updCounter
+	return r.curMpd, r.updCounter, r.readerBase
 }
 
 //Update - Update the MPD content
+// MPD is considered fetched from the same url as before
 // Parameters:
 //   MPD read
 // Return:
 //   1: MPD Updated - PublishTime Updated?
 //   2: error
 func (r *readerBaseExtn) Update(newMpd *MPDtype) (bool, error) {
-	if !IsPresentTime(newMpd.PublishTime) {
+	_, _, reader := r.checkUpdate()
+	return r.UpdateFrom(newMpd, reader.mpdURL.String())
+}
+
+//UpdateFrom - Update the MPD content fetched from url
+// MPD not newer than Current MPD is rejected before it is modified
+// BaseURLs are rebased on the url (redirect or MPD.Location)
+// Remote elements are resolved before the MPD is used
+// Server clock is synced again if MPD.UTCTiming changed
+// Parameters:
+//   1: MPD read
+//   2: url the MPD was fetched from (after redirects)
+// Return:
+//   1: MPD Updated - PublishTime Updated?
+//   2: error
+func (r *readerBaseExtn) UpdateFrom(newMpd *MPDtype, mpdURL string) (bool, error) {
+	if !IsPresentTime(newMpd.PublishTime) && newMpd.Type != "static" {
 		return false, fmt.Errorf("MPD.PublishTime MUST be present")
 	}
+	u, err := url.Parse(mpdURL)
+	if err != nil {
+		return false, fmt.Errorf("Supplied mpdURL(%v) not correct: %w", mpdURL, err)
+	}
+	curMpd, _, _ := r.checkUpdate()
+	if newer, err := r.isNewer(curMpd, newMpd); !newer {
+		return false, err
+	}
+	if r.xlink != nil {
+		//Failures are posted as events, failed elements removed
+		_ = r.xlink.Resolve(newMpd, *u)
+	}
+	baseURLs, err := resolveMPDBaseURLs(newMpd, *u)
+	if err != nil {
+		return false, err
+	}
+	r.syncClock(newMpd, *u)
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	if r.curMpd != nil {
-		if r.curMpd.PublishTime.Equal(newMpd.PublishTime) {
-			return false, nil
-		}
-		if r.curMpd.PublishTime.After(newMpd.PublishTime) {
-			if r.StatzAgg != nil {
-				values := make([]interface{}, 2)
-				values[0] = r.curMpd.PublishTime
-				values[1] = newMpd.PublishTime
-				r.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-					EventClock: time.Now(),
-					ID:         r.ID,
-					Name:       EvtMPDPublishTimeOld,
-					Values:     values,
-				})
-			}
-			return false, fmt.Errorf("MPD.PublishTime MUST move forward. Ignoring")
-		}
+	//Another update may have completed meanwhile
+	if newer, err := r.isNewer(r.curMpd, newMpd); !newer {
+		return false, err
 	}
 	r.lastMpd = r.curMpd
 	r.curMpd = newMpd
+	r.mpdURL = *u
+	r.baseURL = baseURLs[0].URL
+	r.baseURLs = baseURLs
 	r.updCounter++
+	if r.onRequest != nil {
+		r.onRequest.reset()
+	}
+	if r.events != nil {
+		r.events.update(newMpd, getPeriodTimings(r.readerBase, newMpd))
+	}
 	return true, nil
 }
 
+//isNewer - MPD.PublishTime of new MPD after Current MPD?
+// Parameters:
+//   1: Current MPD, nil if none
+//   2: MPD read
+// Return:
+//   1: new MPD to be used?
+//   2: error if MPD.PublishTime moved back
+func (r *readerBaseExtn) isNewer(curMpd *MPDtype, newMpd *MPDtype) (bool, error) {
+	if curMpd == nil {
+		return true, nil
+	}
+	if curMpd.PublishTime.Equal(newMpd.PublishTime) {
+		return false, nil
+	}
+	if curMpd.PublishTime.After(newMpd.PublishTime) {
+		if r.StatzAgg != nil {
+			values := make([]interface{}, 2)
+			values[0] = curMpd.PublishTime
+			values[1] = newMpd.PublishTime
+			r.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+				EventClock: timeNow(r.clock),
+				ID:         r.ID,
+				Name:       EvtMPDPublishTimeOld,
+				Values:     values,
+			})
+		}
+		return false, fmt.Errorf("MPD.PublishTime MUST move forward. Ignoring")
+	}
+	return true, nil
+}
+
+//syncClock - Sync server clock with UTCTiming of new live MPD
+// Not synced if MPD is not newer or UTCTiming same as Current MPD,
+// offset measured earlier is kept
+// Parameters:
+//   1: MPD read
+//   2: url the MPD was fetched from
+func (r *readerBaseExtn) syncClock(newMpd *MPDtype, mpdURL url.URL) {
+	curMpd, _, reader := r.checkUpdate()
+	if reader.clockSync == nil || newMpd.Type == "static" || len(newMpd.UTCTiming) <= 0 {
+		return
+	}
+	if curMpd != nil && (!newMpd.PublishTime.After(curMpd.PublishTime) || !utcTimingChanged(curMpd.UTCTiming, newMpd.UTCTiming)) {
+		return
+	}
+	//Bounded by ClockSync.Timeout, failures are posted as events, previous offset kept
+	_ = reader.clockSync.Sync(context.Background(), newMpd.UTCTiming, mpdURL)
+}
+
 //MakeDASHReaderContext - Makes Reader Context
 // Parameters:
 //   1: Context received earlier... if first time pass nil
@@ -3,6 +3,9 @@ package dashreader
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/eswarantg/statzagg"
@@ -17,6 +20,13 @@ type readerBaseContext struct {
 	streamSelector StreamSelector         //Selector for stream
 	adaptSetID     uint                   //ID of adapatationSet
 	repID          StringNoWhitespaceType //selected RepresentationID
+	bandwidth      uint                   //selected Representation@bandwidth
+	baseURLs       []BaseURLCandidate     //BaseURLs of selected Representation, default first
+	baseURLIndex   int                    //BaseURL in use
+	failedURLs     map[string]bool        //Locations reported failed
+	clock          Clock                  //Local clock, system clock if nil
+	clockSync      *ClockSync             //Server clock offset, local clock if nil
+	options        ContextOptions         //Options of MakeDASHReaderContext
 
 	//Context fields
 	frameRate   float64
@@ -33,7 +43,7 @@ func (c *readerBaseContext) Select(p PeriodType) error {
 			values := make([]interface{}, 1)
 			values[0] = len(p.AdaptationSet)
 			c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-				EventClock: time.Now(),
+				EventClock: timeNow(c.clock),
 				ID:         c.ID,
 				Name:       EvtMPDNoAdaptAfterFilter,
 				Values:     values,
@@ -48,7 +58,7 @@ func (c *readerBaseContext) Select(p PeriodType) error {
 			values := make([]interface{}, 1)
 			values[0] = len(adaptSet.Representation)
 			c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-				EventClock: time.Now(),
+				EventClock: timeNow(c.clock),
 				ID:         c.ID,
 				Name:       EvtMPDNoRepresentationAfterFilter,
 				Values:     values,
@@ -61,6 +71,482 @@ func (c *readerBaseContext) Select(p PeriodType) error {
 	return nil
 }
 
+//representationSwitcher - ReaderContext that can change Representation keeping its position
+type representationSwitcher interface {
+	ReaderContext
+	//baseContext - readerBaseContext embedded in the ReaderContext
+	baseContext() *readerBaseContext
+	//servedPeriod - Period being served in the MPD, nil if not present
+	servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming)
+	//switchRepresentation - Serve the Representation of selected AdaptationSet
+	// init of Representation is returned next, then segments from the position reached
+	switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error
+}
+
+//baseContext - readerBaseContext embedded in the ReaderContext
+func (c *readerBaseContext) baseContext() *readerBaseContext {
+	return c
+}
+
+//copyContext - Copy of ReaderContext, changes to the copy leave the ReaderContext unchanged
+func copyContext(rdrCtx representationSwitcher) representationSwitcher {
+	v := reflect.ValueOf(rdrCtx).Elem()
+	ret := reflect.New(v.Type())
+	ret.Elem().Set(v)
+	return ret.Interface().(representationSwitcher)
+}
+
+//prepareContext - Common start of makeContext
+// Copy of the context received earlier, else newCtx initialised from Reader
+// Selectors and options of this call applied to it
+// Parameters:
+//   1: Reader fixed values
+//   2: Context received earlier... if first time pass nil
+//   3: New context of the Reader's type, its own fields initialised
+//   4: StreamSelector for the ContentType to select AdaptationSet
+//   5: RepresentationSelector ... selector for Representation
+//   6: ContextOptions
+// Return:
+//   1: Context to be updated by makeContext, same type as newCtx
+//   2: error
+func prepareContext(reader readerBase, rdrCtx ReaderContext, newCtx representationSwitcher, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (representationSwitcher, error) {
+	curContext := newCtx
+	if rdrCtx != nil {
+		v, ok := rdrCtx.(representationSwitcher)
+		if !ok || reflect.TypeOf(v) != reflect.TypeOf(newCtx) {
+			return nil, fmt.Errorf("ReaderContext(%T) not created by this Reader", rdrCtx)
+		}
+		curContext = copyContext(v)
+	} else {
+		*curContext.baseContext() = readerBaseContext{
+			ID:             reader.ID,
+			adaptSetID:     0,
+			repID:          "",
+			updCounter:     0,
+			repSelector:    repSelector,
+			streamSelector: streamSelector,
+			StatzAgg:       reader.StatzAgg,
+			clock:          reader.clock,
+			clockSync:      reader.clockSync,
+		}
+	}
+	base := curContext.baseContext()
+	if repSelector != nil {
+		//Selector applied again on every call, ABR selectors keep their state
+		base.repSelector = repSelector
+	}
+	if reflect.TypeOf(base.streamSelector) != reflect.TypeOf(streamSelector) {
+		base.streamSelector = streamSelector
+	}
+	base.options = options
+	return curContext, nil
+}
+
+//servedAdaptationSet - Selected AdaptationSet in the Period being served
+// Return:
+//   1: AdaptationSet
+//   2: Period being served
+//   3: WallClock timing of Period
+//   4: error
+func (c *readerBaseContext) servedAdaptationSet(reader readerBase, curMpd *MPDtype, switcher representationSwitcher) (*AdaptationSetType, *PeriodType, periodTiming, error) {
+	period, timing := switcher.servedPeriod(reader, curMpd)
+	if period == nil {
+		return nil, nil, timing, fmt.Errorf("Period being served not present in MPD")
+	}
+	period = reader.loadOnRequest(period)
+	for i := range period.AdaptationSet {
+		adapt := &period.AdaptationSet[i]
+		if adapt.ContentType == c.streamSelector.ContentType && adapt.Id == c.adaptSetID {
+			return adapt, period, timing, nil
+		}
+	}
+	return nil, period, timing, fmt.Errorf("AdaptationSet(%v) not present in Period(%v)", c.adaptSetID, period.Id)
+}
+
+//reselect - Apply RepresentationSelector again in the selected AdaptationSet
+// On a different selection the context switches keeping its position
+// Failure is posted as an event, selection unchanged
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: ReaderContext embedding this context
+func (c *readerBaseContext) reselect(reader readerBase, curMpd *MPDtype, switcher representationSwitcher) {
+	if c.repSelector == nil {
+		return
+	}
+	adaptSet, _, _, err := c.servedAdaptationSet(reader, curMpd, switcher)
+	var repID StringNoWhitespaceType
+	if err == nil {
+		rep := c.repSelector.SelectRepresentation(c.filterRepresentation(*adaptSet))
+		if rep == nil || rep.Id == c.repID {
+			return
+		}
+		repID = rep.Id
+		err = c.switchTo(reader, curMpd, switcher, repID)
+	}
+	if err != nil && c.StatzAgg != nil {
+		values := make([]interface{}, 2)
+		values[0] = repID
+		values[1] = err.Error()
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: timeNow(c.clock),
+			ID:         c.ID,
+			Name:       EvtRepresentationReselectFailed,
+			Values:     values,
+		})
+	}
+}
+
+//switchTo - Move to Representation of the selected AdaptationSet keeping the position
+//  * init of the Representation is returned next
+//  * segments continue from the one containing the end of last URL returned,
+//    the next segment boundary when segments are aligned
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: ReaderContext embedding this context
+//   4: Representation@id
+// Return:
+//   error, context unchanged
+func (c *readerBaseContext) switchTo(reader readerBase, curMpd *MPDtype, switcher representationSwitcher, repID StringNoWhitespaceType) error {
+	adaptSet, period, timing, err := c.servedAdaptationSet(reader, curMpd, switcher)
+	if err != nil {
+		return err
+	}
+	found := false
+	for i := range adaptSet.Representation {
+		if adaptSet.Representation[i].Id == repID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("Representation(%v) not present in AdaptationSet(%v)", repID, c.adaptSetID)
+	}
+	if repID == c.repID {
+		return nil
+	}
+	from := c.repID
+	if err := switcher.switchRepresentation(reader, curMpd, period, timing, repID); err != nil {
+		return fmt.Errorf("Switch to Representation(%v) failed: %w", repID, err)
+	}
+	if c.StatzAgg != nil {
+		values := make([]interface{}, 3)
+		values[0] = from
+		values[1] = c.repID
+		values[2] = c.bandwidth
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: timeNow(c.clock),
+			ID:         c.ID,
+			Name:       EvtRepresentationSwitch,
+			Values:     values,
+		})
+	}
+	return nil
+}
+
+//selectPeriod - Resolve onRequest remote elements and select in Period
+func (c *readerBaseContext) selectPeriod(reader readerBase, period *PeriodType) error {
+	return c.Select(*reader.loadOnRequest(period))
+}
+
+//periodTiming - WallClock timing of a Period
+type periodTiming struct {
+	index int       //index of Period in MPD
+	start time.Time //WallClock start of Period
+	end   time.Time //WallClock end of Period, ZERO if not known
+}
+
+//getPeriodTimings - WallClock start and end of each Period
+// PSwc = AST + Period@start
+// PSwc = previous PSwc + previous Period@duration, when Period@start is absent
+// PEwc = next PSwc, or PSwc + Period@duration, or AST + MPD@mediaPresentationDuration for last
+func getPeriodTimings(reader readerBase, curMpd *MPDtype) []periodTiming {
+	ret := make([]periodTiming, len(curMpd.Period))
+	pSwc := reader.baseTime
+	var lastDuration *time.Duration
+	for i := range curMpd.Period {
+		period := &curMpd.Period[i]
+		if IsPresentDuration(period.Start) {
+			v, _ := ParseDuration(period.Start)
+			pSwc = reader.baseTime.Add(v)
+		} else if lastDuration != nil {
+			pSwc = pSwc.Add(*lastDuration)
+		}
+		ret[i] = periodTiming{index: i, start: pSwc}
+		lastDuration = nil
+		if IsPresentDuration(period.Duration) {
+			v, _ := ParseDuration(period.Duration)
+			lastDuration = &v
+			ret[i].end = pSwc.Add(v)
+		}
+		if i > 0 {
+			ret[i-1].end = pSwc
+		}
+	}
+	if len(ret) > 0 && !IsPresentTime(ret[len(ret)-1].end) && IsPresentDuration(curMpd.MediaPresentationDuration) {
+		v, _ := ParseDuration(curMpd.MediaPresentationDuration)
+		ret[len(ret)-1].end = reader.baseTime.Add(v)
+	}
+	return ret
+}
+
+//getPeriodByID - Period with given Period@id
+// Return:
+//   1: Period, nil if not found
+//   2: WallClock timing of Period
+func getPeriodByID(reader readerBase, curMpd *MPDtype, periodID string) (*PeriodType, periodTiming) {
+	for _, timing := range getPeriodTimings(reader, curMpd) {
+		if curMpd.Period[timing.index].Id == periodID {
+			return &curMpd.Period[timing.index], timing
+		}
+	}
+	return nil, periodTiming{index: -1}
+}
+
+//now - Current WallClock as per server clock
+func (c *readerBaseContext) now() time.Time {
+	offset, _ := c.clockSync.Offset()
+	return timeNow(c.clock).Add(offset)
+}
+
+//localTime - WallClock of server converted to local clock for ChunkURL.FetchAt
+func (c *readerBaseContext) localTime(wallClock time.Time) time.Time {
+	return c.clockSync.ToLocal(wallClock)
+}
+
+//setAvailability - FetchAt, CompleteAt and Chunked of live segment
+// Parameters:
+//   1: ChunkURL to set
+//   2: WallClock segment becomes available, first chunk for LL-DASH
+//   3: WallClock segment is complete
+//   4: @availabilityTimeComplete
+func (c *readerBaseContext) setAvailability(ret *ChunkURL, fetchAt time.Time, completeAt time.Time, complete bool) {
+	ret.FetchAt = c.localTime(fetchAt)
+	ret.CompleteAt = c.localTime(completeAt)
+	ret.Chunked = !complete && c.now().Before(completeAt)
+}
+
+//getActivePeriod - Period active at the given WallClock
+// Parameters:
+//   1: Reader fixed values
+//   2: MPD to look in
+//   3: WallClock reference
+// Return:
+//   1: Period active, nil if not found
+//   2: WallClock start of Period
+func (c *readerBaseContext) getActivePeriod(reader readerBase, curMpd *MPDtype, curWc time.Time) (*PeriodType, time.Time) {
+	period, timing := c.getActivePeriodTiming(reader, curMpd, curWc)
+	return period, timing.start
+}
+
+//getActivePeriodTiming - Period active at the given WallClock
+// Parameters:
+//   1: Reader fixed values
+//   2: MPD to look in
+//   3: WallClock reference
+// Return:
+//   1: Period active, nil if not found
+//   2: WallClock timing of Period
+func (c *readerBaseContext) getActivePeriodTiming(reader readerBase, curMpd *MPDtype, curWc time.Time) (*PeriodType, periodTiming) {
+	for _, timing := range getPeriodTimings(reader, curMpd) {
+		if curWc.Before(timing.start) {
+			break
+		}
+		if IsPresentTime(timing.end) && !curWc.Before(timing.end) {
+			//The entire period is before curWc
+			continue
+		}
+		//pSwc <= curWc && No End known
+		//pSwc <= curWc < pEwc
+		return &curMpd.Period[timing.index], timing
+	}
+	if c.StatzAgg != nil {
+		values := make([]interface{}, 1)
+		values[0] = len(curMpd.Period)
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: timeNow(c.clock),
+			ID:         c.ID,
+			Name:       EvtMPDNoActivePeriod,
+			Values:     values,
+		})
+	}
+	return nil, periodTiming{index: -1}
+}
+
+//locateRepresentation - Find the selected AdaptationSet and Representation in Period
+// Parameters:
+//   1: Reader fixed values
+//   2: Period to look in
+// Return:
+//   1: AdaptationSet resolved
+//   2: Representation resolved (BaseURL, Segment addressing and attributes inherited)
+//   3: error
+func (c *readerBaseContext) locateRepresentation(reader readerBase, period *PeriodType) (*ResolvedAdaptationSet, *ResolvedRepresentation, error) {
+	resolved, err := resolvePeriod(reader.loadOnRequest(period), reader.baseURLs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range resolved.AdaptationSets {
+		adapt := &resolved.AdaptationSets[i]
+		if adapt.ContentType != c.streamSelector.ContentType || adapt.ID != c.adaptSetID {
+			continue
+		}
+		//Found matching AdaptationSet
+		for j := range adapt.Representations {
+			rp := &adapt.Representations[j]
+			if rp.ID != c.repID {
+				continue
+			}
+			//Found matching Representation
+			return adapt, rp, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("Representation(%v:%v) not found", c.adaptSetID, c.repID)
+}
+
+//setContentFields - Fill the content fields from the selection
+func (c *readerBaseContext) setContentFields(adapt *ResolvedAdaptationSet, rp *ResolvedRepresentation) {
+	c.contentType = adapt.ContentType
+	c.lang = adapt.Lang
+	c.codecs = rp.Codecs
+	c.frameRate, _ = GetFrameRate(string(rp.FrameRate))
+	c.bandwidth = rp.Bandwidth
+	c.setBaseURLs(rp.BaseURLs)
+}
+
+//setBaseURLs - BaseURLs of the selected Representation
+// Location in use is kept if still present and not failed
+func (c *readerBaseContext) setBaseURLs(baseURLs []BaseURLCandidate) {
+	var curLocation string
+	if c.baseURLIndex < len(c.baseURLs) {
+		curLocation = c.baseURLs[c.baseURLIndex].location()
+	}
+	c.baseURLs = baseURLs
+	for i := range baseURLs {
+		if baseURLs[i].location() == curLocation && !c.failedURLs[curLocation] {
+			c.baseURLIndex = i
+			return
+		}
+	}
+	c.baseURLIndex = SelectBaseURL(baseURLs, c.failedURLs, nil)
+	if c.baseURLIndex < 0 {
+		//All failed... stay on the default
+		c.baseURLIndex = 0
+	}
+}
+
+//relocate - Move url built on the default BaseURL to the BaseURL in use
+func (c *readerBaseContext) relocate(u url.URL) url.URL {
+	if c.baseURLIndex <= 0 || c.baseURLIndex >= len(c.baseURLs) {
+		return u
+	}
+	from := c.baseURLs[0].URL.String()
+	v := u.String()
+	if !strings.HasPrefix(v, from) {
+		//Absolute url not under BaseURL
+		return u
+	}
+	ret, err := url.Parse(c.baseURLs[c.baseURLIndex].URL.String() + v[len(from):])
+	if err != nil {
+		return u
+	}
+	return *ret
+}
+
+//relocateChunk - ChunkURL with url moved to the BaseURL in use
+func (c *readerBaseContext) relocateChunk(chunkURL *ChunkURL) *ChunkURL {
+	chunkURL.ChunkURL = c.relocate(chunkURL.ChunkURL)
+	return chunkURL
+}
+
+//FetchFailed - Report failure to fetch a ChunkURL
+//-- Location (@serviceLocation) of the url is not used again
+//-- URLs returned later are from the next BaseURL as per DVB priority/weight
+// Parameters:
+//   ChunkURL which failed
+// Return:
+//   error if url is not from a BaseURL or no other BaseURL is available
+func (c *readerBaseContext) FetchFailed(chunkURL ChunkURL) error {
+	failed := -1
+	failedLen := 0
+	v := chunkURL.ChunkURL.String()
+	for i := range c.baseURLs {
+		from := c.baseURLs[i].URL.String()
+		if strings.HasPrefix(v, from) && len(from) > failedLen {
+			failed = i
+			failedLen = len(from)
+		}
+	}
+	if failed < 0 {
+		return fmt.Errorf("ChunkURL(%v) not from BaseURLs of Representation(%v)", v, c.repID)
+	}
+	//Copied on write, map is shared with copies of the context
+	failedURLs := make(map[string]bool, len(c.failedURLs)+1)
+	for location := range c.failedURLs {
+		failedURLs[location] = true
+	}
+	failedURLs[c.baseURLs[failed].location()] = true
+	c.failedURLs = failedURLs
+	next := SelectBaseURL(c.baseURLs, c.failedURLs, nil)
+	if next < 0 {
+		return fmt.Errorf("All BaseURLs(%v) of Representation(%v) failed", len(c.baseURLs), c.repID)
+	}
+	if next != c.baseURLIndex && c.StatzAgg != nil {
+		values := make([]interface{}, 2)
+		values[0] = c.baseURLs[c.baseURLIndex].URL.String()
+		values[1] = c.baseURLs[next].URL.String()
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: timeNow(c.clock),
+			ID:         c.ID,
+			Name:       EvtBaseURLFailover,
+			Values:     values,
+		})
+	}
+	c.baseURLIndex = next
+	return nil
+}
+
+//loadTemplate - Parse SegmentTemplate urls of the selected Representation
+// Parameters:
+//   1: SegmentTemplate
+//   2: BaseURL resolved till Representation
+// Return:
+//   1: init url, ZERO if not present
+//   2: init range
+//   3: media template
+//   4: error
+func (c *readerBaseContext) loadTemplate(segTemplate *SegmentTemplateType, rpBaseURL url.URL) (url.URL, string, *URLTemplate, error) {
+	media, err := ParseURLTemplate(segTemplate.Media)
+	if err != nil {
+		return url.URL{}, "", nil, fmt.Errorf("Representation(%v) SegmentTemplate.Media: %w", c.repID, err)
+	}
+	initSourceURL, initRange := getTemplateInitialization(segTemplate)
+	if len(initSourceURL) <= 0 {
+		return url.URL{}, "", media, nil
+	}
+	init, err := ParseURLTemplate(initSourceURL)
+	if err != nil {
+		return url.URL{}, "", nil, fmt.Errorf("Representation(%v) SegmentTemplate.Initialization: %w", c.repID, err)
+	}
+	initURL, err := init.resolve(rpBaseURL, c.templateValues())
+	if err != nil {
+		return url.URL{}, "", nil, fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", c.repID, err)
+	}
+	return initURL, initRange, media, nil
+}
+
+//templateValues - SegmentTemplate values of the selected Representation
+// $Number$ and $Time$ are to be filled by the caller
+// $SubNumber$ is 1 as Segment Sequences are not expanded
+func (c *readerBaseContext) templateValues() TemplateValues {
+	return TemplateValues{
+		RepresentationID: string(c.repID),
+		Bandwidth:        uint64(c.bandwidth),
+		SubNumber:        1,
+	}
+}
+
 //selectAdapationSets for period
 func (c *readerBaseContext) selectAdapationSets(p PeriodType) *AdaptationSetType {
 	var ret *AdaptationSetType
@@ -76,10 +562,22 @@ func (c *readerBaseContext) selectAdapationSets(p PeriodType) *AdaptationSetType
 		if matchResp == MatchResultNotFound {
 			continue
 		}
-		//Check if found first time or this is a better match
-		if ret == nil || matchResp > lastMatchResp {
+		//Check if found first time or preferred
+		if ret == nil {
 			ret = &p.AdaptationSet[i]
-			matchResp = lastMatchResp
+			lastMatchResp = matchResp
+			continue
+		}
+		//Roles, Accessibilities, Labels order then a better match
+		switch c.streamSelector.ComparePreference(p.AdaptationSet[i], *ret) {
+		case -1:
+			ret = &p.AdaptationSet[i]
+			lastMatchResp = matchResp
+		case 0:
+			if matchResp > lastMatchResp {
+				ret = &p.AdaptationSet[i]
+				lastMatchResp = matchResp
+			}
 		}
 	}
 	return ret
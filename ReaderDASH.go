@@ -0,0 +1,170 @@
+package dashreader
+
+import (
+	"fmt"
+)
+
+//addressingMode - Segment addressing used by a Representation
+type addressingMode int
+
+const (
+	addressingSegmentBase      addressingMode = iota //SegmentBase (single file, sidx)
+	addressingSegmentList                            //SegmentList with SegmentURLs
+	addressingTemplateTimeline                       //SegmentTemplate with SegmentTimeline
+	addressingTemplateDuration                       //SegmentTemplate@duration
+)
+
+//contextMaker - Makes ReaderContext for one addressing mode
+type contextMaker interface {
+	makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error)
+}
+
+//readerDASH - Implement Reader of MPD
+//  * Live or Static
+//  * Addressing decided per selected Representation
+//  * AdaptationSets of the MPD can use different addressing
+type readerDASH struct {
+	readerBaseExtn
+	isLive       bool         //MPD@type dynamic
+	indexFetcher IndexFetcher //Fetcher for sidx bytes
+}
+
+//MakeDASHReaderContext - Makes Reader Context
+// Parameters:
+//   1: Context received earlier... if first time pass nil
+//   2: StreamSelector for the ContentType to select AdaptationSet
+//   3: RepresentationSelector ... selector for Representation
+//   4: ContextOptions ... live start policy, target latency
+// Return:
+//   1: Context for current AdaptationSet,Representation
+//   2: error
+func (r *readerDASH) MakeDASHReaderContext(rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, opts ...ContextOption) (ReaderContext, error) {
+	curMpd, updCounter, reader := r.readerBaseExtn.checkUpdate()
+	options := makeContextOptions(opts)
+	maker, err := r.getContextMaker(reader, rdrCtx, curMpd, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	return maker.makeContext(reader, curMpd, updCounter, rdrCtx, streamSelector, repSelector, options)
+}
+
+//SwitchRepresentation - Move ReaderContext to another Representation of its AdaptationSet
+// Parameters:
+//   1: Context received earlier
+//   2: Representation@id in the same AdaptationSet
+// Return:
+//   1: Context for the Representation
+//   2: error, Context received is unchanged
+func (r *readerDASH) SwitchRepresentation(rdrCtx ReaderContext, repID string) (ReaderContext, error) {
+	if rdrCtx == nil {
+		return nil, fmt.Errorf("ReaderContext MUST be made before switching Representation")
+	}
+	curMpd, updCounter, reader := r.readerBaseExtn.checkUpdate()
+	return r.switchContext(reader, curMpd, updCounter, rdrCtx, StringNoWhitespaceType(repID))
+}
+
+//switchContext - Move Context to another Representation of its AdaptationSet
+// Position is located in Current MPD, Context is in sync with it once switched
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier
+//   5: Representation@id in the same AdaptationSet
+// Return:
+//   1: Context for the Representation
+//   2: error, Context received is unchanged
+func (r *readerDASH) switchContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, repID StringNoWhitespaceType) (ReaderContext, error) {
+	v, ok := rdrCtx.(representationSwitcher)
+	if !ok {
+		return nil, fmt.Errorf("ReaderContext(%T) not created by this Reader", rdrCtx)
+	}
+	curContext := copyContext(v)
+	base := curContext.baseContext()
+	switched := base.repID != repID
+	if err := base.switchTo(reader, curMpd, curContext, repID); err != nil {
+		return rdrCtx, err
+	}
+	if switched {
+		base.updCounter = updCounter
+	}
+	return curContext, nil
+}
+
+//getContextMaker - contextMaker for the ReaderContext
+// Context received earlier continues with its addressing
+// New context uses addressing of the Representation selected
+func (r *readerDASH) getContextMaker(reader readerBase, rdrCtx ReaderContext, curMpd *MPDtype, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (contextMaker, error) {
+	switch rdrCtx.(type) {
+	case nil:
+	case *readerLiveMPDUpdateContext:
+		return &readerLiveMPDUpdate{}, nil
+	case *readerLiveNumberContext:
+		return &readerLiveNumber{}, nil
+	case *readerSegmentListContext:
+		return &readerSegmentList{isLive: r.isLive}, nil
+	case *readerStaticTemplateContext:
+		return &readerStaticTemplate{}, nil
+	case *readerOnDemandContext:
+		return &readerOnDemand{indexFetcher: r.indexFetcher}, nil
+	default:
+		return nil, fmt.Errorf("ReaderContext(%T) not created by this Reader", rdrCtx)
+	}
+	mode, err := r.selectAddressing(reader, curMpd, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case addressingSegmentList:
+		return &readerSegmentList{isLive: r.isLive}, nil
+	case addressingTemplateTimeline:
+		if r.isLive {
+			return &readerLiveMPDUpdate{}, nil
+		}
+		return &readerStaticTemplate{}, nil
+	case addressingTemplateDuration:
+		if r.isLive {
+			return &readerLiveNumber{}, nil
+		}
+		return &readerStaticTemplate{}, nil
+	}
+	if r.isLive {
+		return nil, fmt.Errorf("SegmentBase addressing not supported for MPD.Type=\"dynamic\"")
+	}
+	return &readerOnDemand{indexFetcher: r.indexFetcher}, nil
+}
+
+//selectAddressing - Addressing of Representation selected in the Period to start with
+// Static - first Period
+// Live - Period active at start as per ContextOptions, or at PublishTime, or last Period
+func (r *readerDASH) selectAddressing(reader readerBase, curMpd *MPDtype, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (addressingMode, error) {
+	if len(curMpd.Period) <= 0 {
+		return addressingSegmentBase, fmt.Errorf("MPD.Period atleast ONE is required")
+	}
+	selCtx := readerBaseContext{
+		ID:             r.ID,
+		repSelector:    repSelector,
+		streamSelector: streamSelector,
+		clock:          reader.clock,
+		clockSync:      reader.clockSync,
+	}
+	period := &curMpd.Period[0]
+	if r.isLive {
+		start, _ := options.liveStartTime(curMpd, selCtx.now())
+		period, _ = selCtx.getActivePeriod(reader, curMpd, start)
+		if period == nil {
+			period, _ = selCtx.getActivePeriod(reader, curMpd, curMpd.PublishTime)
+		}
+		if period == nil {
+			period = &curMpd.Period[len(curMpd.Period)-1]
+		}
+	}
+	if err := selCtx.selectPeriod(reader, period); err != nil {
+		return addressingSegmentBase, fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	_, rp, err := selCtx.locateRepresentation(reader, period)
+	if err != nil {
+		return addressingSegmentBase, err
+	}
+	return rp.addressing(), nil
+}
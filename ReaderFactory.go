@@ -1,6 +1,7 @@
 package dashreader
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -33,10 +34,20 @@ type ReaderFactory struct {
 	curPeriod *PeriodType
 	//Base URL
 	baseURL url.URL
-	//SegmentTimeLine Present
-	isSegmentTimeline *bool
-	//$Time$based ?
-	isTimeBased *bool
+	//All MPD BaseURLs, baseURL first
+	baseURLs []BaseURLCandidate
+	//IndexFetcher - Fetcher for index (sidx) bytes, HTTPIndexFetcher if nil
+	IndexFetcher IndexFetcher
+	//XLinkResolver - Resolver for remote elements, xlink:href not resolved if nil
+	XLinkResolver *XLinkResolver
+	//ClockSync - Server clock for live MPD, synced with MPD.UTCTiming, local clock if nil
+	ClockSync *ClockSync
+	//Clock - Local clock for Readers, ReaderContexts and events, SystemClock if nil
+	Clock Clock
+	//EventDispatcher - Dispatcher of Period EventStream events of every MPD update, not used if nil
+	EventDispatcher *EventDispatcher
+	//MPD url
+	mpdURL url.URL
 }
 
 //GetDASHReader - Depending on the MPD contents find the right reader
@@ -45,16 +56,27 @@ func (f *ReaderFactory) GetDASHReader(ID string, mpdURL string, mpd *MPDtype) (R
 	if err != nil {
 		return nil, fmt.Errorf("Supplied mpdURL(%v) not correct: %w", mpdURL, err)
 	}
-	baseURL, err = AdjustURLPath(*baseURL, mpd.BaseURL, relativeBasePathURL)
+	f.mpdURL = *baseURL
+	f.defaultClocks()
+	if f.XLinkResolver != nil {
+		//Failures are posted as events, failed elements removed
+		_ = f.XLinkResolver.Resolve(mpd, f.mpdURL)
+	}
+	baseURLs, err := resolveMPDBaseURLs(mpd, *baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("MPD.%w", err)
+		return nil, err
 	}
-	f.baseURL = *baseURL
+	f.baseURL = baseURLs[0].URL
+	f.baseURLs = baseURLs
 	//Validate and read the fields to understand the type of MPD
 	err = f.validate(mpd)
 	if err != nil {
 		return nil, err
 	}
+	if f.ClockSync != nil && f.IsLive && len(mpd.UTCTiming) > 0 {
+		//Bounded by ClockSync.Timeout, failures are posted as events, previous offset kept
+		_ = f.ClockSync.Sync(context.Background(), mpd.UTCTiming, f.mpdURL)
+	}
 	//Build a Reader and respond
 	return f.makeDASHReader(ID, mpd)
 }
@@ -133,10 +155,11 @@ func (f *ReaderFactory) validate(mpd *MPDtype) error {
 	if err != nil {
 		return err
 	}
-	if !IsPresentTime(mpd.PublishTime) {
+	if IsPresentTime(mpd.PublishTime) {
+		f.PT = mpd.PublishTime
+	} else if mpd.Type != "static" {
 		return fmt.Errorf("MPD.PublishTime MUST be present")
 	}
-	f.PT = mpd.PublishTime
 	switch mpd.Type {
 	case "static":
 		err = f.validateStatic(mpd)
@@ -151,7 +174,7 @@ func (f *ReaderFactory) validate(mpd *MPDtype) error {
 func (f *ReaderFactory) validateDynamicMpd(mpd *MPDtype) error {
 	mpdUpdateMode := true
 	// Value of these fields won't change
-	if !strings.Contains(mpd.Profiles, LiveProfile) {
+	if !hasProfile(mpd.Profiles, LiveProfile, MainProfile, FullProfile) {
 		return fmt.Errorf("MPD.Profile (\"%v\") MUST include \"%v\" for MPD.Type=\"dynamic\"", mpd.Profiles, LiveProfile)
 	}
 	if !IsPresentTime(mpd.AvailabilityStartTime) {
@@ -171,18 +194,20 @@ func (f *ReaderFactory) validateDynamicMpd(mpd *MPDtype) error {
 		var pSwc time.Time
 		if !IsPresentDuration(period.Start) {
 			//Period.Start is MUST for Period 1
-			if i == 1 {
+			if i == 0 {
 				return fmt.Errorf("Period.Start MUST be present for first period")
 			}
 			if lastPeriodDuration == nil {
-				panic("lastPeriodDuration not known in second period")
+				return fmt.Errorf("Period(%v) Period.Start or previous Period.Duration MUST be present", period.Id)
 			}
+			// PSwc = lastPSwc + lastPeriodDuration
 			pSwc = lastPeriodStart.Add(*lastPeriodDuration)
 		} else {
-			// PSwc = lastPSwc + PS
+			// PSwc = AST + PS
 			v, _ := ParseDuration(period.Start)
-			pSwc = lastPeriodStart.Add(v)
+			pSwc = f.AST.Add(v)
 		}
+		lastPeriodStart = pSwc
 		if !IsPresentDuration(period.Duration) {
 			//Without MPD update
 			if !mpdUpdateMode {
@@ -199,8 +224,12 @@ func (f *ReaderFactory) validateDynamicMpd(mpd *MPDtype) error {
 			lastPeriodDuration = &v
 
 		}
-		for _, adaptSet := range period.AdaptationSet {
-			err := f.validateDynamicAdaptSet(pSwc, &lastPeriodDuration, &adaptSet)
+		resolved, err := resolvePeriod(&mpd.Period[i], f.baseURLs)
+		if err != nil {
+			return err
+		}
+		for j := range resolved.AdaptationSets {
+			err := f.validateDynamicAdaptSet(pSwc, &lastPeriodDuration, &resolved.AdaptationSets[j])
 			if err != nil {
 				return err
 			}
@@ -210,109 +239,257 @@ func (f *ReaderFactory) validateDynamicMpd(mpd *MPDtype) error {
 	return nil
 }
 
-func (f *ReaderFactory) validateDynamicAdaptSet(periodStart time.Time, periodDuration **time.Duration, adaptSet *AdaptationSetType) error {
-	timeScalePresent := false
-	segTimelinePresent := false
-	durationPresent := false
-	numberBased := false
-	timeBased := false
-	if GetBoolFromConditionalUintType(adaptSet.SegmentAlignment) == false {
-		return fmt.Errorf("AdapatationSet (%v) SegmentAlignment MUST be \"true\"", adaptSet.Id)
-	}
-	segTemplate := adaptSet.SegmentTemplate
-	if len(segTemplate.Media) <= 0 {
-		return fmt.Errorf("AdapatationSet (%v) SegmentTemplate.Media MUST be present", adaptSet.Id)
+func (f *ReaderFactory) validateDynamicAdaptSet(periodStart time.Time, periodDuration **time.Duration, adaptSet *ResolvedAdaptationSet) error {
+	if GetBoolFromConditionalUintType(adaptSet.AdaptationSet.SegmentAlignment) == false {
+		return fmt.Errorf("AdapatationSet (%v) SegmentAlignment MUST be \"true\"", adaptSet.ID)
 	}
-	if len(segTemplate.Media) > 0 {
-		if strings.Contains(segTemplate.Media, TimeToken) {
-			timeBased = true
-		}
-		if strings.Contains(segTemplate.Media, NumberToken) {
-			numberBased = true
-		}
-		if timeBased == false && numberBased == false {
-			return fmt.Errorf("AdapatationSet (%v) SegmentTemplate.Media (%v) MUST be either %v or %v", adaptSet.Id, segTemplate.Media, TimeToken, NumberToken)
+	if err := validateRepresentations(adaptSet); err != nil {
+		return err
+	}
+	//Addressing is decided per AdaptationSet
+	if adaptSet.hasAddressing(addressingSegmentList) {
+		return f.validateSegmentListAdaptSet(adaptSet)
+	}
+	for i := range adaptSet.Representations {
+		rep := &adaptSet.Representations[i]
+		if err := validateTemplateRepresentation(adaptSet, rep); err != nil {
+			return err
 		}
-		if timeBased == true && numberBased == true {
-			return fmt.Errorf("AdapatationSet (%v) SegmentTemplate.Media (%v) MUST be either %v or %v", adaptSet.Id, segTemplate.Media, TimeToken, NumberToken)
+		segTemplate := &rep.SegmentTemplate
+		if len(segTemplate.SegmentTimeline.S) > 0 && segTemplate.Timescale == 0 {
+			return fmt.Errorf("Representation(\"%v:%v\") SegmentTemplate.TimeScale (%v) MUST be present with SegmentTemplate.SegmentTimeline", adaptSet.ID, rep.ID, segTemplate.Timescale)
 		}
-		if f.isTimeBased == nil {
-			f.isTimeBased = new(bool)
-			*f.isTimeBased = timeBased
+	}
+	return nil
+}
+
+//validateRepresentations - Checks Representation@id and @bandwidth of AdaptationSet
+func validateRepresentations(adaptSet *ResolvedAdaptationSet) error {
+	for i := range adaptSet.Representations {
+		rep := &adaptSet.Representations[i]
+		if rep.ID == "" {
+			return fmt.Errorf("Representation without ID(\"%v:%v\") found", adaptSet.ID, rep.ID)
 		}
-		if *f.isTimeBased != timeBased {
-			return fmt.Errorf("Different AdaptationSets are using different URL patterns, Not Supported")
+		if rep.Bandwidth <= 0 {
+			return fmt.Errorf("Representation(\"%v:%v\") with invalid Bandwidth(%v) found", adaptSet.ID, rep.ID, rep.Bandwidth)
 		}
 	}
-	if adaptSet.SegmentTemplate.Duration != 0 {
-		durationPresent = true
+	return nil
+}
+
+//validateTemplateRepresentation - Checks SegmentTemplate of Representation for live and static
+func validateTemplateRepresentation(adaptSet *ResolvedAdaptationSet, rep *ResolvedRepresentation) error {
+	segTemplate := &rep.SegmentTemplate
+	if len(segTemplate.Media) <= 0 {
+		return fmt.Errorf("Representation(\"%v:%v\") SegmentTemplate.Media MUST be present", adaptSet.ID, rep.ID)
 	}
-	if adaptSet.SegmentTemplate.SegmentTimeline.S != nil {
-		if len(adaptSet.SegmentTemplate.SegmentTimeline.S) > 0 {
-			segTimelinePresent = true
-		}
+	media, err := validateTemplate(segTemplate)
+	if err != nil {
+		return fmt.Errorf("Representation(\"%v:%v\") %w", adaptSet.ID, rep.ID, err)
 	}
-	if segTemplate.Timescale != 0 {
-		timeScalePresent = true
+	timeBased := media.Has(templateTime)
+	numberBased := media.Has(templateNumber)
+	if timeBased == numberBased {
+		return fmt.Errorf("Representation(\"%v:%v\") SegmentTemplate.Media (%v) MUST be either %v or %v", adaptSet.ID, rep.ID, segTemplate.Media, TimeToken, NumberToken)
 	}
+	segTimelinePresent := len(segTemplate.SegmentTimeline.S) > 0
+	durationPresent := segTemplate.Duration != 0
 	if segTimelinePresent && durationPresent {
-		return fmt.Errorf("For AdaptationSet %v only ONE of SegmentTemplate.Duration(\"%v\") or SegmentTemplate.SegmentTimeline(%v items) MUST be present. ", adaptSet.Id, adaptSet.SegmentTemplate.Duration, len(adaptSet.SegmentList.SegmentTimeline.S))
+		return fmt.Errorf("Representation(\"%v:%v\") only ONE of SegmentTemplate.Duration(\"%v\") or SegmentTemplate.SegmentTimeline(%v items) MUST be present", adaptSet.ID, rep.ID, segTemplate.Duration, len(segTemplate.SegmentTimeline.S))
 	}
-	if segTimelinePresent && !timeScalePresent {
-		return fmt.Errorf("AdapatationSet (%v) SegmentTemplate.TimeScale (%v) MUST be present with SegmentTemplate.SegmentTimeline", adaptSet.Id, segTemplate.Timescale)
+	if !segTimelinePresent && !durationPresent {
+		return fmt.Errorf("Representation(\"%v:%v\") SegmentTemplate.Duration or SegmentTemplate.SegmentTimeline MUST be present", adaptSet.ID, rep.ID)
 	}
-	if f.isSegmentTimeline == nil {
-		f.isSegmentTimeline = new(bool)
-		*f.isSegmentTimeline = segTimelinePresent
+	return nil
+}
+
+func (f *ReaderFactory) validateStatic(mpd *MPDtype) error {
+	if !hasProfile(mpd.Profiles, ISOOnDemandProfile, OnDemandProfile, LiveProfile, MainProfile, FullProfile) {
+		return fmt.Errorf("MPD.Profile MUST include \"%v\" for MPD.Type=\"static\"", ISOOnDemandProfile)
 	}
-	if segTimelinePresent != *f.isSegmentTimeline {
-		return fmt.Errorf("Different AdaptationSets are using different GEN patterns, Not Supported")
+	if len(mpd.Period) <= 0 {
+		return fmt.Errorf("MPD.Period atleast ONE is required")
 	}
-	for _, rep := range adaptSet.Representation {
-		if rep.Id == "" {
-			return fmt.Errorf("Representation without ID(\"%v:%v\") found", adaptSet.Id, rep.Id)
+	timings := getPeriodTimings(readerBase{}, mpd)
+	for i := range mpd.Period {
+		period := &mpd.Period[i]
+		var periodDuration time.Duration
+		if IsPresentTime(timings[i].end) {
+			periodDuration = timings[i].end.Sub(timings[i].start)
 		}
-		if rep.Bandwidth <= 0 {
-			return fmt.Errorf("Representation(\"%v:%v\") with invalid Bandwidth(%v) found", adaptSet.Id, rep.Id, rep.Bandwidth)
+		resolved, err := resolvePeriod(period, f.baseURLs)
+		if err != nil {
+			return err
+		}
+		for j := range resolved.AdaptationSets {
+			err := f.validateStaticAdaptSet(periodDuration, &resolved.AdaptationSets[j])
+			if err != nil {
+				return err
+			}
 		}
 	}
+	f.IsLive = false
 	return nil
 }
 
-func (f *ReaderFactory) validateStatic(mpd *MPDtype) error {
-	if !strings.Contains(mpd.Profiles, OnDemandProfile) {
-		return fmt.Errorf("MPD.Profile MUST include \"%v\" for MPD.Type=\"static\"", OnDemandProfile)
+func (f *ReaderFactory) validateStaticAdaptSet(periodDuration time.Duration, adaptSet *ResolvedAdaptationSet) error {
+	if err := validateRepresentations(adaptSet); err != nil {
+		return err
+	}
+	//Addressing is decided per AdaptationSet
+	if adaptSet.hasAddressing(addressingSegmentList) {
+		return f.validateSegmentListAdaptSet(adaptSet)
+	}
+	if adaptSet.hasAddressing(addressingTemplateTimeline) || adaptSet.hasAddressing(addressingTemplateDuration) {
+		return f.validateStaticTemplateAdaptSet(periodDuration, adaptSet)
+	}
+	for i := range adaptSet.Representations {
+		rep := &adaptSet.Representations[i]
+		segBase := &rep.SegmentBase
+		indexRange := segBase.IndexRange
+		if len(indexRange) <= 0 {
+			indexRange = segBase.RepresentationIndex.Range
+		}
+		if len(indexRange) <= 0 {
+			return fmt.Errorf("Representation(\"%v:%v\") SegmentBase.IndexRange MUST be present", adaptSet.ID, rep.ID)
+		}
+		if _, _, err := parseByteRange(indexRange); err != nil {
+			return fmt.Errorf("Representation(\"%v:%v\") SegmentBase.IndexRange: %w", adaptSet.ID, rep.ID, err)
+		}
+		if len(segBase.Initialization.Range) > 0 {
+			if _, _, err := parseByteRange(segBase.Initialization.Range); err != nil {
+				return fmt.Errorf("Representation(\"%v:%v\") SegmentBase.Initialization.Range: %w", adaptSet.ID, rep.ID, err)
+			}
+		}
 	}
 	return nil
 }
 
-//makeDASHReader - depending on the read type, return DASH Reader
-func (f *ReaderFactory) makeDASHReader(ID string, mpd *MPDtype) (Reader, error) {
-	if f.IsLive {
-		if f.isSegmentTimeline != nil {
-			if *f.isSegmentTimeline {
-				if f.isTimeBased != nil {
-					ret := &readerLiveMPDUpdate{
-						readerBaseExtn: readerBaseExtn{
-							updCounter: 0,
-							readerBase: readerBase{
-								ID:       ID,
-								baseURL:  f.baseURL,
-								baseTime: f.AST,
-								isNumber: !*f.isTimeBased,
-								isTime:   *f.isTimeBased,
-							},
-						},
-					}
-					_, err := ret.Update(mpd)
-					if err != nil {
-						return nil, err
-					}
-					return ret, nil
+//validateTemplate - Checks identifiers of SegmentTemplate@media and initialization
+// Return:
+//   1: parsed SegmentTemplate@media
+//   2: error
+func validateTemplate(segTemplate *SegmentTemplateType) (*URLTemplate, error) {
+	media, err := ParseURLTemplate(segTemplate.Media)
+	if err != nil {
+		return nil, fmt.Errorf("SegmentTemplate.Media: %w", err)
+	}
+	initSourceURL, _ := getTemplateInitialization(segTemplate)
+	init, err := ParseURLTemplate(initSourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("SegmentTemplate.Initialization: %w", err)
+	}
+	//Only $RepresentationID$ and $Bandwidth$ for Initialization
+	for _, identifier := range []string{templateNumber, templateTime, templateSubNumber} {
+		if init.Has(identifier) {
+			return nil, fmt.Errorf("SegmentTemplate.Initialization (%v) MUST NOT use $%v$", initSourceURL, identifier)
+		}
+	}
+	return media, nil
+}
+
+func (f *ReaderFactory) validateStaticTemplateAdaptSet(periodDuration time.Duration, adaptSet *ResolvedAdaptationSet) error {
+	for i := range adaptSet.Representations {
+		rep := &adaptSet.Representations[i]
+		if err := validateTemplateRepresentation(adaptSet, rep); err != nil {
+			return err
+		}
+		if _, err := buildTemplateSegments(&rep.SegmentTemplate, periodDuration); err != nil {
+			return fmt.Errorf("Representation(\"%v:%v\") %v", adaptSet.ID, rep.ID, err)
+		}
+	}
+	return nil
+}
+
+func (f *ReaderFactory) validateSegmentListAdaptSet(adaptSet *ResolvedAdaptationSet) error {
+	for i := range adaptSet.Representations {
+		rep := &adaptSet.Representations[i]
+		segList := &rep.SegmentList
+		if len(segList.SegmentURL) <= 0 {
+			return fmt.Errorf("Representation(\"%v:%v\") SegmentList.SegmentURL MUST be present", adaptSet.ID, rep.ID)
+		}
+		segTimelinePresent := len(segList.SegmentTimeline.S) > 0
+		if segTimelinePresent && segList.Duration != 0 {
+			return fmt.Errorf("Representation(\"%v:%v\") only ONE of SegmentList.Duration(\"%v\") or SegmentList.SegmentTimeline(%v items) MUST be present", adaptSet.ID, rep.ID, segList.Duration, len(segList.SegmentTimeline.S))
+		}
+		if !segTimelinePresent && segList.Duration == 0 && len(segList.SegmentURL) > 1 {
+			return fmt.Errorf("Representation(\"%v:%v\") SegmentList.Duration or SegmentList.SegmentTimeline MUST be present", adaptSet.ID, rep.ID)
+		}
+		for j, segURL := range segList.SegmentURL {
+			if len(segURL.Media) <= 0 && len(segURL.MediaRange) <= 0 {
+				return fmt.Errorf("Representation(\"%v:%v\") SegmentURL(%v) MUST have media or mediaRange", adaptSet.ID, rep.ID, j)
+			}
+			if len(segURL.MediaRange) > 0 {
+				if _, _, err := parseByteRange(segURL.MediaRange); err != nil {
+					return fmt.Errorf("Representation(\"%v:%v\") SegmentURL(%v).MediaRange: %w", adaptSet.ID, rep.ID, j, err)
 				}
 			}
+		}
+	}
+	return nil
+}
 
+//hasProfile - Checks if any of the profiles is listed in MPD@profiles
+func hasProfile(profiles string, wanted ...string) bool {
+	for _, profile := range strings.Split(profiles, ",") {
+		profile = strings.TrimSpace(profile)
+		for _, w := range wanted {
+			if profile == w {
+				return true
+			}
 		}
 	}
-	return nil, fmt.Errorf("Reader not found")
+	return false
+}
+
+//defaultClocks - Clock of the Reader for the helpers without one
+// ClockSync, XLinkResolver and EventDispatcher time with Clock if their Clock is nil
+func (f *ReaderFactory) defaultClocks() {
+	if f.ClockSync != nil && f.ClockSync.Clock == nil {
+		f.ClockSync.Clock = f.Clock
+	}
+	if f.XLinkResolver != nil && f.XLinkResolver.Clock == nil {
+		f.XLinkResolver.Clock = f.Clock
+	}
+	if f.EventDispatcher != nil {
+		//Events timed with the clock of the Reader
+		if f.EventDispatcher.Clock == nil {
+			f.EventDispatcher.Clock = f.Clock
+		}
+		if f.EventDispatcher.ClockSync == nil {
+			f.EventDispatcher.ClockSync = f.ClockSync
+		}
+	}
+}
+
+//makeDASHReader - return DASH Reader, addressing is decided per ReaderContext
+func (f *ReaderFactory) makeDASHReader(ID string, mpd *MPDtype) (Reader, error) {
+	indexFetcher := f.IndexFetcher
+	if indexFetcher == nil {
+		indexFetcher = HTTPIndexFetcher{}
+	}
+	ret := &readerDASH{
+		readerBaseExtn: readerBaseExtn{
+			updCounter: 0,
+			readerBase: readerBase{
+				ID:        ID,
+				baseURL:   f.baseURL,
+				baseURLs:  f.baseURLs,
+				mpdURL:    f.mpdURL,
+				xlink:     f.XLinkResolver,
+				onRequest: &onRequestPeriods{},
+				clock:     f.Clock,
+				clockSync: f.ClockSync,
+				events:    f.EventDispatcher,
+				baseTime:  f.AST,
+			},
+		},
+		isLive:       f.IsLive,
+		indexFetcher: indexFetcher,
+	}
+	_, err := ret.Update(mpd)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
@@ -1,6 +1,8 @@
 package dashreader_test
 
 import (
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/anbangisak/dashreader"
@@ -24,3 +26,54 @@ func TestFactory(t *testing.T) {
 		t.Logf("================ %v =================", file)
 	}
 }
+
+func TestFactoryInvalidRepresentation(t *testing.T) {
+	tests := []struct {
+		file string
+		old  string
+		new  string
+		exp  string
+	}{
+		{"test/live_number_default.mpd", "bandwidth=\"300000\"", "bandwidth=\"0\"", "invalid Bandwidth"},
+		{"test/live_seglist.mpd", "id=\"V300\"", "", "without ID"},
+		{"test/static_template.mpd", "bandwidth=\"64000\"", "", "invalid Bandwidth"},
+		{"test/static_seglist.mpd", "id=\"A64\"", "", "without ID"},
+		{"test/ondemand_segbase.mpd", "bandwidth=\"300000\"", "", "invalid Bandwidth"},
+	}
+	for _, test := range tests {
+		data, err := ioutil.ReadFile(test.file)
+		if err != nil {
+			t.Fatalf("Error reading %s:%v", test.file, err)
+		}
+		mpd, err := dashreader.ReadMPDFromStream(strings.NewReader(strings.Replace(string(data), test.old, test.new, 1)))
+		if err != nil {
+			t.Fatalf("Error reading %s:%v", test.file, err)
+		}
+		factory := dashreader.ReaderFactory{}
+		_, err = factory.GetDASHReader("client1", "http://127.0.0.1/"+test.file, mpd)
+		if err == nil || !strings.Contains(err.Error(), test.exp) {
+			t.Errorf("%v: Error Exp: %v Act: %v", test.file, test.exp, err)
+		}
+	}
+}
+
+func TestFactoryOnDemandProfiles(t *testing.T) {
+	file := "test/ondemand_segbase.mpd"
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	for _, profile := range []string{dashreader.ISOOnDemandProfile, dashreader.OnDemandProfile} {
+		mpd, err := dashreader.ReadMPDFromStream(strings.NewReader(strings.Replace(string(data), dashreader.ISOOnDemandProfile, profile, 1)))
+		if err != nil {
+			t.Fatalf("Error reading %s:%v", file, err)
+		}
+		if mpd.Profiles != profile {
+			t.Fatalf("Profiles Exp: %v Act: %v", profile, mpd.Profiles)
+		}
+		factory := dashreader.ReaderFactory{}
+		if _, err = factory.GetDASHReader("client1", "http://127.0.0.1/"+file, mpd); err != nil {
+			t.Errorf("%v: Error getting reader : %v", profile, err)
+		}
+	}
+}
@@ -2,12 +2,14 @@ package dashreader
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,7 +21,7 @@ import (
 // r - Must implement the io.Reader interface.
 func ReadMPDFromStream(r io.Reader) (*MPDtype, error) {
 	var mpd MPDtype
-	d := xml.NewDecoder(r)
+	d := newMPDDecoder(r, "", nil)
 	err := d.Decode(&mpd)
 	if err != nil {
 		return nil, err
@@ -27,6 +29,124 @@ func ReadMPDFromStream(r io.Reader) (*MPDtype, error) {
 	return &mpd, nil
 }
 
+//newMPDDecoder - Decoder of MPD elements, Event content kept as XML in EventType.Items,
+// inheritedSegmentAttrs written on Segment elements
+// Parameters:
+//   1: document
+//   2: namespace of elements without one
+//   3: inheritedSegmentAttrs in effect where the document is placed, nil for MPD
+func newMPDDecoder(r io.Reader, defaultSpace string, inherited map[segmentAttr]string) *xml.Decoder {
+	d := xml.NewDecoder(r)
+	d.DefaultSpace = defaultSpace
+	return xml.NewTokenDecoder(&segmentAttrReader{
+		r:      &eventContentReader{d: d},
+		levels: []map[segmentAttr]string{inherited},
+	})
+}
+
+//eventContentReader - Replaces every child element of Event with an element of same name
+// having the XML of the child as text. EventType.Items only keeps the text of child elements.
+type eventContentReader struct {
+	d       *xml.Decoder
+	depth   int         //depth of current element
+	event   int         //depth of Event being read, 0 if none
+	pending []xml.Token //tokens to return before reading more
+}
+
+//Token - Next token, Event content read whole
+func (r *eventContentReader) Token() (xml.Token, error) {
+	if len(r.pending) > 0 {
+		token := r.pending[0]
+		r.pending = r.pending[1:]
+		return token, nil
+	}
+	token, err := r.d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case xml.StartElement:
+		if r.event > 0 && r.depth == r.event {
+			content, err := readElementXML(r.d, t)
+			if err != nil {
+				return nil, err
+			}
+			start := xml.StartElement{Name: t.Name}
+			r.pending = []xml.Token{xml.CharData(content), start.End()}
+			return start, nil
+		}
+		r.depth++
+		if t.Name.Space == mpdNamespace && t.Name.Local == "Event" {
+			r.event = r.depth
+		}
+	case xml.EndElement:
+		if r.depth == r.event {
+			r.event = 0
+		}
+		r.depth--
+	}
+	return token, nil
+}
+
+//readElementXML - XML of element, start already read
+// Element names carry their namespace, xmlns attributes are dropped
+func readElementXML(d *xml.Decoder, start xml.StartElement) ([]byte, error) {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	depth := 0
+	token := xml.Token(start)
+	for {
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			attrs := make([]xml.Attr, 0, len(t.Attr))
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			t.Attr = attrs
+			token = t
+		case xml.EndElement:
+			depth--
+		case xml.ProcInst, xml.Directive:
+			token = nil
+		}
+		if token != nil {
+			if err := e.EncodeToken(token); err != nil {
+				return nil, err
+			}
+		}
+		if depth == 0 {
+			break
+		}
+		var err error
+		token, err = d.Token()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//MarshalXML - Encode Event with Items written as XML, as kept by newMPDDecoder
+func (t *EventType) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type T EventType
+	var layout struct {
+		*T
+		Content string `xml:",innerxml"`
+	}
+	event := *t
+	event.Items = nil
+	layout.T = (*T)(&event)
+	layout.Content = strings.Join(t.Items, "")
+	return e.EncodeElement(layout, start)
+}
+
 // ReadMPDFromFile - Reads from a File strored into an MPD object returned.
 func ReadMPDFromFile(filename string) (*MPDtype, error) {
 	f, err := os.Open(filename)
@@ -122,3 +242,31 @@ func AdjustURLPath(refURL url.URL, adjustURL []BaseURLType, relativePath string)
 	}
 	return &refURL, nil
 }
+
+//parseByteRange - Parses "first-last" byte range
+func parseByteRange(byteRange string) (uint64, uint64, error) {
+	parts := strings.SplitN(strings.TrimSpace(byteRange), "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("ByteRange(%v) MUST be first-last", byteRange)
+	}
+	first, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ByteRange(%v) first not correct: %w", byteRange, err)
+	}
+	last, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ByteRange(%v) last not correct: %w", byteRange, err)
+	}
+	if last < first {
+		return 0, 0, fmt.Errorf("ByteRange(%v) last MUST NOT be before first", byteRange)
+	}
+	return first, last, nil
+}
+
+//ticksToDuration - Convert ticks in timescale to time.Duration
+func ticksToDuration(ticks uint64, timescale uint) time.Duration {
+	if timescale == 0 {
+		timescale = 1
+	}
+	return time.Duration(float64(ticks)*1000000/float64(timescale)) * time.Microsecond
+}
@@ -15,11 +15,11 @@ func TestAdjustURLPath(t *testing.T) {
 	mpdURL := "http://127.0.0.1/default.mpd"
 	refURL, err := url.Parse(mpdURL)
 	if err != nil {
-		t.Errorf("Supplied mpdURL(%v) not correct: %w", mpdURL, err)
+		t.Errorf("Supplied mpdURL(%v) not correct: %v", mpdURL, err)
 	}
 	newURL, err = dashreader.AdjustURLPath(*refURL, []dashreader.BaseURLType{}, "./")
 	if err != nil {
-		t.Errorf("Base Path test failed : %w", err)
+		t.Errorf("Base Path test failed : %v", err)
 	}
 	exp = "http://127.0.0.1/"
 	if newURL.String() != exp {
@@ -32,7 +32,7 @@ func TestAdjustURLPath(t *testing.T) {
 	}
 	newURL, err = dashreader.AdjustURLPath(*refURL, adjustURL, "")
 	if err != nil {
-		t.Errorf("No Change test failed : %w", err)
+		t.Errorf("No Change test failed : %v", err)
 	}
 	exp = "http://127.0.0.1/default.mpd"
 	if newURL.String() != exp {
@@ -45,7 +45,7 @@ func TestAdjustURLPath(t *testing.T) {
 	}
 	newURL, err = dashreader.AdjustURLPath(*refURL, adjustURL, "")
 	if err != nil {
-		t.Errorf("Replace test failed : %w", err)
+		t.Errorf("Replace test failed : %v", err)
 	}
 	exp = "http://127.0.0.1/NewPath"
 	if newURL.String() != exp {
@@ -58,7 +58,7 @@ func TestAdjustURLPath(t *testing.T) {
 	}
 	newURL, err = dashreader.AdjustURLPath(*refURL, adjustURL, "")
 	if err != nil {
-		t.Errorf("Append test failed : %w", err)
+		t.Errorf("Append test failed : %v", err)
 	}
 	exp = "http://127.0.0.1/NewPath"
 	if newURL.String() != exp {
@@ -12,12 +12,19 @@ import (
 type ChunkURL struct {
 	//ChunkURL - Actual URL
 	ChunkURL url.URL
-	//Range - Range Header
+	//Range - Range Header (first-last bytes), empty for full resource
 	Range string
 	//FetchAt - WallClock Time when URL becomes available
+	//ZERO for static MPD (available at once)
 	FetchAt time.Time
 	//Duration - Duration of content available in this URL
 	Duration time.Duration
+	//CompleteAt - WallClock Time when segment is completely available
+	//ZERO for static MPD, same as FetchAt unless AvailabilityTimeOffset
+	CompleteAt time.Time
+	//Chunked - Segment still being produced (LL-DASH, @availabilityTimeComplete="false")
+	//request with chunked transfer encoding, response grows till CompleteAt
+	Chunked bool
 }
 
 //ChunkURLChannel - Channel of Chunk URLs
@@ -53,6 +60,15 @@ type ReaderContext interface {
 	GetLang() string
 	//GetCodecs - Codecs of content
 	GetCodecs() string
+
+	//FetchFailed - Report failure to fetch a ChunkURL
+	//-- Location (@serviceLocation) of the url is not used again
+	//-- URLs returned later are from the next BaseURL as per DVB priority/weight
+	// Parameters:
+	//   ChunkURL which failed
+	// Return:
+	//   error if url is not from a BaseURL or no other BaseURL is available
+	FetchFailed(ChunkURL) error
 }
 
 //Reader - Read any DASH file and get Playback URLs
@@ -65,15 +81,38 @@ type Reader interface {
 	//   2: error
 	Update(*MPDtype) (bool, error)
 
+	//UpdateFrom -
+	// BaseURLs are rebased on the url (redirect or MPD.Location)
+	// Parameters:
+	//   1: MPD read
+	//   2: url the MPD was fetched from (after redirects)
+	// Return:
+	//   1: MPD Updated - PublishTime Updated?
+	//   2: error
+	UpdateFrom(*MPDtype, string) (bool, error)
+
 	//MakeDASHReaderContext - Makes Reader Context
 	// Parameters:
 	//   1: Context received earlier... if first time pass nil
 	//   2: StreamSelector for the ContentType to select AdaptationSet
 	//   3: RepresentationSelector ... selector for Representation
+	//   4: ContextOptions ... live start policy, target latency
 	// Return:
 	//   1: Context for current AdaptationSet,Representation
 	//   2: error
-	MakeDASHReaderContext(ReaderContext, StreamSelector, RepresentationSelector) (ReaderContext, error)
+	MakeDASHReaderContext(ReaderContext, StreamSelector, RepresentationSelector, ...ContextOption) (ReaderContext, error)
+
+	//SwitchRepresentation - Move ReaderContext to another Representation of its AdaptationSet
+	//-- init of the Representation is returned next
+	//-- segments continue at the next segment boundary after the last URL returned
+	//-- RepresentationSelector of later MakeDASHReaderContext calls applies again
+	// Parameters:
+	//   1: Context received earlier
+	//   2: Representation@id in the same AdaptationSet
+	// Return:
+	//   1: Context for the Representation
+	//   2: error, Context received is unchanged
+	SwitchRepresentation(ReaderContext, string) (ReaderContext, error)
 
 	//SetStatzAgg - Set StatzAgg for event forwarding
 	// Parameters;
@@ -81,4 +120,11 @@ type Reader interface {
 	// Return:
 	//   NA
 	SetStatzAgg(statzAgg statzagg.StatzAgg)
+
+	//SetClock - Set local clock for ReaderContexts made later and events
+	// Parameters;
+	//   Clock, SystemClock if nil
+	// Return:
+	//   NA
+	SetClock(clock Clock)
 }
@@ -1,71 +1,64 @@
 package dashreader
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"reflect"
 )
 
-//readerLiveMPDUpdate - Implement Reader of MPD
+//readerLiveMPDUpdate - Implement addressing of MPD
 //  * Live
 //  * MPD Updating
 //  * SegmentTimeLine
 //  * $Time$ based url
 //  * $Number$ based url
+//  * Multiple Periods, moves to next Period at end of timeline
 type readerLiveMPDUpdate struct {
-	readerBaseExtn
 }
 
-//MakeDASHReaderContext - Makes Reader Context
+//makeContext - Makes Reader Context
 // Parameters:
-//   1: Context received earlier... if first time pass nil
-//   2: StreamSelector for the ContentType to select AdaptationSet
-//   3: RepresentationSelector ... selector for Representation
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier... if first time pass nil
+//   5: StreamSelector for the ContentType to select AdaptationSet
+//   6: RepresentationSelector ... selector for Representation
+//   7: ContextOptions
 // Return:
 //   1: Context for current AdaptationSet,Representation
 //   2: error
-func (r *readerLiveMPDUpdate) MakeDASHReaderContext(rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector) (ReaderContext, error) {
-	var curContext readerLiveMPDUpdateContext
-	if rdrCtx != nil {
-		v := rdrCtx.(*readerLiveMPDUpdateContext)
-		curContext = *v
-	} else {
-		curContext = readerLiveMPDUpdateContext{
-			readerBaseContext: readerBaseContext{
-				ID:             r.ID,
-				adaptSetID:     0,
-				repID:          "",
-				updCounter:     0,
-				repSelector:    repSelector,
-				streamSelector: streamSelector,
-				StatzAgg:       r.StatzAgg,
-			},
-		}
-	}
-	curMpd, updCounter := r.readerBaseExtn.checkUpdate()
-	if reflect.TypeOf(curContext.repSelector) != reflect.TypeOf(repSelector) {
-		curContext.repSelector = repSelector
-	}
-	if reflect.TypeOf(curContext.streamSelector) != reflect.TypeOf(streamSelector) {
-		curContext.streamSelector = streamSelector
+func (r *readerLiveMPDUpdate) makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error) {
+	ctx, err := prepareContext(reader, rdrCtx, &readerLiveMPDUpdateContext{}, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
 	}
+	curContext := ctx.(*readerLiveMPDUpdateContext)
 	if rdrCtx != nil {
-		if updCounter == curContext.updCounter {
-			//no update
-			return &curContext, nil
+		if updCounter != curContext.updCounter {
+			err = curContext.adjustRepUpdate(reader, curMpd)
 		}
-		err := curContext.adjustRepUpdate(r.readerBase, curMpd)
 		if err == nil {
-			return &curContext, nil
+			curContext.updCounter = updCounter
+			//Position kept, Representation switched if selection changed
+			curContext.reselect(reader, curMpd, curContext)
+			return curContext, nil
 		}
 		//Gaps TBD
 		log.Printf("Adjust Rep Update Fail : %v", err)
 	}
 	//Incoming context is nil = new context
 	//Locate the livePoint
-	err := curContext.livePointLocate(r.readerBase, curMpd)
+	err = curContext.livePointLocate(reader, curMpd)
+	var windowErr *SeekOutOfWindowError
+	if errors.As(err, &windowErr) {
+		//Positioned at the edge of time-shift window
+		curContext.updCounter = updCounter
+		return curContext, err
+	}
 	if err != nil {
-		return &curContext, fmt.Errorf("LivePoint Locate Failed: %w", err)
+		return curContext, fmt.Errorf("LivePoint Locate Failed: %w", err)
 	}
-	return &curContext, nil
+	curContext.updCounter = updCounter
+	return curContext, nil
 }
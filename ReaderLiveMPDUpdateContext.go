@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/eswarantg/statzagg"
@@ -34,11 +32,16 @@ type readerLiveMPDUpdateContext struct {
 	baseWcTime time.Time           //BaseTime for SegmentTimeline
 	timeline   SegmentTimelineType //SegmentTimeline - Active
 	timescale  uint                //timescale - Ticks per sec
-	isNumber   bool                //Number pattern
-	isTime     bool                //Time pattern
 	initURL    url.URL             //url for init
 	initRange  string              //range Header for init
-	baseURL    url.URL             //Base url for chunk
+	baseURL    url.URL             //Base url of Representation
+	media      *URLTemplate        //SegmentTemplate@media
+	ato        time.Duration       //AvailabilityTimeOffset
+	atc        bool                //AvailabilityTimeComplete, false for LL-DASH chunks
+
+	reader   readerBase //Reader fixed values for Period transition
+	mpd      *MPDtype   //MPD the timeline is from
+	periodID string     //Period@id being served
 
 	binitURLServed       bool   //init URL pending to be returned
 	curSegTimeLineEntry  int    //cur entry to generate next url from
@@ -72,7 +75,7 @@ func (c *readerLiveMPDUpdateContext) moveToNext(wallClock *time.Time) livePointE
 					values[0] = wallClock
 					values[1] = entryStartTime
 					c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-						EventClock: time.Now(),
+						EventClock: timeNow(c.clock),
 						ID:         c.ID,
 						Name:       EvtMPDTimelineNoLivePointEntries,
 						Values:     values,
@@ -117,7 +120,7 @@ func (c *readerLiveMPDUpdateContext) moveToNext(wallClock *time.Time) livePointE
 						values[0] = c.elapsedDurationTicks
 						values[1] = entry.T
 						c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-							EventClock: time.Now(),
+							EventClock: timeNow(c.clock),
 							ID:         c.ID,
 							Name:       EvtMPDTimelineGapFilled,
 							Values:     values,
@@ -144,7 +147,7 @@ func (c *readerLiveMPDUpdateContext) moveToNext(wallClock *time.Time) livePointE
 					values[0] = wallClock
 					values[1] = entryStartTime
 					c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-						EventClock: time.Now(),
+						EventClock: timeNow(c.clock),
 						ID:         c.ID,
 						Name:       EvtMPDTimelineInFuture,
 						Values:     values,
@@ -162,11 +165,13 @@ func (c *readerLiveMPDUpdateContext) moveToNext(wallClock *time.Time) livePointE
 			}
 			//log.Printf("%v Start%v <= End:%v <= WC:%v", c.ID, entryStartTime.UTC(), entryEndTime.UTC(), wallClock.UTC())
 			c.curEntry++
+			c.chunkNumber++
 			c.chunkTimeTicks += entry.D
 			continue //Check next record
 		}
 		//Just next record
 		c.curEntry++
+		c.chunkNumber++
 		c.chunkTimeTicks += entry.D
 		//log.Printf("%v Current moved to %v", c.ID, entryStartTime.UTC())
 		//wallClock is not nil
@@ -174,264 +179,251 @@ func (c *readerLiveMPDUpdateContext) moveToNext(wallClock *time.Time) livePointE
 	}
 }
 
-func (c *readerLiveMPDUpdateContext) getActivePeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, time.Time) {
-	pSwc := reader.baseTime
-	//log.Printf("Base pSwc : %v", pSwc.UTC())
-	pEwc := time.Time{}
-	//Use PT as the base time to compute live point ref
-	curWc := curMpd.PublishTime
-	//log.Printf("PublishTime : %v", curWc.UTC())
-	for _, period := range curMpd.Period {
-		var v time.Duration
-		if IsPresentDuration(period.Start) {
-			// PSwc = lastPSwc + PS
-			v, _ = ParseDuration(period.Start)
-			pSwc = pSwc.Add(v)
-			//log.Printf("New pSwc : %v", pSwc.UTC())
-		}
-		if curWc.Before(pSwc) {
-			//log.Printf("WallClock (%v) < Period Start (%v)", curWc.UTC(), pSwc.UTC())
-			break
-		}
-		if curWc.After(pSwc) {
-			if IsPresentDuration(period.Duration) {
-				v, _ = ParseDuration(period.Duration)
-				pEwc = pSwc.Add(v)
-				//log.Printf("New pEwc : %v", pEwc.UTC())
-				if pEwc.Before(curWc) {
-					//The entire period is before curWc
-					continue
-				}
-			}
-		}
-		//curWc.Equal(pSwc)
-		//pSwc >= curWc && No Duration present
-		//pSwc >= curWc  < pEwc
-		return &period, pSwc
+//getBaseWcTime - WallClock reference for SegmentTimeline of Representation
+func getBaseWcTime(pSwc time.Time, segTemplate *SegmentTemplateType, rp *ResolvedRepresentation) time.Time {
+	baseWcTime := pSwc
+	//Offset any PresentationTimeOffset
+	if rp.SegmentBase.PresentationTimeOffset > 0 {
+		baseWcTime = baseWcTime.Add(-1 * time.Duration(float64(rp.SegmentBase.PresentationTimeOffset)*1000000/float64(segTemplate.Timescale)) * time.Microsecond)
 	}
-	if c.StatzAgg != nil {
-		values := make([]interface{}, 1)
-		values[0] = len(curMpd.Period)
-		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
-			EventClock: time.Now(),
-			ID:         c.ID,
-			Name:       EvtMPDNoActivePeriod,
-			Values:     values,
-		})
+	if segTemplate.PresentationTimeOffset > 0 {
+		baseWcTime = baseWcTime.Add(-1 * time.Duration(float64(segTemplate.PresentationTimeOffset)*1000000/float64(segTemplate.Timescale)) * time.Microsecond)
+	}
+	//AvailabilityTimeOffset changes availability, not the timeline
+	return baseWcTime
+}
+
+//loadURLs - Initialize init and media urls for Representation
+func (c *readerLiveMPDUpdateContext) loadURLs(segTemplate *SegmentTemplateType, rpBaseURL url.URL) error {
+	var err error
+	c.initURL, c.initRange, c.media, err = c.loadTemplate(segTemplate, rpBaseURL)
+	if err != nil {
+		return err
 	}
-	return nil, pSwc
+	//init to be supplied if present
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	c.baseURL = rpBaseURL
+	return nil
 }
 
 //adjustRepUpdate - Handle Rep update
 func (c *readerLiveMPDUpdateContext) adjustRepUpdate(reader readerBase, curMpd *MPDtype) error {
-	var periodBaseURL url.URL
-	periodBaseURL = reader.baseURL
-
 	//Use PT as the base time to compute live point ref
 	curWc := curMpd.PublishTime
 	//log.Printf("PublishTime : %v", curWc.UTC())
-	period, pSwc := c.getActivePeriod(reader, curMpd)
+	period, timing := getPeriodByID(reader, curMpd, c.periodID)
 	if period == nil {
-		return fmt.Errorf("Unable to find Active Period")
+		return fmt.Errorf("Period(%v) not present in MPD", c.periodID)
+	}
+	adapt, rp, err := c.locateRepresentation(reader, period)
+	if err != nil {
+		return err
+	}
+	segTemplate := &rp.SegmentTemplate
+	baseWcTime := getBaseWcTime(timing.start, segTemplate, rp)
+	//Check if BaseWCTime is not modified
+	if baseWcTime != c.baseWcTime {
+		return fmt.Errorf("BaseTime mismatch (%v,%v,%v) C %v != Wc %v)", period.Id, adapt.ID, rp.ID, c.baseWcTime, baseWcTime)
+	}
+	//Nothing has changed
+	//update only required field
+	if err := c.loadURLs(segTemplate, rp.BaseURL); err != nil {
+		return err
+	}
+	c.ato = time.Duration(segTemplate.AvailabilityTimeOffset * float64(time.Second))
+	c.atc = segTemplate.AvailabilityTimeComplete
+	entryStartTime := c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
+	c.mpd = curMpd
+	c.timeline = segTemplate.SegmentTimeline
+	c.curSegTimeLineEntry = 0
+	c.curEntry = 0
+	c.chunkNumber = 0
+	c.chunkTimeTicks = 0
+	c.elapsedDurationTicks = 0
+	c.startNumber = segTemplate.StartNumber
+	if entryStartTime.Before(curWc) {
+		entryStartTime = entryStartTime.UTC().Add(1 * time.Microsecond)
+		//log.Printf("Move (%v) To WallClock entryStartTime %v Begin %v", c.ID, curWc.UTC(), entryStartTime)
+		curWc = entryStartTime
 	}
+	livePointErr := c.moveToNext(&curWc)
+	//log.Printf("Update %v %v", livePointErr.errType, livePointErr.err)
+	switch livePointErr.errType {
+	case livePointNoEntry:
+		//No Entry ... update without new Timeline addition
+		livePointErr.err = nil
+	case livePointFutureEntry:
+		//Entry in future
+		livePointErr.err = nil
+	}
+	return livePointErr.err
+}
 
-	tURL, err := AdjustURLPath(periodBaseURL, period.BaseURL, "")
+//loadRepresentation - Initialize the values from selected Representation
+func (c *readerLiveMPDUpdateContext) loadRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, pSwc time.Time) error {
+	adapt, rp, err := c.locateRepresentation(reader, period)
 	if err != nil {
-		return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
+		return err
 	}
-	periodBaseURL = *tURL
-	for _, adapt := range period.AdaptationSet {
-		if adapt.ContentType != c.streamSelector.ContentType || adapt.Id != c.adaptSetID {
-			continue
+	segTemplate := &rp.SegmentTemplate
+	c.setContentFields(adapt, rp)
+	//Initialize the values
+	c.reader = reader
+	c.mpd = curMpd
+	c.periodID = period.Id
+	c.timescale = segTemplate.Timescale
+	c.ato = time.Duration(segTemplate.AvailabilityTimeOffset * float64(time.Second))
+	c.atc = segTemplate.AvailabilityTimeComplete
+	c.baseWcTime = getBaseWcTime(pSwc, segTemplate, rp)
+	//log.Printf("baseWcTime : %v", c.baseWcTime.UTC())
+	c.timeline = segTemplate.SegmentTimeline
+	if err := c.loadURLs(segTemplate, rp.BaseURL); err != nil {
+		return err
+	}
+	c.curSegTimeLineEntry = 0
+	c.curEntry = 0
+	c.chunkNumber = 0
+	c.chunkTimeTicks = 0
+	c.elapsedDurationTicks = 0
+	c.startNumber = segTemplate.StartNumber
+	return nil
+}
+
+//servedPeriod - Period with Period@id being served
+func (c *readerLiveMPDUpdateContext) servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming) {
+	return getPeriodByID(reader, curMpd, c.periodID)
+}
+
+//switchRepresentation - Serve repID from the entry containing start of next segment
+func (c *readerLiveMPDUpdateContext) switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error {
+	prev := *c
+	next := c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
+	c.repID = repID
+	if err := c.loadRepresentation(reader, curMpd, period, timing.start); err != nil {
+		*c = prev
+		return err
+	}
+	//Inside the segment, its start is also end of previous one
+	startWc := next.Add(1 * time.Microsecond)
+	livePointErr := c.moveToNext(&startWc)
+	switch livePointErr.errType {
+	case livePointNoEntry, livePointFutureEntry:
+		//Position not in timeline of Representation, continue from the entry reached
+		livePointErr.err = nil
+	}
+	return livePointErr.err
+}
+
+//timelineSpans - WallClock start and end of each segment of the timeline
+func (c *readerLiveMPDUpdateContext) timelineSpans() [][2]time.Time {
+	toWc := func(ticks uint64) time.Time {
+		return c.baseWcTime.Add(time.Duration(float64(ticks)*1000000/float64(c.timescale)) * time.Microsecond)
+	}
+	var ret [][2]time.Time
+	var elapsed uint64
+	for _, entry := range c.timeline.S {
+		if entry.T != 0 {
+			elapsed = entry.T
 		}
-		//Found matching AdaptationSet
-		tURL, err := AdjustURLPath(periodBaseURL, adapt.BaseURL, "")
-		if err != nil {
-			return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
-		}
-		adaptBaseURL := *tURL
-		for _, rp := range adapt.Representation {
-			if rp.Id != c.repID {
-				continue
-			}
-			//Found matching Representation
-			tURL, err := AdjustURLPath(adaptBaseURL, rp.BaseURL, "")
-			if err != nil {
-				return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
-			}
-			rpBaseURL := *tURL
-			baseWcTime := pSwc
-			//Offset any PresentationTimeOffset
-			if rp.SegmentBase.PresentationTimeOffset > 0 {
-				baseWcTime = baseWcTime.Add(-1 * time.Duration(float64(rp.SegmentBase.PresentationTimeOffset)*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond)
-			}
-			if adapt.SegmentTemplate.PresentationTimeOffset > 0 {
-				baseWcTime = baseWcTime.Add(-1 * time.Duration(float64(adapt.SegmentTemplate.PresentationTimeOffset)*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond)
-			}
-			//Offset any AvailabilityTimeOffset
-			if adapt.SegmentTemplate.AvailabilityTimeOffset > 0 {
-				baseWcTime = baseWcTime.Add(time.Duration(adapt.SegmentTemplate.AvailabilityTimeOffset*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond)
-			}
-			//Check if BaseWCTime is not modified
-			if baseWcTime != c.baseWcTime {
-				return fmt.Errorf("BaseTime mismatch (%v,%v,%v) C %v != Wc %v)", period.Id, adapt.Id, rp.Id, c.baseWcTime, baseWcTime)
-			}
-			//Nothing has changed
-			//update only required field
-			if len(adapt.SegmentTemplate.Initialization.SourceURL) > 0 {
-				temp := strings.ReplaceAll(adapt.SegmentTemplate.Initialization.SourceURL, "$RepresentationID$", string(rp.Id))
-				v, err := AdjustURLPath(rpBaseURL, []BaseURLType{}, temp)
-				if err != nil {
-					return fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.Id, err)
-				}
-				c.initURL = *v
-				c.initRange = adapt.SegmentTemplate.Initialization.Range
-				c.binitURLServed = false //to be supplied
-			} else {
-				c.initURL = url.URL{}
-				c.initRange = ""
-				c.binitURLServed = true //mark already supplied so that it is not done
-			}
-			temp := strings.ReplaceAll(adapt.SegmentTemplate.Media, "$RepresentationID$", string(rp.Id))
-			v, err := AdjustURLPath(rpBaseURL, []BaseURLType{}, temp)
-			if err != nil {
-				return fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.Id, err)
-			}
-			entryStartTime := c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
-			c.timeline = adapt.SegmentTemplate.SegmentTimeline
-			c.baseURL = *v
-			c.curSegTimeLineEntry = 0
-			c.curEntry = 0
-			c.chunkNumber = 0
-			c.chunkTimeTicks = 0
-			c.elapsedDurationTicks = 0
-			c.startNumber = adapt.SegmentTemplate.StartNumber
-			if entryStartTime.Before(curWc) {
-				entryStartTime = entryStartTime.UTC().Add(1 * time.Microsecond)
-				//log.Printf("Move (%v) To WallClock entryStartTime %v Begin %v", c.ID, curWc.UTC(), entryStartTime)
-				curWc = entryStartTime
-			}
-			livePointErr := c.moveToNext(&curWc)
-			//log.Printf("Update %v %v", livePointErr.errType, livePointErr.err)
-			switch livePointErr.errType {
-			case livePointNoEntry:
-				//No Entry ... update without new Timeline addition
-				livePointErr.err = nil
-			case livePointFutureEntry:
-				//Entry in future
-				livePointErr.err = nil
-			}
-			return livePointErr.err
+		for i := 0; i < entry.R+1; i++ {
+			ret = append(ret, [2]time.Time{toWc(elapsed), toWc(elapsed + entry.D)})
+			elapsed += entry.D
 		}
 	}
-	return fmt.Errorf("Representation(%v:%v) not found", c.adaptSetID, c.repID)
+	return ret
 }
 
 //livePointLocate - Locate the Live Point in the Current MPD
-// Set the context so that next URL fetch will return required values
+// Set the context so that next URL fetch will return the segment as per ContextOptions
+// WallClock (synced) is used, PublishTime if no Period is active now
 func (c *readerLiveMPDUpdateContext) livePointLocate(reader readerBase, curMpd *MPDtype) error {
-	var periodBaseURL url.URL
-	periodBaseURL = reader.baseURL
-
-	//Use PT as the base time to compute live point ref
-	curWc := curMpd.PublishTime
-	//log.Printf("PublishTime : %v", curWc.UTC())
-
-	period, pSwc := c.getActivePeriod(reader, curMpd)
+	now := c.now()
+	curWc, startErr := c.options.liveStartTime(curMpd, now)
+	period, pSwc := c.getActivePeriod(reader, curMpd, curWc)
+	if period == nil {
+		curWc = curMpd.PublishTime
+		period, pSwc = c.getActivePeriod(reader, curMpd, curWc)
+	}
 	if period == nil {
 		return fmt.Errorf("Unable to find Active Period")
 	}
-	if err := c.Select(*period); err != nil {
+	if err := c.selectPeriod(reader, period); err != nil {
 		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
 	}
-	tURL, err := AdjustURLPath(periodBaseURL, period.BaseURL, "")
-	if err != nil {
-		return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
+	if err := c.loadRepresentation(reader, curMpd, period, pSwc); err != nil {
+		return err
 	}
-	periodBaseURL = *tURL
-	for _, adapt := range period.AdaptationSet {
-		if adapt.ContentType != c.streamSelector.ContentType || adapt.Id != c.adaptSetID {
-			continue
+	spans := c.timelineSpans()
+	if len(spans) <= 0 {
+		livePointErr := c.moveToNext(&curWc)
+		return livePointErr.err
+	}
+	latest, delayed := 0, 0
+	for i, span := range spans {
+		//Segment available, first chunk for LL-DASH
+		if !span[1].Add(-1 * c.ato).After(now) {
+			latest = i
 		}
-		//Found matching AdaptationSet
-		tURL, err := AdjustURLPath(periodBaseURL, adapt.BaseURL, "")
-		if err != nil {
-			return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
+		if !span[0].After(curWc) {
+			delayed = i
 		}
-		adaptBaseURL := *tURL
-		for _, rp := range adapt.Representation {
-			if rp.Id != c.repID {
-				continue
-			}
-			//Found matching Representation
-			tURL, err := AdjustURLPath(adaptBaseURL, rp.BaseURL, "")
-			if err != nil {
-				return fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
-			}
-			rpBaseURL := *tURL
-
-			c.contentType = adapt.ContentType
-			c.lang = adapt.Lang
-			c.codecs = rp.Codecs
-			c.frameRate, _ = GetFrameRate(string(rp.FrameRate))
-
-			//Initialize the values
-			c.baseURL = rpBaseURL
-			c.timescale = adapt.SegmentTemplate.Timescale
+	}
+	//Inside the segment, its start is also end of previous one
+	startWc := spans[c.options.liveStartIndex(latest, delayed)][0].Add(1 * time.Microsecond)
+	if livePointErr := c.moveToNext(&startWc); livePointErr.err != nil {
+		return livePointErr.err
+	}
+	return startErr
+}
 
-			c.baseWcTime = pSwc
-			//log.Printf("baseWcTime : %v", c.baseWcTime.UTC())
-			//Offset any PresentationTimeOffset
-			if rp.SegmentBase.PresentationTimeOffset > 0 {
-				d := time.Duration(float64(rp.SegmentBase.PresentationTimeOffset)*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond
-				c.baseWcTime = c.baseWcTime.Add(-1 * d)
-				//log.Printf("baseWcTime + SB.PresentationTimeOffset (%v): %v", d, c.baseWcTime.UTC())
-			}
-			if adapt.SegmentTemplate.PresentationTimeOffset > 0 {
-				d := time.Duration(float64(adapt.SegmentTemplate.PresentationTimeOffset)*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond
-				c.baseWcTime = c.baseWcTime.Add(-1 * d)
-				//log.Printf("baseWcTime + ST.PresentationTimeOffset (%v): %v", d, c.baseWcTime.UTC())
-			}
-			//Offset any AvailabilityTimeOffset
-			if adapt.SegmentTemplate.AvailabilityTimeOffset > 0 {
-				d := time.Duration(adapt.SegmentTemplate.AvailabilityTimeOffset*1000000/float64(adapt.SegmentTemplate.Timescale)) * time.Microsecond
-				c.baseWcTime = c.baseWcTime.Add(d)
-				//log.Printf("baseWcTime + ST.PresentationTimeOffset (%v): %v", d, c.baseWcTime.UTC())
-			}
-			c.timeline = adapt.SegmentTemplate.SegmentTimeline
-			c.isNumber = reader.isNumber
-			c.isTime = reader.isTime
-			if len(adapt.SegmentTemplate.Initialization.SourceURL) > 0 {
-				temp := strings.ReplaceAll(adapt.SegmentTemplate.Initialization.SourceURL, "$RepresentationID$", string(rp.Id))
-				v, err := AdjustURLPath(rpBaseURL, []BaseURLType{}, temp)
-				if err != nil {
-					return fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.Id, err)
-				}
-				c.initURL = *v
-				c.initRange = adapt.SegmentTemplate.Initialization.Range
-				c.binitURLServed = false //to be supplied
-			} else {
-				c.initURL = url.URL{}
-				c.initRange = ""
-				c.binitURLServed = true //mark already supplied so that it is not done
-			}
-			temp := strings.ReplaceAll(adapt.SegmentTemplate.Media, "$RepresentationID$", string(rp.Id))
-			v, err := AdjustURLPath(rpBaseURL, []BaseURLType{}, temp)
-			if err != nil {
-				return fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.Id, err)
-			}
-			c.baseURL = *v
-			c.curSegTimeLineEntry = 0
-			c.curEntry = 0
-			c.chunkNumber = 0
-			c.chunkTimeTicks = 0
-			c.elapsedDurationTicks = 0
-			c.startNumber = adapt.SegmentTemplate.StartNumber
-			livePointErr := c.moveToNext(&curWc)
-			return livePointErr.err
-		}
+//nextPeriod - Move to the start of Period following the current one
+// StreamSelector and RepresentationSelector are applied again on the new Period
+// Return:
+//   error - io.EOF if no next Period in current MPD
+func (c *readerLiveMPDUpdateContext) nextPeriod() error {
+	if c.mpd == nil {
+		return io.EOF
+	}
+	period, timing := getPeriodByID(c.reader, c.mpd, c.periodID)
+	if period == nil || timing.index+1 >= len(c.mpd.Period) {
+		return io.EOF
+	}
+	timings := getPeriodTimings(c.reader, c.mpd)
+	next := &c.mpd.Period[timing.index+1]
+	pSwc := timings[timing.index+1].start
+	//Select on a copy, current selection retained if Period can not be served
+	sel := c.readerBaseContext
+	if err := sel.selectPeriod(c.reader, next); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", next.Id, err)
+	}
+	_, rp, err := sel.locateRepresentation(c.reader, next)
+	if err != nil {
+		return err
+	}
+	if rp.addressing() != addressingTemplateTimeline {
+		//Addressing changed, new ReaderContext required
+		return fmt.Errorf("Period(%v) without SegmentTimeline: %w", next.Id, io.EOF)
 	}
-	return fmt.Errorf("Representation(%v:%v) not found", c.adaptSetID, c.repID)
+	c.readerBaseContext = sel
+	if err := c.loadRepresentation(c.reader, c.mpd, next, pSwc); err != nil {
+		return err
+	}
+	//Position at the first entry of the timeline
+	//Timeline without entries is filled by next MPD update
+	c.moveToNext(&pSwc)
+	if c.StatzAgg != nil {
+		values := make([]interface{}, 3)
+		values[0] = period.Id
+		values[1] = next.Id
+		values[2] = pSwc
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: timeNow(c.clock),
+			ID:         c.ID,
+			Name:       EvtMPDPeriodChange,
+			Values:     values,
+		})
+	}
+	return nil
 }
 
 //getURL - Returns current URL
@@ -496,26 +488,35 @@ func (c *readerLiveMPDUpdateContext) nextURL() (ret *ChunkURL, err error) {
 		ret.ChunkURL = c.initURL
 		ret.Range = c.initRange
 		ret.Duration = 0
-		ret.FetchAt = time.Now()
+		ret.FetchAt = c.localTime(c.now())
+		ret = c.relocateChunk(ret)
 		return
 	}
 	entry, err = c.getURL()
+	if err == io.EOF {
+		//Timeline of Period done, continue in next Period if present
+		if c.nextPeriod() == nil {
+			return c.nextURL()
+		}
+	}
 	if err != nil {
 		return
 	}
-	ret = &ChunkURL{}
-	if c.isNumber {
-		ret.ChunkURL = c.baseURL
-		ret.Duration = time.Duration(float64(entry.D)*1000000/float64(c.timescale)) * time.Microsecond
-		ret.ChunkURL.Path = strings.ReplaceAll(ret.ChunkURL.Path, "$Number$", strconv.FormatInt(int64(c.chunkNumber+c.startNumber), 10))
-		ret.FetchAt = c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
-	}
-	if c.isTime {
-		ret.ChunkURL = c.baseURL
-		ret.Duration = time.Duration(float64(entry.D)*1000000/float64(c.timescale)) * time.Microsecond
-		ret.ChunkURL.Path = strings.ReplaceAll(ret.ChunkURL.Path, "$Time$", strconv.FormatUint((c.elapsedDurationTicks+c.chunkTimeTicks), 10))
-		ret.FetchAt = c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
+	values := c.templateValues()
+	values.Number = uint64(c.chunkNumber + c.startNumber)
+	values.Time = c.elapsedDurationTicks + c.chunkTimeTicks
+	chunkURL, err := c.media.resolve(c.baseURL, values)
+	if err != nil {
+		return nil, fmt.Errorf("Segment(%v) url has error: %v", values.Number, err)
 	}
+	ret = &ChunkURL{}
+	ret.ChunkURL = chunkURL
+	ret.Duration = time.Duration(float64(entry.D)*1000000/float64(c.timescale)) * time.Microsecond
+	//Available at segment end, earlier by AvailabilityTimeOffset
+	segStart := c.baseWcTime.Add(time.Duration(float64(c.elapsedDurationTicks+c.chunkTimeTicks)*1000000/float64(c.timescale)) * time.Microsecond)
+	segEnd := segStart.Add(ret.Duration)
+	c.setAvailability(ret, segEnd.Add(-1*c.ato), segEnd, c.atc)
 	c.moveToNext(nil)
+	ret = c.relocateChunk(ret)
 	return
 }
@@ -0,0 +1,74 @@
+package dashreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eswarantg/statzagg"
+)
+
+//readerLiveNumber - Implement addressing of MPD
+//  * Live
+//  * No MPD Update required
+//  * SegmentTemplate@duration (No SegmentTimeline)
+//  * $Number$ based url
+//  * $Time$ based url
+type readerLiveNumber struct {
+}
+
+//makeContext - Makes Reader Context
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier... if first time pass nil
+//   5: StreamSelector for the ContentType to select AdaptationSet
+//   6: RepresentationSelector ... selector for Representation
+//   7: ContextOptions
+// Return:
+//   1: Context for current AdaptationSet,Representation
+//   2: error
+func (r *readerLiveNumber) makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error) {
+	ctx, err := prepareContext(reader, rdrCtx, &readerLiveNumberContext{}, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	curContext := ctx.(*readerLiveNumberContext)
+	if rdrCtx != nil {
+		if updCounter != curContext.updCounter {
+			err = curContext.adjustRepUpdate(reader, curMpd)
+		}
+		if err == nil {
+			curContext.updCounter = updCounter
+			//Position kept, Representation switched if selection changed
+			curContext.reselect(reader, curMpd, curContext)
+			return curContext, nil
+		}
+		//Position not kept, live point located again
+		if curContext.StatzAgg != nil {
+			values := make([]interface{}, 1)
+			values[0] = err.Error()
+			curContext.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+				EventClock: timeNow(curContext.clock),
+				ID:         curContext.ID,
+				Name:       EvtMPDUpdateAdjustFailed,
+				Values:     values,
+			})
+		}
+	}
+	//Incoming context is nil = new context
+	//Locate the livePoint
+	err = curContext.livePointLocate(reader, curMpd, curContext.now())
+	var windowErr *SeekOutOfWindowError
+	if errors.As(err, &windowErr) {
+		//Positioned at the edge of time-shift window
+		curContext.updCounter = updCounter
+		return curContext, err
+	}
+	if err != nil {
+		return curContext, fmt.Errorf("LivePoint Locate Failed: %w", err)
+	}
+	curContext.updCounter = updCounter
+	return curContext, nil
+}
@@ -0,0 +1,251 @@
+package dashreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/eswarantg/statzagg"
+)
+
+//readerLiveNumberContext - readerLiveNumber Context
+type readerLiveNumberContext struct {
+	readerBaseContext
+
+	periodStart time.Time     //WallClock start of Period
+	periodEnd   time.Time     //WallClock end of Period, ZERO if not known
+	tsb         time.Duration //TimeShiftBufferDepth, ZERO if not known
+	ato         time.Duration //AvailabilityTimeOffset
+	atc         bool          //AvailabilityTimeComplete, false for LL-DASH chunks
+	segDuration uint64        //SegmentTemplate@duration in ticks
+	timescale   uint          //timescale - Ticks per sec
+	pto         uint64        //PresentationTimeOffset in ticks
+	startNumber uint          //SegmentTemplate@startNumber, 1 if absent
+	initURL     url.URL       //url for init
+	initRange   string        //range Header for init
+	baseURL     url.URL       //Base url of Representation
+	media       *URLTemplate  //SegmentTemplate@media
+
+	binitURLServed bool   //init URL pending to be returned
+	segIndex       uint64 //index of next segment from start of Period
+}
+
+//getTemplateInitialization - Initialization of SegmentTemplate (attribute or element)
+// Return:
+//   1: sourceURL
+//   2: range
+func getTemplateInitialization(segTemplate *SegmentTemplateType) (string, string) {
+	if len(segTemplate.InitializationAttr) > 0 {
+		return segTemplate.InitializationAttr, ""
+	}
+	return segTemplate.Initialization.SourceURL, segTemplate.Initialization.Range
+}
+
+//segmentStart - WallClock time at which segment starts
+func (c *readerLiveNumberContext) segmentStart(segIndex uint64) time.Time {
+	return c.periodStart.Add(ticksToDuration(segIndex*c.segDuration, c.timescale))
+}
+
+//segmentAvailable - WallClock time at which segment becomes available
+func (c *readerLiveNumberContext) segmentAvailable(segIndex uint64) time.Time {
+	return c.segmentStart(segIndex + 1).Add(-1 * c.ato)
+}
+
+//segmentAt - index of segment containing the WallClock
+// Return:
+//   1: index of segment
+//   2: false if WallClock is before Period start
+func (c *readerLiveNumberContext) segmentAt(wallClock time.Time) (uint64, bool) {
+	if wallClock.Before(c.periodStart) {
+		return 0, false
+	}
+	segDur := ticksToDuration(c.segDuration, c.timescale)
+	return uint64(wallClock.Sub(c.periodStart) / segDur), true
+}
+
+//isPeriodDone - segIndex is beyond end of the Period
+func (c *readerLiveNumberContext) isPeriodDone(segIndex uint64) bool {
+	if !IsPresentTime(c.periodEnd) {
+		return false
+	}
+	return !c.segmentStart(segIndex).Before(c.periodEnd)
+}
+
+//loadRepresentation - Initialize fields from the selected Representation
+func (c *readerLiveNumberContext) loadRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming) error {
+	adapt, rp, err := c.locateRepresentation(reader, period)
+	if err != nil {
+		return err
+	}
+	segTemplate := &rp.SegmentTemplate
+	if segTemplate.Duration == 0 {
+		return fmt.Errorf("AdaptationSet(%v) SegmentTemplate.Duration MUST be present", adapt.ID)
+	}
+	c.setContentFields(adapt, rp)
+	c.periodStart = timing.start
+	c.periodEnd = timing.end
+	c.tsb, _ = ParseDuration(curMpd.TimeShiftBufferDepth)
+	c.timescale = segTemplate.Timescale
+	if c.timescale == 0 {
+		c.timescale = 1
+	}
+	c.segDuration = uint64(segTemplate.Duration)
+	c.pto = segTemplate.PresentationTimeOffset
+	c.ato = time.Duration(segTemplate.AvailabilityTimeOffset * float64(time.Second))
+	c.atc = segTemplate.AvailabilityTimeComplete
+	c.startNumber = segTemplate.StartNumber
+	c.initURL, c.initRange, c.media, err = c.loadTemplate(segTemplate, rp.BaseURL)
+	if err != nil {
+		return err
+	}
+	c.baseURL = rp.BaseURL
+	return nil
+}
+
+//livePointLocate - Locate the Live Point from WallClock
+// Set the context so that next URL fetch will return the segment as per ContextOptions
+func (c *readerLiveNumberContext) livePointLocate(reader readerBase, curMpd *MPDtype, wallClock time.Time) error {
+	start, startErr := c.options.liveStartTime(curMpd, wallClock)
+	period, timing := c.getActivePeriodTiming(reader, curMpd, start)
+	if period == nil {
+		return fmt.Errorf("Unable to find Active Period")
+	}
+	if err := c.selectPeriod(reader, period); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	if err := c.loadRepresentation(reader, curMpd, period, timing); err != nil {
+		return err
+	}
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	//Latest segment completely available
+	segIndex, ok := c.segmentAt(wallClock.Add(c.ato))
+	if !ok || segIndex == 0 {
+		//First segment not yet available
+		c.segIndex = 0
+		return startErr
+	}
+	delayed, _ := c.segmentAt(start)
+	c.segIndex = uint64(c.options.liveStartIndex(int(segIndex-1), int(delayed)))
+	if c.isPeriodDone(c.segIndex) {
+		return fmt.Errorf("Period(%v) ended at %v: %w", period.Id, c.periodEnd.UTC(), io.EOF)
+	}
+	return startErr
+}
+
+//adjustRepUpdate - Handle MPD update, keep the position
+func (c *readerLiveNumberContext) adjustRepUpdate(reader readerBase, curMpd *MPDtype) error {
+	period, timing := c.getActivePeriodTiming(reader, curMpd, c.segmentStart(c.segIndex))
+	if period == nil {
+		return fmt.Errorf("Unable to find Active Period")
+	}
+	if !timing.start.Equal(c.periodStart) {
+		return fmt.Errorf("Period(%v) start moved %v -> %v", period.Id, c.periodStart.UTC(), timing.start.UTC())
+	}
+	segDuration, timescale := c.segDuration, c.timescale
+	if err := c.loadRepresentation(reader, curMpd, period, timing); err != nil {
+		return err
+	}
+	if segDuration != c.segDuration || timescale != c.timescale {
+		return fmt.Errorf("SegmentTemplate duration changed %v/%v -> %v/%v", segDuration, timescale, c.segDuration, c.timescale)
+	}
+	return nil
+}
+
+//servedPeriod - Period starting at periodStart
+func (c *readerLiveNumberContext) servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming) {
+	for _, timing := range getPeriodTimings(reader, curMpd) {
+		if timing.start.Equal(c.periodStart) {
+			return &curMpd.Period[timing.index], timing
+		}
+	}
+	return nil, periodTiming{index: -1}
+}
+
+//switchRepresentation - Serve repID from the segment containing start of next segment
+func (c *readerLiveNumberContext) switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error {
+	prev := *c
+	next := c.segmentStart(c.segIndex)
+	c.repID = repID
+	if err := c.loadRepresentation(reader, curMpd, period, timing); err != nil {
+		*c = prev
+		return err
+	}
+	c.segIndex, _ = c.segmentAt(next)
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	return nil
+}
+
+//skipToTimeShiftBuffer - Move ahead if next segment is no more available
+func (c *readerLiveNumberContext) skipToTimeShiftBuffer(wallClock time.Time) {
+	if c.tsb <= 0 {
+		return
+	}
+	earliest, ok := c.segmentAt(wallClock.Add(-1 * c.tsb))
+	if !ok || earliest <= c.segIndex {
+		return
+	}
+	if c.StatzAgg != nil {
+		values := make([]interface{}, 2)
+		values[0] = c.segIndex
+		values[1] = earliest
+		c.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+			EventClock: wallClock,
+			ID:         c.ID,
+			Name:       EvtMPDTimeShiftBufferSkip,
+			Values:     values,
+		})
+	}
+	c.segIndex = earliest
+}
+
+//NextURLs - Get URLs from Current MPD context
+//-- Once end of this list is reached
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   context for cancellation
+// Return:
+//   1: Channel of URLs, can be read till closed
+//   2: error
+func (c *readerLiveNumberContext) NextURLs(ctx context.Context) (ret <-chan ChunkURL, err error) {
+	return c.getURLs(ctx, ReaderContext(c))
+}
+
+//NextURL -
+//-- Once end of Period is reached (io.EOF)
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   None
+// Return:
+//   1: Next URL
+//   2: error
+func (c *readerLiveNumberContext) NextURL() (*ChunkURL, error) {
+	now := c.now()
+	if !c.binitURLServed {
+		c.binitURLServed = true
+		return c.relocateChunk(&ChunkURL{
+			ChunkURL: c.initURL,
+			Range:    c.initRange,
+			FetchAt:  c.localTime(now),
+		}), nil
+	}
+	c.skipToTimeShiftBuffer(now)
+	if c.isPeriodDone(c.segIndex) {
+		return nil, io.EOF
+	}
+	values := c.templateValues()
+	values.Number = c.segIndex + uint64(c.startNumber)
+	values.Time = c.pto + c.segIndex*c.segDuration
+	chunkURL, err := c.media.resolve(c.baseURL, values)
+	if err != nil {
+		return nil, fmt.Errorf("Segment(%v) url has error: %v", values.Number, err)
+	}
+	ret := &ChunkURL{
+		ChunkURL: chunkURL,
+		Duration: ticksToDuration(c.segDuration, c.timescale),
+	}
+	c.setAvailability(ret, c.segmentAvailable(c.segIndex), c.segmentStart(c.segIndex+1), c.atc)
+	c.segIndex++
+	return c.relocateChunk(ret), nil
+}
@@ -0,0 +1,130 @@
+package dashreader_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestLiveNumber(t *testing.T) {
+	tests := []struct {
+		file        string
+		startNumber int64
+	}{
+		{"test/live_noManupd.mpd", 0},
+		{"test/live_Man30supd.mpd", 0},
+		{"test/live_number_default.mpd", 1}, //@startNumber absent
+	}
+	segDur := 2 * time.Second
+	for _, test := range tests {
+		file := test.file
+		mpd, err := dashreader.ReadMPDFromFile(file)
+		if err != nil {
+			t.Fatalf("Error reading %s:%v", file, err)
+		}
+		factory := dashreader.ReaderFactory{}
+		rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/"+file, mpd)
+		if err != nil {
+			t.Fatalf("%v: Error getting reader : %v", file, err)
+		}
+		before := time.Now()
+		readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", file, err)
+		}
+		after := time.Now()
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("%v: Error getting init : %v", file, err)
+		}
+		if !strings.HasSuffix(chunkURL.ChunkURL.Path, "/V300/init.mp4") {
+			t.Errorf("%v: Init URL %v not expected", file, chunkURL.ChunkURL.String())
+		}
+		//AST=epoch, Period@start=0, $Number$ = index of latest available segment + @startNumber
+		minNumber := int64(before.Sub(time.Unix(0, 0))/segDur) - 1 + test.startNumber
+		maxNumber := int64(after.Sub(time.Unix(0, 0))/segDur) - 1 + test.startNumber
+		var lastNumber int64
+		for i := 0; i < 3; i++ {
+			chunkURL, err = readCtx.NextURL()
+			if err != nil {
+				t.Fatalf("%v: Error getting URL : %v", file, err)
+			}
+			base := chunkURL.ChunkURL.Path[strings.LastIndex(chunkURL.ChunkURL.Path, "/")+1:]
+			number, err := strconv.ParseInt(strings.TrimSuffix(base, ".m4s"), 10, 64)
+			if err != nil {
+				t.Fatalf("%v: URL %v has no number : %v", file, chunkURL.ChunkURL.String(), err)
+			}
+			if i == 0 && (number < minNumber || number > maxNumber) {
+				t.Errorf("%v: First number %v not in [%v,%v]", file, number, minNumber, maxNumber)
+			}
+			if i > 0 && number != lastNumber+1 {
+				t.Errorf("%v: Number %v does not follow %v", file, number, lastNumber)
+			}
+			lastNumber = number
+			expFetchAt := time.Unix(0, 0).Add(time.Duration(number-test.startNumber+1) * segDur)
+			if !chunkURL.FetchAt.Equal(expFetchAt) {
+				t.Errorf("%v: Number %v FetchAt Exp: %v Act: %v", file, number, expFetchAt.UTC(), chunkURL.FetchAt.UTC())
+			}
+			if chunkURL.Duration != segDur {
+				t.Errorf("%v: Number %v Duration Exp: %v Act: %v", file, number, segDur, chunkURL.Duration)
+			}
+		}
+	}
+}
+
+func TestLiveNumberAdjustFailed(t *testing.T) {
+	file := "test/live_number_default.mpd"
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	mpd, err := dashreader.ReadMPDFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/"+file, mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	rdr.SetStatzAgg(events)
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	//SegmentTemplate@duration changed, position not kept
+	updated := strings.Replace(string(data), "publishTime=\"2020-08-08T13:50:09Z\"", "publishTime=\"2020-08-08T13:50:19Z\"", 1)
+	updated = strings.Replace(updated, "<SegmentTemplate duration=\"2\"", "<SegmentTemplate duration=\"4\"", -1)
+	newMpd, err := dashreader.ReadMPDFromStream(strings.NewReader(updated))
+	if err != nil {
+		t.Fatalf("Error reading update : %v", err)
+	}
+	if ok, err := rdr.Update(newMpd); !ok || err != nil {
+		t.Fatalf("Update Exp: true <nil> Act: %v %v", ok, err)
+	}
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context after update : %v", err)
+	}
+	if len(events.find(dashreader.EvtMPDUpdateAdjustFailed)) != 1 {
+		t.Errorf("Expected 1 %v event, got %v", dashreader.EvtMPDUpdateAdjustFailed, len(events.find(dashreader.EvtMPDUpdateAdjustFailed)))
+	}
+	//Live point located again with the new duration
+	if _, err := readCtx.NextURL(); err != nil {
+		t.Errorf("Error getting URL after update : %v", err)
+	}
+	chunkURL, err := readCtx.NextURL()
+	if err != nil {
+		t.Fatalf("Error getting URL after update : %v", err)
+	}
+	if chunkURL.Duration != 4*time.Second {
+		t.Errorf("Duration Exp: %v Act: %v", 4*time.Second, chunkURL.Duration)
+	}
+}
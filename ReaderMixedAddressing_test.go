@@ -0,0 +1,70 @@
+package dashreader_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestLiveMixedAddressing(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/live_mixed.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	//Video - SegmentTimeline $Time$, live point at 8s is t6000 (latest available)
+	rdr.SetClock(dashreader.NewFakeClock(ast.Add(8 * time.Second)))
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("video: Error getting context : %v", err)
+	}
+	seg := mustURL(t, "http://127.0.0.1/live/V300/t8000.m4s")
+	seg.Duration = 2 * time.Second
+	seg.FetchAt = ast.Add(10 * time.Second)
+	first := mustURL(t, "http://127.0.0.1/live/V300/t6000.m4s")
+	first.Duration = 2 * time.Second
+	first.FetchAt = ast.Add(8 * time.Second)
+	checkURLs(t, "video", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/V300/init.mp4"),
+		first,
+		seg,
+	})
+	//Audio - SegmentTemplate@duration $Number$, live point from WallClock
+	rdr.SetClock(nil)
+	before := time.Now()
+	readCtx, err = rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "audio"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	after := time.Now()
+	chunkURL, err := readCtx.NextURL()
+	if err != nil || chunkURL.ChunkURL.String() != "http://127.0.0.1/live/A64/init.mp4" {
+		t.Fatalf("audio: init Exp: http://127.0.0.1/live/A64/init.mp4 Act: %v %v", chunkURL, err)
+	}
+	chunkURL, err = readCtx.NextURL()
+	if err != nil {
+		t.Fatalf("audio: Error getting URL : %v", err)
+	}
+	base := strings.TrimPrefix(chunkURL.ChunkURL.String(), "http://127.0.0.1/live/A64/")
+	number, err := strconv.ParseInt(strings.TrimSuffix(base, ".m4s"), 10, 64)
+	if err != nil {
+		t.Fatalf("audio: URL %v has no number : %v", chunkURL.ChunkURL.String(), err)
+	}
+	//startNumber=1, latest available segment
+	minNumber := int64(before.Sub(ast) / (2 * time.Second))
+	maxNumber := int64(after.Sub(ast) / (2 * time.Second))
+	if number < minNumber || number > maxNumber {
+		t.Errorf("audio: Number %v not in [%v,%v]", number, minNumber, maxNumber)
+	}
+	if chunkURL.Duration != 2*time.Second {
+		t.Errorf("audio: Duration Exp: %v Act: %v", 2*time.Second, chunkURL.Duration)
+	}
+}
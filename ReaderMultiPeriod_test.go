@@ -0,0 +1,74 @@
+package dashreader_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+	"github.com/eswarantg/statzagg"
+)
+
+//eventCapture - StatzAgg recording the events posted
+type eventCapture struct {
+	events []*statzagg.EventStats
+}
+
+func (e *eventCapture) PostHTTPClientStats(ctx context.Context, s *statzagg.HTTPClientStatz) {}
+
+func (e *eventCapture) PostEventStats(ctx context.Context, s *statzagg.EventStats) {
+	e.events = append(e.events, s)
+}
+
+func (e *eventCapture) find(name string) []*statzagg.EventStats {
+	var ret []*statzagg.EventStats
+	for _, evt := range e.events {
+		if evt.Name == name {
+			ret = append(ret, evt)
+		}
+	}
+	return ret
+}
+
+func TestLiveMultiPeriod(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/live_multiperiod.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	events := &eventCapture{}
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(ast.Add(8 * time.Second))}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	rdr.SetStatzAgg(events)
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	//Available at segment end
+	seg := func(s string, start time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		u.FetchAt = ast.Add(start + u.Duration)
+		return u
+	}
+	//Live point at 8s is t6000 in p0 (latest available), p1 starts at 10s
+	checkURLs(t, "multiperiod", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/p0/V300/init.mp4"),
+		seg("http://127.0.0.1/live/p0/V300/t6000.m4s", 6*time.Second),
+		seg("http://127.0.0.1/live/p0/V300/t8000.m4s", 8*time.Second),
+		mustURL(t, "http://127.0.0.1/live/p1/V300/init.mp4"),
+		seg("http://127.0.0.1/live/p1/V300/t0.m4s", 10*time.Second),
+		seg("http://127.0.0.1/live/p1/V300/t2000.m4s", 12*time.Second),
+		seg("http://127.0.0.1/live/p1/V300/t4000.m4s", 14*time.Second),
+	})
+	changes := events.find(dashreader.EvtMPDPeriodChange)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 %v event, got %v", dashreader.EvtMPDPeriodChange, len(changes))
+	}
+	if changes[0].Values[0] != "p0" || changes[0].Values[1] != "p1" {
+		t.Errorf("Period change Exp: p0->p1 Act: %v->%v", changes[0].Values[0], changes[0].Values[1])
+	}
+}
@@ -0,0 +1,50 @@
+package dashreader
+
+import (
+	"fmt"
+	"io"
+)
+
+//readerOnDemand - Implement addressing of MPD
+//  * Static
+//  * SegmentBase with indexRange (sidx)
+//  * Single file per Representation
+type readerOnDemand struct {
+	indexFetcher IndexFetcher //Fetcher for sidx bytes
+}
+
+//makeContext - Makes Reader Context
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier... if first time pass nil
+//   5: StreamSelector for the ContentType to select AdaptationSet
+//   6: RepresentationSelector ... selector for Representation
+//   7: ContextOptions
+// Return:
+//   1: Context for current AdaptationSet,Representation
+//   2: error (io.EOF once all Periods are served)
+func (r *readerOnDemand) makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error) {
+	ctx, err := prepareContext(reader, rdrCtx, &readerOnDemandContext{periodIndex: -1}, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	curContext := ctx.(*readerOnDemandContext)
+	curContext.updCounter = updCounter
+	if rdrCtx != nil && !curContext.isPeriodDone() {
+		//Current period still has URLs, Representation switched if selection changed
+		curContext.reselect(reader, curMpd, curContext)
+		return curContext, nil
+	}
+	//Move to next Period
+	periodIndex := curContext.periodIndex + 1
+	if periodIndex >= len(curMpd.Period) {
+		return curContext, fmt.Errorf("All Periods(%v) served: %w", len(curMpd.Period), io.EOF)
+	}
+	err = curContext.loadPeriod(reader, r.indexFetcher, curMpd, periodIndex)
+	if err != nil {
+		return curContext, fmt.Errorf("Period(%v) load failed: %w", periodIndex, err)
+	}
+	return curContext, nil
+}
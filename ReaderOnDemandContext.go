@@ -0,0 +1,186 @@
+package dashreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+//onDemandSubsegment - Subsegment located from sidx
+type onDemandSubsegment struct {
+	byteRange string        //first-last within media
+	duration  time.Duration //duration of subsegment
+}
+
+//readerOnDemandContext - readerOnDemand Context
+type readerOnDemandContext struct {
+	readerBaseContext
+
+	periodIndex  int          //index of the Period being served
+	indexFetcher IndexFetcher //Fetcher for sidx of Representation switched to
+	mediaURL     url.URL      //url of the single media file
+	initURL      url.URL      //url for init
+	initRange    string       //range Header for init
+
+	binitURLServed bool                 //init URL pending to be returned
+	subsegments    []onDemandSubsegment //subsegments read from sidx
+	curSubsegment  int                  //index of next subsegment to return
+}
+
+//isPeriodDone - All URLs of the Period are returned
+func (c *readerOnDemandContext) isPeriodDone() bool {
+	return c.binitURLServed && c.curSubsegment >= len(c.subsegments)
+}
+
+//getSegmentBase - SegmentBase that applies to Representation
+// Representation values override AdaptationSet values which override Period values
+func getSegmentBase(period *PeriodType, adapt *AdaptationSetType, rp *RepresentationType) *SegmentBaseType {
+	ret := mergeSegmentBase(mergeSegmentBase(period.SegmentBase, adapt.SegmentBase), rp.SegmentBase)
+	return &ret
+}
+
+//loadPeriod - Select the Representation in the Period and read its index
+func (c *readerOnDemandContext) loadPeriod(reader readerBase, fetcher IndexFetcher, curMpd *MPDtype, periodIndex int) error {
+	period := &curMpd.Period[periodIndex]
+	if err := c.selectPeriod(reader, period); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	c.indexFetcher = fetcher
+	c.periodIndex = periodIndex
+	return c.loadRepresentation(reader, period)
+}
+
+//loadRepresentation - Initialize fields from the selected Representation and read its index
+func (c *readerOnDemandContext) loadRepresentation(reader readerBase, period *PeriodType) error {
+	adapt, rp, err := c.locateRepresentation(reader, period)
+	if err != nil {
+		return err
+	}
+	c.setContentFields(adapt, rp)
+	c.mediaURL = rp.BaseURL
+	return c.readIndex(c.indexFetcher, &rp.SegmentBase)
+}
+
+//servedPeriod - Period at periodIndex
+func (c *readerOnDemandContext) servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming) {
+	if c.periodIndex < 0 || c.periodIndex >= len(curMpd.Period) {
+		return nil, periodTiming{index: -1}
+	}
+	return &curMpd.Period[c.periodIndex], getPeriodTimings(reader, curMpd)[c.periodIndex]
+}
+
+//switchRepresentation - Serve repID from the subsegment containing end of last subsegment returned
+func (c *readerOnDemandContext) switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error {
+	prev := *c
+	var next time.Duration
+	for i := 0; i < c.curSubsegment && i < len(c.subsegments); i++ {
+		next += c.subsegments[i].duration
+	}
+	c.repID = repID
+	if err := c.loadRepresentation(reader, period); err != nil {
+		*c = prev
+		return err
+	}
+	var end time.Duration
+	c.curSubsegment = len(c.subsegments)
+	for i := range c.subsegments {
+		end += c.subsegments[i].duration
+		if end > next {
+			c.curSubsegment = i
+			break
+		}
+	}
+	return nil
+}
+
+//readIndex - Fetch sidx and build the subsegment list
+func (c *readerOnDemandContext) readIndex(fetcher IndexFetcher, segBase *SegmentBaseType) error {
+	indexRange := segBase.IndexRange
+	if len(indexRange) <= 0 {
+		indexRange = segBase.RepresentationIndex.Range
+	}
+	indexFirst, _, err := parseByteRange(indexRange)
+	if err != nil {
+		return fmt.Errorf("SegmentBase.IndexRange: %w", err)
+	}
+	//Initialization
+	c.initURL = c.mediaURL
+	c.initRange = segBase.Initialization.Range
+	if len(segBase.Initialization.SourceURL) > 0 {
+		v, err := AdjustURLPath(c.mediaURL, []BaseURLType{}, segBase.Initialization.SourceURL)
+		if err != nil {
+			return fmt.Errorf("Adjusting to Initialization(%v) has error: %v", segBase.Initialization.SourceURL, err)
+		}
+		c.initURL = *v
+	} else if len(c.initRange) <= 0 && indexFirst > 0 {
+		//Init is everything before the index
+		c.initRange = "0-" + strconv.FormatUint(indexFirst-1, 10)
+	}
+	c.binitURLServed = false
+	//Index
+	data, err := fetcher.FetchRange(c.relocate(c.mediaURL), indexRange)
+	if err != nil {
+		return fmt.Errorf("Fetching index(%v) failed: %w", indexRange, err)
+	}
+	sidx, sidxEnd, err := parseSidx(data)
+	if err != nil {
+		return fmt.Errorf("Parsing index(%v) failed: %w", indexRange, err)
+	}
+	offset := indexFirst + sidxEnd + sidx.firstOffset
+	c.subsegments = make([]onDemandSubsegment, 0, len(sidx.references))
+	for i, ref := range sidx.references {
+		if ref.referenceType != 0 {
+			return fmt.Errorf("sidx reference(%v) to another sidx not supported", i)
+		}
+		c.subsegments = append(c.subsegments, onDemandSubsegment{
+			byteRange: strconv.FormatUint(offset, 10) + "-" + strconv.FormatUint(offset+uint64(ref.referencedSize)-1, 10),
+			duration:  ticksToDuration(uint64(ref.subsegmentDuration), uint(sidx.timescale)),
+		})
+		offset += uint64(ref.referencedSize)
+	}
+	c.curSubsegment = 0
+	return nil
+}
+
+//NextURLs - Get URLs from Current MPD context
+//-- Once end of this list is reached
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   context for cancellation
+// Return:
+//   1: Channel of URLs, can be read till closed
+//   2: error
+func (c *readerOnDemandContext) NextURLs(ctx context.Context) (ret <-chan ChunkURL, err error) {
+	return c.getURLs(ctx, ReaderContext(c))
+}
+
+//NextURL -
+//-- Once end is reached (io.EOF)
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   None
+// Return:
+//   1: Next URL
+//   2: error
+func (c *readerOnDemandContext) NextURL() (*ChunkURL, error) {
+	if !c.binitURLServed {
+		c.binitURLServed = true
+		return c.relocateChunk(&ChunkURL{
+			ChunkURL: c.initURL,
+			Range:    c.initRange,
+		}), nil
+	}
+	if c.curSubsegment >= len(c.subsegments) {
+		return nil, io.EOF
+	}
+	subsegment := c.subsegments[c.curSubsegment]
+	c.curSubsegment++
+	return c.relocateChunk(&ChunkURL{
+		ChunkURL: c.mediaURL,
+		Range:    subsegment.byteRange,
+		Duration: subsegment.duration,
+	}), nil
+}
@@ -0,0 +1,106 @@
+package dashreader_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//fileIndexFetcher - serves index bytes from fixtures in test/
+type fileIndexFetcher struct {
+	fetched []string
+}
+
+func (f *fileIndexFetcher) FetchRange(u url.URL, byteRange string) ([]byte, error) {
+	f.fetched = append(f.fetched, u.String()+"|"+byteRange)
+	data, err := ioutil.ReadFile("test/" + path.Base(u.Path))
+	if err != nil {
+		return nil, err
+	}
+	if len(byteRange) <= 0 {
+		return data, nil
+	}
+	parts := strings.SplitN(byteRange, "-", 2)
+	first, _ := strconv.Atoi(parts[0])
+	last, _ := strconv.Atoi(parts[1])
+	if last >= len(data) {
+		return nil, fmt.Errorf("range %v beyond %v bytes", byteRange, len(data))
+	}
+	return data[first : last+1], nil
+}
+
+func TestOnDemandSegmentBase(t *testing.T) {
+	file := "test/ondemand_segbase.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	fetcher := &fileIndexFetcher{}
+	factory := dashreader.ReaderFactory{IndexFetcher: fetcher}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	tests := []struct {
+		contentType string
+		exp         []dashreader.ChunkURL
+	}{
+		{
+			contentType: "video",
+			exp: []dashreader.ChunkURL{
+				{Range: "0-95"},
+				{Range: "164-1163", Duration: 2 * time.Second},
+				{Range: "1164-2363", Duration: 2 * time.Second},
+				{Range: "2364-3163", Duration: 1500 * time.Millisecond},
+			},
+		},
+		{
+			contentType: "audio",
+			exp: []dashreader.ChunkURL{
+				{Range: "0-95"},
+				{Range: "152-551", Duration: 2 * time.Second},
+				{Range: "552-951", Duration: 1 * time.Second},
+			},
+		},
+	}
+	for _, test := range tests {
+		streamSelector := dashreader.StreamSelector{ID: "1", ContentType: test.contentType}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", test.contentType, err)
+		}
+		expURL := "http://127.0.0.1/vod/media/ondemand_" + test.contentType + ".mp4"
+		for i, exp := range test.exp {
+			chunkURL, err := readCtx.NextURL()
+			if err != nil {
+				t.Fatalf("%v: URL %v error : %v", test.contentType, i, err)
+			}
+			if chunkURL.ChunkURL.String() != expURL || chunkURL.Range != exp.Range || chunkURL.Duration != exp.Duration {
+				t.Errorf("%v: URL %v Exp: %v %v %v Act: %v %v %v", test.contentType, i, expURL, exp.Range, exp.Duration,
+					chunkURL.ChunkURL.String(), chunkURL.Range, chunkURL.Duration)
+			}
+			if !chunkURL.FetchAt.IsZero() {
+				t.Errorf("%v: URL %v FetchAt %v expected ZERO", test.contentType, i, chunkURL.FetchAt)
+			}
+		}
+		if _, err := readCtx.NextURL(); err != io.EOF {
+			t.Errorf("%v: Expected io.EOF at end, got %v", test.contentType, err)
+		}
+		_, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("%v: Expected io.EOF after last Period, got %v", test.contentType, err)
+		}
+	}
+	if len(fetcher.fetched) != 2 {
+		t.Errorf("Expected 2 index fetches, got %v", fetcher.fetched)
+	}
+}
@@ -0,0 +1,91 @@
+package dashreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/eswarantg/statzagg"
+)
+
+//readerSegmentList - Implement addressing of MPD
+//  * Live or Static
+//  * SegmentList with SegmentURL@media/mediaRange
+//  * SegmentTimeline or SegmentList@duration
+type readerSegmentList struct {
+	isLive bool //MPD@type dynamic
+}
+
+//makeContext - Makes Reader Context
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier... if first time pass nil
+//   5: StreamSelector for the ContentType to select AdaptationSet
+//   6: RepresentationSelector ... selector for Representation
+//   7: ContextOptions
+// Return:
+//   1: Context for current AdaptationSet,Representation
+//   2: error (io.EOF once all Periods of static MPD are served)
+func (r *readerSegmentList) makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error) {
+	ctx, err := prepareContext(reader, rdrCtx, &readerSegmentListContext{isLive: r.isLive, periodIndex: -1}, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	curContext := ctx.(*readerSegmentListContext)
+	if !r.isLive {
+		curContext.updCounter = updCounter
+		if rdrCtx != nil && !curContext.isListDone() {
+			//Current period still has URLs, Representation switched if selection changed
+			curContext.reselect(reader, curMpd, curContext)
+			return curContext, nil
+		}
+		periodIndex := curContext.periodIndex + 1
+		if periodIndex >= len(curMpd.Period) {
+			return curContext, fmt.Errorf("All Periods(%v) served: %w", len(curMpd.Period), io.EOF)
+		}
+		err = curContext.loadPeriod(reader, curMpd, periodIndex)
+		if err != nil {
+			return curContext, fmt.Errorf("Period(%v) load failed: %w", periodIndex, err)
+		}
+		return curContext, nil
+	}
+	if rdrCtx != nil {
+		if updCounter != curContext.updCounter {
+			err = curContext.adjustRepUpdate(reader, curMpd)
+		}
+		if err == nil {
+			curContext.updCounter = updCounter
+			//Position kept, Representation switched if selection changed
+			curContext.reselect(reader, curMpd, curContext)
+			return curContext, nil
+		}
+		//Position not kept, live point located again
+		if curContext.StatzAgg != nil {
+			values := make([]interface{}, 1)
+			values[0] = err.Error()
+			curContext.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+				EventClock: timeNow(curContext.clock),
+				ID:         curContext.ID,
+				Name:       EvtMPDUpdateAdjustFailed,
+				Values:     values,
+			})
+		}
+	}
+	//Incoming context is nil = new context
+	//Locate the livePoint
+	err = curContext.livePointLocate(reader, curMpd, curContext.now())
+	var windowErr *SeekOutOfWindowError
+	if errors.As(err, &windowErr) {
+		//Positioned at the edge of time-shift window
+		curContext.updCounter = updCounter
+		return curContext, err
+	}
+	if err != nil {
+		return curContext, fmt.Errorf("LivePoint Locate Failed: %w", err)
+	}
+	curContext.updCounter = updCounter
+	return curContext, nil
+}
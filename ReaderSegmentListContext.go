@@ -0,0 +1,359 @@
+package dashreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+//listSegment - Segment described by SegmentList.SegmentURL
+type listSegment struct {
+	mediaURL   url.URL //url of the segment
+	mediaRange string  //range Header for segment
+	start      uint64  //media time in ticks (includes PresentationTimeOffset)
+	duration   uint64  //duration in ticks
+}
+
+//readerSegmentListContext - readerSegmentList Context
+type readerSegmentListContext struct {
+	readerBaseContext
+
+	isLive      bool          //MPD@type dynamic
+	periodIndex int           //index of the Period being served (static)
+	periodID    string        //Period@id being served
+	periodStart time.Time     //WallClock start of Period
+	timescale   uint          //timescale - Ticks per sec
+	pto         uint64        //PresentationTimeOffset in ticks
+	ato         time.Duration //AvailabilityTimeOffset
+	atc         bool          //AvailabilityTimeComplete, false for LL-DASH chunks
+	initURL     url.URL       //url for init
+	initRange   string        //range Header for init
+
+	binitURLServed bool          //init URL pending to be returned
+	segments       []listSegment //segments of the Representation
+	curSegment     int           //index of next segment to return
+	servedAny      bool          //atleast one segment returned
+	lastStart      uint64        //start of last segment returned
+}
+
+//getSegmentList - SegmentList that applies to Representation
+// Representation values override AdaptationSet values which override Period values
+// @startNumber absent at every level is 1, written by newMPDDecoder
+func getSegmentList(period *PeriodType, adapt *AdaptationSetType, rp *RepresentationType) *SegmentListType {
+	ret := mergeSegmentList(mergeSegmentList(period.SegmentList, adapt.SegmentList), rp.SegmentList)
+	return &ret
+}
+
+//isListDone - All URLs of the list are returned
+func (c *readerSegmentListContext) isListDone() bool {
+	return c.binitURLServed && c.curSegment >= len(c.segments)
+}
+
+//segmentStart - WallClock time at which segment starts
+func (c *readerSegmentListContext) segmentStart(seg *listSegment) time.Time {
+	if seg.start < c.pto {
+		return c.periodStart
+	}
+	return c.periodStart.Add(ticksToDuration(seg.start-c.pto, c.timescale))
+}
+
+//segmentAvailable - WallClock time at which segment becomes available
+func (c *readerSegmentListContext) segmentAvailable(seg *listSegment) time.Time {
+	return c.segmentStart(seg).Add(ticksToDuration(seg.duration, c.timescale)).Add(-1 * c.ato)
+}
+
+//buildSegments - Expand SegmentURLs with timing from SegmentTimeline or @duration
+// Single SegmentURL without both spans the Period
+// Parameters:
+//   1: SegmentList
+//   2: BaseURL of Representation
+//   3: Period@duration, ZERO if not known
+// Return:
+//   1: segments
+//   2: error
+func buildSegments(segList *SegmentListType, rpBaseURL url.URL, periodDuration time.Duration) ([]listSegment, error) {
+	ret := make([]listSegment, 0, len(segList.SegmentURL))
+	add := func(start uint64, duration uint64) error {
+		segURL := segList.SegmentURL[len(ret)]
+		mediaURL := rpBaseURL
+		if len(segURL.Media) > 0 {
+			v, err := AdjustURLPath(rpBaseURL, []BaseURLType{}, segURL.Media)
+			if err != nil {
+				return fmt.Errorf("SegmentURL(%v) has error: %v", segURL.Media, err)
+			}
+			mediaURL = *v
+		}
+		ret = append(ret, listSegment{
+			mediaURL:   mediaURL,
+			mediaRange: segURL.MediaRange,
+			start:      start,
+			duration:   duration,
+		})
+		return nil
+	}
+	timeline := segList.SegmentTimeline.S
+	if len(timeline) <= 0 && segList.Duration == 0 {
+		if len(segList.SegmentURL) > 1 {
+			return nil, fmt.Errorf("SegmentList.Duration or SegmentList.SegmentTimeline MUST be present")
+		}
+		timescale := segList.Timescale
+		if timescale == 0 {
+			timescale = 1
+		}
+		if len(segList.SegmentURL) == 1 {
+			if err := add(segList.PresentationTimeOffset, uint64(periodDuration.Seconds()*float64(timescale)+0.5)); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+	if len(timeline) <= 0 {
+		for i := range segList.SegmentURL {
+			start := segList.PresentationTimeOffset + uint64(i)*uint64(segList.Duration)
+			if err := add(start, uint64(segList.Duration)); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+	var start uint64
+	for i, entry := range timeline {
+		if i == 0 || entry.T != 0 {
+			start = entry.T
+		}
+		repeatCount := entry.R + 1
+		if entry.R < 0 {
+			//Repeat till next entry or end of list
+			repeatCount = len(segList.SegmentURL)
+			if i+1 < len(timeline) && timeline[i+1].T > start && entry.D > 0 {
+				repeatCount = int((timeline[i+1].T - start) / entry.D)
+			}
+		}
+		for j := 0; j < repeatCount && len(ret) < len(segList.SegmentURL); j++ {
+			if err := add(start, entry.D); err != nil {
+				return nil, err
+			}
+			start += entry.D
+		}
+	}
+	return ret, nil
+}
+
+//loadRepresentation - Initialize fields from the selected Representation
+func (c *readerSegmentListContext) loadRepresentation(reader readerBase, period *PeriodType, pSwc time.Time) error {
+	adapt, rp, err := c.locateRepresentation(reader, period)
+	if err != nil {
+		return err
+	}
+	var periodDuration time.Duration
+	if IsPresentDuration(period.Duration) {
+		periodDuration, _ = ParseDuration(period.Duration)
+	}
+	segList := &rp.SegmentList
+	segments, err := buildSegments(segList, rp.BaseURL, periodDuration)
+	if err != nil {
+		return fmt.Errorf("Representation(%v) %v", rp.ID, err)
+	}
+	c.setContentFields(adapt, rp)
+	c.periodID = period.Id
+	c.periodStart = pSwc
+	c.timescale = segList.Timescale
+	if c.timescale == 0 {
+		c.timescale = 1
+	}
+	c.pto = segList.PresentationTimeOffset
+	c.ato = time.Duration(segList.AvailabilityTimeOffset * float64(time.Second))
+	c.atc = segList.AvailabilityTimeComplete
+	c.initURL = url.URL{}
+	c.initRange = segList.Initialization.Range
+	if len(segList.Initialization.SourceURL) > 0 {
+		v, err := AdjustURLPath(rp.BaseURL, []BaseURLType{}, segList.Initialization.SourceURL)
+		if err != nil {
+			return fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.ID, err)
+		}
+		c.initURL = *v
+	} else if len(c.initRange) > 0 {
+		c.initURL = rp.BaseURL
+	}
+	c.segments = segments
+	return nil
+}
+
+//loadPeriod - Select the Representation in the Period (static)
+func (c *readerSegmentListContext) loadPeriod(reader readerBase, curMpd *MPDtype, periodIndex int) error {
+	period := &curMpd.Period[periodIndex]
+	if err := c.selectPeriod(reader, period); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	if err := c.loadRepresentation(reader, period, time.Time{}); err != nil {
+		return err
+	}
+	c.periodIndex = periodIndex
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	c.curSegment = 0
+	c.servedAny = false
+	return nil
+}
+
+//livePointLocate - Locate the Live Point from WallClock
+// Set the context so that next URL fetch will return the segment as per ContextOptions
+func (c *readerSegmentListContext) livePointLocate(reader readerBase, curMpd *MPDtype, wallClock time.Time) error {
+	start, startErr := c.options.liveStartTime(curMpd, wallClock)
+	period, pSwc := c.getActivePeriod(reader, curMpd, start)
+	if period == nil {
+		return fmt.Errorf("Unable to find Active Period")
+	}
+	if err := c.selectPeriod(reader, period); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	if err := c.loadRepresentation(reader, period, pSwc); err != nil {
+		return err
+	}
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	c.servedAny = false
+	latest, delayed := 0, 0
+	for i := range c.segments {
+		if !c.segmentAvailable(&c.segments[i]).After(wallClock) {
+			latest = i
+		}
+		if !c.segmentStart(&c.segments[i]).After(start) {
+			delayed = i
+		}
+	}
+	c.curSegment = c.options.liveStartIndex(latest, delayed)
+	return startErr
+}
+
+//adjustRepUpdate - Handle MPD update, continue after last segment returned
+func (c *readerSegmentListContext) adjustRepUpdate(reader readerBase, curMpd *MPDtype) error {
+	wallClock := c.periodStart
+	if c.curSegment < len(c.segments) {
+		wallClock = c.segmentStart(&c.segments[c.curSegment])
+	} else if len(c.segments) > 0 {
+		wallClock = c.segmentStart(&c.segments[len(c.segments)-1])
+	}
+	period, pSwc := c.getActivePeriod(reader, curMpd, wallClock)
+	if period == nil {
+		return fmt.Errorf("Unable to find Active Period")
+	}
+	if period.Id != c.periodID {
+		return fmt.Errorf("Period changed %v -> %v", c.periodID, period.Id)
+	}
+	curSegment := c.curSegment
+	if err := c.loadRepresentation(reader, period, pSwc); err != nil {
+		return err
+	}
+	if !c.servedAny {
+		//Nothing returned yet, keep the index
+		if curSegment > len(c.segments) {
+			curSegment = len(c.segments)
+		}
+		c.curSegment = curSegment
+		return nil
+	}
+	//List extended... continue after the last segment returned
+	c.curSegment = len(c.segments)
+	for i := range c.segments {
+		if c.segments[i].start > c.lastStart {
+			c.curSegment = i
+			break
+		}
+	}
+	return nil
+}
+
+//servedPeriod - Period being served, by index for static and Period@id for live
+func (c *readerSegmentListContext) servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming) {
+	if c.isLive {
+		return getPeriodByID(reader, curMpd, c.periodID)
+	}
+	if c.periodIndex < 0 || c.periodIndex >= len(curMpd.Period) {
+		return nil, periodTiming{index: -1}
+	}
+	return &curMpd.Period[c.periodIndex], getPeriodTimings(reader, curMpd)[c.periodIndex]
+}
+
+//switchRepresentation - Serve repID from the segment containing start of next segment
+func (c *readerSegmentListContext) switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error {
+	prev := *c
+	next := c.periodStart
+	if c.curSegment < len(c.segments) {
+		next = c.segmentStart(&c.segments[c.curSegment])
+	} else if len(c.segments) > 0 {
+		last := &c.segments[len(c.segments)-1]
+		next = c.segmentStart(last).Add(ticksToDuration(last.duration, c.timescale))
+	}
+	c.repID = repID
+	if err := c.loadRepresentation(reader, period, c.periodStart); err != nil {
+		*c = prev
+		return err
+	}
+	c.curSegment = len(c.segments)
+	for i := range c.segments {
+		seg := &c.segments[i]
+		if c.segmentStart(seg).Add(ticksToDuration(seg.duration, c.timescale)).After(next) {
+			c.curSegment = i
+			break
+		}
+	}
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	if c.curSegment > 0 {
+		//Later updates continue after the segment before the position
+		c.lastStart = c.segments[c.curSegment-1].start
+	} else {
+		c.servedAny = false
+	}
+	return nil
+}
+
+//NextURLs - Get URLs from Current MPD context
+//-- Once end of this list is reached
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   context for cancellation
+// Return:
+//   1: Channel of URLs, can be read till closed
+//   2: error
+func (c *readerSegmentListContext) NextURLs(ctx context.Context) (ret <-chan ChunkURL, err error) {
+	return c.getURLs(ctx, ReaderContext(c))
+}
+
+//NextURL -
+//-- Once end is reached (io.EOF)
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   None
+// Return:
+//   1: Next URL
+//   2: error
+func (c *readerSegmentListContext) NextURL() (*ChunkURL, error) {
+	if !c.binitURLServed {
+		c.binitURLServed = true
+		ret := &ChunkURL{
+			ChunkURL: c.initURL,
+			Range:    c.initRange,
+		}
+		if c.isLive {
+			ret.FetchAt = c.localTime(c.now())
+		}
+		return c.relocateChunk(ret), nil
+	}
+	if c.curSegment >= len(c.segments) {
+		return nil, io.EOF
+	}
+	seg := &c.segments[c.curSegment]
+	ret := &ChunkURL{
+		ChunkURL: seg.mediaURL,
+		Range:    seg.mediaRange,
+		Duration: ticksToDuration(seg.duration, c.timescale),
+	}
+	if c.isLive {
+		c.setAvailability(ret, c.segmentAvailable(seg), c.segmentStart(seg).Add(ret.Duration), c.atc)
+	}
+	c.curSegment++
+	c.servedAny = true
+	c.lastStart = seg.start
+	return c.relocateChunk(ret), nil
+}
@@ -0,0 +1,204 @@
+package dashreader_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func checkURLs(t *testing.T, name string, readCtx dashreader.ReaderContext, exp []dashreader.ChunkURL) {
+	for i, e := range exp {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("%v: URL %v error : %v", name, i, err)
+		}
+		if chunkURL.ChunkURL.String() != e.ChunkURL.String() || chunkURL.Range != e.Range || chunkURL.Duration != e.Duration {
+			t.Errorf("%v: URL %v Exp: %v %v %v Act: %v %v %v", name, i, e.ChunkURL.String(), e.Range, e.Duration,
+				chunkURL.ChunkURL.String(), chunkURL.Range, chunkURL.Duration)
+		}
+		if !e.FetchAt.IsZero() && !chunkURL.FetchAt.Equal(e.FetchAt) {
+			t.Errorf("%v: URL %v FetchAt Exp: %v Act: %v", name, i, e.FetchAt.UTC(), chunkURL.FetchAt.UTC())
+		}
+	}
+	if _, err := readCtx.NextURL(); err != io.EOF {
+		t.Errorf("%v: Expected io.EOF at end, got %v", name, err)
+	}
+}
+
+func mustURL(t *testing.T, s string) dashreader.ChunkURL {
+	ret, err := url.Parse(s)
+	if err != nil {
+		t.Fatalf("URL %v : %v", s, err)
+	}
+	return dashreader.ChunkURL{ChunkURL: *ret}
+}
+
+func TestStaticSegmentList(t *testing.T) {
+	file := "test/static_seglist.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	withRange := func(u dashreader.ChunkURL, r string, d time.Duration) dashreader.ChunkURL {
+		u.Range = r
+		u.Duration = d
+		return u
+	}
+	tests := []struct {
+		contentType string
+		exp         []dashreader.ChunkURL
+	}{
+		{
+			contentType: "video",
+			exp: []dashreader.ChunkURL{
+				mustURL(t, "http://127.0.0.1/vod/v/init.mp4"),
+				withRange(mustURL(t, "http://127.0.0.1/vod/v/1.m4s"), "", 2*time.Second),
+				withRange(mustURL(t, "http://127.0.0.1/vod/v/2.m4s"), "", 2*time.Second),
+				withRange(mustURL(t, "http://127.0.0.1/vod/v/3.m4s"), "", 2*time.Second),
+			},
+		},
+		{
+			contentType: "audio",
+			exp: []dashreader.ChunkURL{
+				withRange(mustURL(t, "http://127.0.0.1/vod/audio.mp4"), "0-99", 0),
+				withRange(mustURL(t, "http://127.0.0.1/vod/audio.mp4"), "100-599", 2*time.Second),
+				withRange(mustURL(t, "http://127.0.0.1/vod/audio.mp4"), "600-1099", 2*time.Second),
+				withRange(mustURL(t, "http://127.0.0.1/vod/audio.mp4"), "1100-1599", 2*time.Second),
+			},
+		},
+	}
+	for _, test := range tests {
+		streamSelector := dashreader.StreamSelector{ID: "1", ContentType: test.contentType}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", test.contentType, err)
+		}
+		checkURLs(t, test.contentType, readCtx, test.exp)
+		_, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("%v: Expected io.EOF after last Period, got %v", test.contentType, err)
+		}
+	}
+}
+
+func TestSegmentListWithoutDuration(t *testing.T) {
+	readMPD := func(segmentURLs int) *dashreader.MPDtype {
+		mpd, err := dashreader.ReadMPDFromFile("test/static_seglist.mpd")
+		if err != nil {
+			t.Fatalf("Error reading : %v", err)
+		}
+		mpd.PublishTime = time.Now().Add(time.Duration(segmentURLs) * time.Second)
+		mpd.Period[0].Duration = "PT6S"
+		segList := &mpd.Period[0].AdaptationSet[0].Representation[0].SegmentList
+		segList.Duration = 0
+		segList.SegmentURL = segList.SegmentURL[:segmentURLs]
+		return mpd
+	}
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "audio"}
+	//Single SegmentURL spans the Period
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", readMPD(1))
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	init := mustURL(t, "http://127.0.0.1/vod/audio.mp4")
+	init.Range = "0-99"
+	seg := mustURL(t, "http://127.0.0.1/vod/audio.mp4")
+	seg.Range = "100-599"
+	seg.Duration = 6 * time.Second
+	checkURLs(t, "single SegmentURL", readCtx, []dashreader.ChunkURL{init, seg})
+	//Many SegmentURLs need @duration or SegmentTimeline
+	if _, err := rdr.Update(readMPD(3)); err != nil {
+		t.Fatalf("Update failed : %v", err)
+	}
+	if _, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{}); err == nil {
+		t.Errorf("Expected error for SegmentURLs without SegmentList.Duration or SegmentTimeline")
+	}
+}
+
+func TestLiveSegmentListUpdate(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/live_seglist.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	rdr.SetStatzAgg(events)
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	seg := func(s string, end time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		u.FetchAt = ast.Add(end)
+		return u
+	}
+	//All segments are in the past, live point is the last one
+	checkURLs(t, "live", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/v/init.mp4"),
+		seg("http://127.0.0.1/live/v/5.m4s", 10*time.Second),
+	})
+	mpd, err = dashreader.ReadMPDFromFile("test/live_seglist_upd.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	if updated, err := rdr.Update(mpd); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context after update : %v", err)
+	}
+	checkURLs(t, "live update", readCtx, []dashreader.ChunkURL{
+		seg("http://127.0.0.1/live/v/6.m4s", 12*time.Second),
+		seg("http://127.0.0.1/live/v/7.m4s", 14*time.Second),
+	})
+	//Period replaced, position not kept
+	data, err := ioutil.ReadFile("test/live_seglist_upd.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	updated := strings.Replace(string(data), "publishTime=\"2020-01-01T00:00:14Z\"", "publishTime=\"2020-01-01T00:00:16Z\"", 1)
+	updated = strings.Replace(updated, "<Period id=\"p0\"", "<Period id=\"p1\"", 1)
+	mpd, err = dashreader.ReadMPDFromStream(strings.NewReader(updated))
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	if updated, err := rdr.Update(mpd); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context after Period change : %v", err)
+	}
+	if len(events.find(dashreader.EvtMPDUpdateAdjustFailed)) != 1 {
+		t.Errorf("Expected 1 %v event, got %v", dashreader.EvtMPDUpdateAdjustFailed, len(events.find(dashreader.EvtMPDUpdateAdjustFailed)))
+	}
+	//Live point located again in the new Period
+	checkURLs(t, "period change", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/live/v/init.mp4"),
+		seg("http://127.0.0.1/live/v/7.m4s", 14*time.Second),
+	})
+}
@@ -0,0 +1,49 @@
+package dashreader
+
+import (
+	"fmt"
+	"io"
+)
+
+//readerStaticTemplate - Implement addressing of MPD
+//  * Static
+//  * SegmentTemplate with SegmentTimeline ($Time$ or $Number$)
+//  * SegmentTemplate@duration ($Number$)
+type readerStaticTemplate struct {
+}
+
+//makeContext - Makes Reader Context
+// Parameters:
+//   1: Reader fixed values
+//   2: Current MPD
+//   3: Update counter of Current MPD
+//   4: Context received earlier... if first time pass nil
+//   5: StreamSelector for the ContentType to select AdaptationSet
+//   6: RepresentationSelector ... selector for Representation
+//   7: ContextOptions
+// Return:
+//   1: Context for current AdaptationSet,Representation
+//   2: error (io.EOF once all Periods are served)
+func (r *readerStaticTemplate) makeContext(reader readerBase, curMpd *MPDtype, updCounter int64, rdrCtx ReaderContext, streamSelector StreamSelector, repSelector RepresentationSelector, options ContextOptions) (ReaderContext, error) {
+	ctx, err := prepareContext(reader, rdrCtx, &readerStaticTemplateContext{periodIndex: -1}, streamSelector, repSelector, options)
+	if err != nil {
+		return nil, err
+	}
+	curContext := ctx.(*readerStaticTemplateContext)
+	curContext.updCounter = updCounter
+	if rdrCtx != nil && !curContext.isPeriodDone() {
+		//Current period still has URLs, Representation switched if selection changed
+		curContext.reselect(reader, curMpd, curContext)
+		return curContext, nil
+	}
+	//Move to next Period
+	periodIndex := curContext.periodIndex + 1
+	if periodIndex >= len(curMpd.Period) {
+		return curContext, fmt.Errorf("All Periods(%v) served: %w", len(curMpd.Period), io.EOF)
+	}
+	err = curContext.loadPeriod(reader, curMpd, periodIndex)
+	if err != nil {
+		return curContext, fmt.Errorf("Period(%v) load failed: %w", periodIndex, err)
+	}
+	return curContext, nil
+}
@@ -0,0 +1,246 @@
+package dashreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+//templateSegment - Segment described by SegmentTemplate
+type templateSegment struct {
+	number   uint64 //value for $Number$
+	start    uint64 //media time in ticks, value for $Time$
+	duration uint64 //duration in ticks
+}
+
+//readerStaticTemplateContext - readerStaticTemplate Context
+type readerStaticTemplateContext struct {
+	readerBaseContext
+
+	periodIndex int          //index of the Period being served
+	timescale   uint         //timescale - Ticks per sec
+	pto         uint64       //PresentationTimeOffset in ticks
+	initURL     url.URL      //url for init
+	initRange   string       //range Header for init
+	baseURL     url.URL      //Base url of Representation
+	media       *URLTemplate //SegmentTemplate@media
+
+	binitURLServed bool              //init URL pending to be returned
+	segments       []templateSegment //segments of the Period
+	curSegment     int               //index of next segment to return
+}
+
+//getSegmentTemplate - SegmentTemplate that applies to Representation
+// Representation values override AdaptationSet values which override Period values
+// @startNumber absent at every level is 1, written by newMPDDecoder
+func getSegmentTemplate(period *PeriodType, adapt *AdaptationSetType, rp *RepresentationType) *SegmentTemplateType {
+	ret := mergeSegmentTemplate(mergeSegmentTemplate(period.SegmentTemplate, adapt.SegmentTemplate), rp.SegmentTemplate)
+	return &ret
+}
+
+//buildTemplateSegments - Expand SegmentTimeline or @duration till end of Period
+// Parameters:
+//   1: SegmentTemplate
+//   2: Period duration, ZERO if not known
+// Return:
+//   1: segments
+//   2: error
+func buildTemplateSegments(segTemplate *SegmentTemplateType, periodDuration time.Duration) ([]templateSegment, error) {
+	timescale := segTemplate.Timescale
+	if timescale == 0 {
+		timescale = 1
+	}
+	pto := segTemplate.PresentationTimeOffset
+	//Period end in media time
+	var endTicks uint64
+	if periodDuration > 0 {
+		endTicks = pto + uint64(periodDuration.Seconds()*float64(timescale)+0.5)
+	}
+	ret := []templateSegment{}
+	number := uint64(segTemplate.StartNumber)
+	timeline := segTemplate.SegmentTimeline.S
+	if len(timeline) <= 0 {
+		if segTemplate.Duration == 0 {
+			return nil, fmt.Errorf("SegmentTemplate.Duration or SegmentTemplate.SegmentTimeline MUST be present")
+		}
+		if endTicks == 0 {
+			return nil, fmt.Errorf("Period duration MUST be known for SegmentTemplate.Duration")
+		}
+		for start := pto; start < endTicks; start += uint64(segTemplate.Duration) {
+			ret = append(ret, templateSegment{number: number, start: start, duration: uint64(segTemplate.Duration)})
+			number++
+		}
+		return ret, nil
+	}
+	var start uint64
+	for i, entry := range timeline {
+		if i == 0 || entry.T != 0 {
+			start = entry.T
+		}
+		if entry.D == 0 {
+			return nil, fmt.Errorf("SegmentTimeline.S(%v) with ZERO duration", i)
+		}
+		repeatCount := uint64(entry.R + 1)
+		if entry.R < 0 {
+			//Repeat till next entry or end of Period
+			switch {
+			case i+1 < len(timeline) && timeline[i+1].T > start:
+				repeatCount = (timeline[i+1].T - start + entry.D - 1) / entry.D
+			case endTicks > start:
+				repeatCount = (endTicks - start + entry.D - 1) / entry.D
+			default:
+				return nil, fmt.Errorf("SegmentTimeline.S(%v)@r=%v without end", i, entry.R)
+			}
+		}
+		for j := uint64(0); j < repeatCount; j++ {
+			if endTicks > 0 && start >= endTicks {
+				//Content beyond end of Period
+				return ret, nil
+			}
+			ret = append(ret, templateSegment{number: number, start: start, duration: entry.D})
+			number++
+			start += entry.D
+		}
+	}
+	return ret, nil
+}
+
+//isPeriodDone - All URLs of the Period are returned
+func (c *readerStaticTemplateContext) isPeriodDone() bool {
+	return c.binitURLServed && c.curSegment >= len(c.segments)
+}
+
+//loadPeriod - Select the Representation in the Period and expand its segments
+func (c *readerStaticTemplateContext) loadPeriod(reader readerBase, curMpd *MPDtype, periodIndex int) error {
+	period := &curMpd.Period[periodIndex]
+	if err := c.selectPeriod(reader, period); err != nil {
+		return fmt.Errorf("For Period(%v) No AdaptationSet selection found : %v", period.Id, err)
+	}
+	if err := c.loadRepresentation(reader, curMpd, periodIndex); err != nil {
+		return err
+	}
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	c.curSegment = 0
+	return nil
+}
+
+//loadRepresentation - Initialize fields from the selected Representation and expand its segments
+func (c *readerStaticTemplateContext) loadRepresentation(reader readerBase, curMpd *MPDtype, periodIndex int) error {
+	period := &curMpd.Period[periodIndex]
+	adapt, rp, err := c.locateRepresentation(reader, period)
+	if err != nil {
+		return err
+	}
+	timing := getPeriodTimings(reader, curMpd)[periodIndex]
+	var periodDuration time.Duration
+	if IsPresentTime(timing.end) {
+		periodDuration = timing.end.Sub(timing.start)
+	}
+	segTemplate := &rp.SegmentTemplate
+	segments, err := buildTemplateSegments(segTemplate, periodDuration)
+	if err != nil {
+		return fmt.Errorf("Representation(%v) %v", rp.ID, err)
+	}
+	c.setContentFields(adapt, rp)
+	c.periodIndex = periodIndex
+	c.timescale = segTemplate.Timescale
+	if c.timescale == 0 {
+		c.timescale = 1
+	}
+	c.pto = segTemplate.PresentationTimeOffset
+	c.initURL, c.initRange, c.media, err = c.loadTemplate(segTemplate, rp.BaseURL)
+	if err != nil {
+		return err
+	}
+	c.baseURL = rp.BaseURL
+	c.segments = segments
+	return nil
+}
+
+//segmentEnd - End of segment from start of Period
+func (c *readerStaticTemplateContext) segmentEnd(seg *templateSegment) time.Duration {
+	if seg.start+seg.duration < c.pto {
+		return 0
+	}
+	return ticksToDuration(seg.start+seg.duration-c.pto, c.timescale)
+}
+
+//servedPeriod - Period at periodIndex
+func (c *readerStaticTemplateContext) servedPeriod(reader readerBase, curMpd *MPDtype) (*PeriodType, periodTiming) {
+	if c.periodIndex < 0 || c.periodIndex >= len(curMpd.Period) {
+		return nil, periodTiming{index: -1}
+	}
+	return &curMpd.Period[c.periodIndex], getPeriodTimings(reader, curMpd)[c.periodIndex]
+}
+
+//switchRepresentation - Serve repID from the segment containing start of next segment
+func (c *readerStaticTemplateContext) switchRepresentation(reader readerBase, curMpd *MPDtype, period *PeriodType, timing periodTiming, repID StringNoWhitespaceType) error {
+	prev := *c
+	var next time.Duration
+	if c.curSegment > 0 && c.curSegment <= len(c.segments) {
+		next = c.segmentEnd(&c.segments[c.curSegment-1])
+	}
+	c.repID = repID
+	if err := c.loadRepresentation(reader, curMpd, timing.index); err != nil {
+		*c = prev
+		return err
+	}
+	c.curSegment = len(c.segments)
+	for i := range c.segments {
+		if c.segmentEnd(&c.segments[i]) > next {
+			c.curSegment = i
+			break
+		}
+	}
+	c.binitURLServed = len(c.initURL.Path) <= 0
+	return nil
+}
+
+//NextURLs - Get URLs from Current MPD context
+//-- Once end of this list is reached
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   context for cancellation
+// Return:
+//   1: Channel of URLs, can be read till closed
+//   2: error
+func (c *readerStaticTemplateContext) NextURLs(ctx context.Context) (ret <-chan ChunkURL, err error) {
+	return c.getURLs(ctx, ReaderContext(c))
+}
+
+//NextURL -
+//-- Once end of Period is reached (io.EOF)
+//-- MakeDASHReaderContext has to be called again
+// Parameters;
+//   None
+// Return:
+//   1: Next URL (FetchAt ZERO)
+//   2: error
+func (c *readerStaticTemplateContext) NextURL() (*ChunkURL, error) {
+	if !c.binitURLServed {
+		c.binitURLServed = true
+		return c.relocateChunk(&ChunkURL{
+			ChunkURL: c.initURL,
+			Range:    c.initRange,
+		}), nil
+	}
+	if c.curSegment >= len(c.segments) {
+		return nil, io.EOF
+	}
+	seg := &c.segments[c.curSegment]
+	values := c.templateValues()
+	values.Number = seg.number
+	values.Time = seg.start
+	chunkURL, err := c.media.resolve(c.baseURL, values)
+	if err != nil {
+		return nil, fmt.Errorf("Segment(%v) url has error: %v", seg.number, err)
+	}
+	ret := &ChunkURL{
+		ChunkURL: chunkURL,
+		Duration: ticksToDuration(seg.duration, c.timescale),
+	}
+	c.curSegment++
+	return c.relocateChunk(ret), nil
+}
@@ -0,0 +1,97 @@
+package dashreader_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestStaticTemplate(t *testing.T) {
+	file := "test/static_template.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	seg := func(s string, d time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = d
+		return u
+	}
+	tests := []struct {
+		contentType string
+		exp         [][]dashreader.ChunkURL
+	}{
+		{
+			contentType: "video",
+			exp: [][]dashreader.ChunkURL{
+				{
+					mustURL(t, "http://127.0.0.1/vod/p0/V300/init.mp4"),
+					seg("http://127.0.0.1/vod/p0/V300/t0.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p0/V300/t2000.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p0/V300/t4000.m4s", 2*time.Second),
+				},
+				{
+					mustURL(t, "http://127.0.0.1/vod/p1/V300/init.mp4"),
+					seg("http://127.0.0.1/vod/p1/V300/t9000.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p1/V300/t11000.m4s", 2*time.Second),
+				},
+			},
+		},
+		{
+			contentType: "audio",
+			exp: [][]dashreader.ChunkURL{
+				{ //@startNumber absent, numbering starts at 1
+					mustURL(t, "http://127.0.0.1/vod/p0/A64/init.mp4"),
+					seg("http://127.0.0.1/vod/p0/A64/1.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p0/A64/2.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p0/A64/3.m4s", 2*time.Second),
+				},
+				{
+					mustURL(t, "http://127.0.0.1/vod/p1/A64/init.mp4"),
+					seg("http://127.0.0.1/vod/p1/A64/64000_001.m4s", 2*time.Second),
+					seg("http://127.0.0.1/vod/p1/A64/64000_002.m4s", 2*time.Second),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		streamSelector := dashreader.StreamSelector{ID: "1", ContentType: test.contentType}
+		var readCtx dashreader.ReaderContext
+		for i, exp := range test.exp {
+			readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+			if err != nil {
+				t.Fatalf("%v: Period %v Error getting context : %v", test.contentType, i, err)
+			}
+			checkURLs(t, test.contentType, readCtx, exp)
+		}
+		_, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("%v: Expected io.EOF after last Period, got %v", test.contentType, err)
+		}
+	}
+	//Wall clock is not used for static
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	for {
+		chunkURL, err := readCtx.NextURL()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error getting URL : %v", err)
+		}
+		if !chunkURL.FetchAt.IsZero() {
+			t.Errorf("URL %v FetchAt Exp: ZERO Act: %v", chunkURL.ChunkURL.String(), chunkURL.FetchAt)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package dashreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//fixedRepresentationSelector - Selects rep whatever is available
+type fixedRepresentationSelector struct {
+	rep *dashreader.RepresentationType
+}
+
+func (s fixedRepresentationSelector) SelectRepresentation(reps []*dashreader.RepresentationType) *dashreader.RepresentationType {
+	return s.rep
+}
+
+//nextURLs - URLs returned next, io.EOF not expected after
+func nextURLs(t *testing.T, name string, readCtx dashreader.ReaderContext, exp []string) {
+	for i, e := range exp {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("%v: URL %v error : %v", name, i, err)
+		}
+		if chunkURL.ChunkURL.String() != e {
+			t.Errorf("%v: URL %v Exp: %v Act: %v", name, i, e, chunkURL.ChunkURL.String())
+		}
+	}
+}
+
+func TestSwitchRepresentation(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mpd, err := dashreader.ReadMPDFromFile("test/live_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	clock := dashreader.NewFakeClock(ast.Add(10 * time.Second))
+	factory := dashreader.ReaderFactory{Clock: clock}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := &eventCapture{}
+	rdr.SetStatzAgg(events)
+	if _, err := rdr.SwitchRepresentation(nil, "V1000"); err == nil {
+		t.Errorf("Expected error for switch without context")
+	}
+
+	//SegmentTimeline
+	video := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, video, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("video: Error getting context : %v", err)
+	}
+	nextURLs(t, "video", readCtx, []string{"http://127.0.0.1/live/V300/init.mp4", "http://127.0.0.1/live/V300/t8000.m4s"})
+	readCtx, err = rdr.SwitchRepresentation(readCtx, "V1000")
+	if err != nil {
+		t.Fatalf("video: Switch failed : %v", err)
+	}
+	if readCtx.GetCodecs() != "avc1.64001f" {
+		t.Errorf("video: Codecs Exp: avc1.64001f Act: %v", readCtx.GetCodecs())
+	}
+	nextURLs(t, "switch", readCtx, []string{"http://127.0.0.1/live/V1000/init.mp4", "http://127.0.0.1/live/V1000/t10000.m4s"})
+	//Not in AdaptationSet, context unchanged
+	for _, id := range []string{"V9", "A64"} {
+		ret, err := rdr.SwitchRepresentation(readCtx, id)
+		if err == nil || ret != readCtx {
+			t.Errorf("video: Switch to %v Exp: error and same context Act: %v", id, err)
+		}
+	}
+	//Same Representation, nothing repeated
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "V1000"); err != nil {
+		t.Fatalf("video: Switch failed : %v", err)
+	}
+	nextURLs(t, "same", readCtx, []string{"http://127.0.0.1/live/V1000/t12000.m4s"})
+
+	//Switch after MPD update, position kept in the updated timeline
+	clock.Set(ast.Add(22 * time.Second))
+	mpdUpd, err := dashreader.ReadMPDFromFile("test/live_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	mpdUpd.PublishTime = ast.Add(22 * time.Second)
+	mpdUpd.Period[0].AdaptationSet[0].SegmentTemplate.SegmentTimeline.S[0].R = 10
+	if updated, err := rdr.Update(mpdUpd); !updated || err != nil {
+		t.Fatalf("Update failed %v %v", updated, err)
+	}
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "V3000"); err != nil {
+		t.Fatalf("video: Switch failed : %v", err)
+	}
+	selector := dashreader.MaxBWRepresentationSelector{}
+	if readCtx, err = rdr.MakeDASHReaderContext(readCtx, video, selector); err != nil {
+		t.Fatalf("video: Error getting context : %v", err)
+	}
+	nextURLs(t, "update", readCtx, []string{
+		"http://127.0.0.1/live/V3000/init.mp4",
+		"http://127.0.0.1/live/V3000/t14000.m4s",
+		"http://127.0.0.1/live/V3000/t16000.m4s",
+		"http://127.0.0.1/live/V3000/t18000.m4s",
+		"http://127.0.0.1/live/V3000/t20000.m4s",
+	})
+
+	//SegmentTemplate@duration
+	clock.Set(ast.Add(10 * time.Second))
+	audio := dashreader.StreamSelector{ID: "1", ContentType: "audio"}
+	readCtx, err = rdr.MakeDASHReaderContext(nil, audio, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	nextURLs(t, "audio", readCtx, []string{"http://127.0.0.1/live/A64/init.mp4", "http://127.0.0.1/live/A64/5.m4s", "http://127.0.0.1/live/A64/6.m4s"})
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "A128"); err != nil {
+		t.Fatalf("audio: Switch failed : %v", err)
+	}
+	nextURLs(t, "audio switch", readCtx, []string{"http://127.0.0.1/live/A128/init.mp4", "http://127.0.0.1/live/A128/7.m4s"})
+	//Selection not in AdaptationSet, selection unchanged
+	missing := fixedRepresentationSelector{rep: &dashreader.RepresentationType{Id: "A9"}}
+	if readCtx, err = rdr.MakeDASHReaderContext(readCtx, audio, missing); err != nil {
+		t.Fatalf("audio: Error getting context : %v", err)
+	}
+	nextURLs(t, "audio reselect", readCtx, []string{"http://127.0.0.1/live/A128/8.m4s"})
+	if evts := events.find(dashreader.EvtRepresentationReselectFailed); len(evts) != 1 || evts[0].Values[0] != dashreader.StringNoWhitespaceType("A9") {
+		t.Errorf("Expected 1 %v event for A9, got %v", dashreader.EvtRepresentationReselectFailed, evts)
+	}
+
+	if evts := events.find(dashreader.EvtRepresentationSwitch); len(evts) != 3 {
+		t.Errorf("Expected 3 %v events, got %v", dashreader.EvtRepresentationSwitch, len(evts))
+	}
+}
+
+func TestSwitchRepresentationStatic(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	//Before any URL, init of the Representation switched to
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "V1000"); err != nil {
+		t.Fatalf("Switch failed : %v", err)
+	}
+	nextURLs(t, "start", readCtx, []string{"http://127.0.0.1/vod/V1000/init.mp4", "http://127.0.0.1/vod/V1000/t0.m4s"})
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "V300"); err != nil {
+		t.Fatalf("Switch failed : %v", err)
+	}
+	seg := func(s string) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		return u
+	}
+	checkURLs(t, "static", readCtx, []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/vod/V300/init.mp4"),
+		seg("http://127.0.0.1/vod/V300/t2000.m4s"),
+		seg("http://127.0.0.1/vod/V300/t4000.m4s"),
+		seg("http://127.0.0.1/vod/V300/t6000.m4s"),
+	})
+}
+
+func TestSwitchRepresentationSegmentList(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_seglist.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	//Second audio Representation in its own file
+	audio := &mpd.Period[0].AdaptationSet[0]
+	rep := audio.Representation[0]
+	rep.Id = "A128"
+	rep.Bandwidth = 128000
+	rep.BaseURL = []dashreader.BaseURLType{{Value: "audio128.mp4"}}
+	audio.Representation = append(audio.Representation, rep)
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "audio"}, dashreader.MinBWRepresentationSelector{})
+	if err != nil {
+		t.Fatalf("Error getting context : %v", err)
+	}
+	nextURLs(t, "A64", readCtx, []string{"http://127.0.0.1/vod/audio.mp4", "http://127.0.0.1/vod/audio.mp4"})
+	if readCtx, err = rdr.SwitchRepresentation(readCtx, "A128"); err != nil {
+		t.Fatalf("Switch failed : %v", err)
+	}
+	withRange := func(s string, r string, d time.Duration) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Range = r
+		u.Duration = d
+		return u
+	}
+	checkURLs(t, "A128", readCtx, []dashreader.ChunkURL{
+		withRange("http://127.0.0.1/vod/audio128.mp4", "0-99", 0),
+		withRange("http://127.0.0.1/vod/audio128.mp4", "600-1099", 2*time.Second),
+		withRange("http://127.0.0.1/vod/audio128.mp4", "1100-1599", 2*time.Second),
+	})
+}
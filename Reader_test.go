@@ -5,7 +5,6 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"strconv"
@@ -19,15 +18,10 @@ import (
 )
 
 func getMPD(t *testing.T, url string) (mpd *dashreader.MPDtype, err error) {
-	resp, err := http.Get(url)
+	fetcher := dashreader.MPDFetcher{URL: url}
+	mpd, _, err = fetcher.Fetch(context.TODO())
 	if err != nil {
 		t.Errorf("Error getting \"%v\" : %v", url, err)
-		return
-	}
-	defer resp.Body.Close()
-	mpd, err = dashreader.ReadMPDFromStream(resp.Body)
-	if err != nil {
-		t.Errorf("Error reading %s:%v", url, err)
 	}
 	return
 }
@@ -25,7 +25,7 @@ func (s MinBWRepresentationSelector) SelectRepresentation(reps []*Representation
 }
 
 //MaxBWRepresentationSelector -
-//Selects the minimum of the available BW
+//Selects the maximum of the available BW
 type MaxBWRepresentationSelector struct {
 }
 
@@ -34,7 +34,7 @@ func (s MaxBWRepresentationSelector) SelectRepresentation(reps []*Representation
 	var ret *RepresentationType
 	var bandwidth uint = 0
 	for _, r := range reps {
-		if r.Bandwidth < bandwidth {
+		if ret == nil || r.Bandwidth > bandwidth {
 			bandwidth = r.Bandwidth
 			ret = r
 		}
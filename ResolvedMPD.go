@@ -0,0 +1,445 @@
+package dashreader
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ResolvedMPD - MPD with DASH inheritance rules applied
+// Every Representation carries its effective attributes,
+// Segment addressing and BaseURL
+type ResolvedMPD struct {
+	MPD      *MPDtype           //Source MPD
+	BaseURL  url.URL            //MPD BaseURL resolved with MPD url
+	BaseURLs []BaseURLCandidate //All MPD BaseURLs resolved with MPD url
+	Periods  []ResolvedPeriod   //Periods in MPD order
+}
+
+//ResolvedPeriod - Period with inheritance applied
+type ResolvedPeriod struct {
+	Period         *PeriodType             //Source Period
+	Index          int                     //index of Period in MPD
+	ID             string                  //Period@id
+	Start          time.Duration           //PeriodStart from AvailabilityStartTime (static: from presentation start)
+	Duration       time.Duration           //Period duration, ZERO if not known
+	BaseURL        url.URL                 //BaseURL resolved till Period
+	BaseURLs       []BaseURLCandidate      //All BaseURLs resolved till Period
+	AdaptationSets []ResolvedAdaptationSet //AdaptationSets in Period order
+}
+
+//ResolvedAdaptationSet - AdaptationSet with inheritance applied
+type ResolvedAdaptationSet struct {
+	AdaptationSet   *AdaptationSetType       //Source AdaptationSet
+	ID              uint                     //AdaptationSet@id
+	ContentType     string                   //@contentType, from @mimeType if absent
+	Lang            string                   //@lang
+	BaseURL         url.URL                  //BaseURL resolved till AdaptationSet
+	BaseURLs        []BaseURLCandidate       //All BaseURLs resolved till AdaptationSet
+	Representations []ResolvedRepresentation //Representations in AdaptationSet order
+}
+
+//ResolvedRepresentation - Representation with inheritance applied
+type ResolvedRepresentation struct {
+	Representation    *RepresentationType    //Source Representation
+	ID                StringNoWhitespaceType //Representation@id
+	Bandwidth         uint                   //@bandwidth
+	Codecs            string                 //@codecs
+	MimeType          string                 //@mimeType
+	FrameRate         FrameRateType          //@frameRate
+	Width             uint                   //@width
+	Height            uint                   //@height
+	Sar               RatioType              //@sar
+	ScanType          VideoScanType          //@scanType
+	AudioSamplingRate string                 //@audioSamplingRate
+	MaxPlayoutRate    float64                //@maxPlayoutRate
+	BaseURL           url.URL                //BaseURL resolved till Representation
+	BaseURLs          []BaseURLCandidate     //All BaseURLs resolved till Representation, BaseURL first
+	SegmentBase       SegmentBaseType        //effective SegmentBase
+	SegmentList       SegmentListType        //effective SegmentList
+	SegmentTemplate   SegmentTemplateType    //effective SegmentTemplate
+	InbandEventStream []EventStreamType      //InbandEventStreams of AdaptationSet and Representation
+}
+
+//ResolveMPD - Apply the DASH inheritance rules on MPD
+// Parameters:
+//   1: MPD read
+//   2: url the MPD was fetched from
+// Return:
+//   1: ResolvedMPD
+//   2: error
+func ResolveMPD(mpd *MPDtype, mpdURL url.URL) (*ResolvedMPD, error) {
+	baseURLs, err := resolveMPDBaseURLs(mpd, mpdURL)
+	if err != nil {
+		return nil, err
+	}
+	ret := &ResolvedMPD{
+		MPD:      mpd,
+		BaseURL:  baseURLs[0].URL,
+		BaseURLs: baseURLs,
+		Periods:  make([]ResolvedPeriod, len(mpd.Period)),
+	}
+	timings := getPeriodTimings(readerBase{}, mpd)
+	for i := range mpd.Period {
+		period, err := resolvePeriod(&mpd.Period[i], baseURLs)
+		if err != nil {
+			return nil, err
+		}
+		period.Index = i
+		period.Start = timings[i].start.Sub(time.Time{})
+		if IsPresentTime(timings[i].end) {
+			period.Duration = timings[i].end.Sub(timings[i].start)
+		}
+		ret.Periods[i] = *period
+	}
+	return ret, nil
+}
+
+//resolveMPDBaseURLs - MPD BaseURL set, MPD url directory if no BaseURL
+func resolveMPDBaseURLs(mpd *MPDtype, mpdURL url.URL) ([]BaseURLCandidate, error) {
+	rootURL, err := AdjustURLPath(mpdURL, []BaseURLType{}, relativeBasePathURL)
+	if err != nil {
+		return nil, fmt.Errorf("MPD.%w", err)
+	}
+	root := []BaseURLCandidate{{
+		URL:      *rootURL,
+		Priority: DVBDefaultPriority,
+		Weight:   DVBDefaultWeight,
+	}}
+	ret, err := resolveBaseURLs(root, mpd.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("MPD.%w", err)
+	}
+	return ret, nil
+}
+
+//resolvePeriod - Apply the DASH inheritance rules on Period
+// Start and Duration are not filled
+func resolvePeriod(period *PeriodType, mpdBaseURLs []BaseURLCandidate) (*ResolvedPeriod, error) {
+	periodBaseURLs, err := resolveBaseURLs(mpdBaseURLs, period.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("Adjusting to Period(%v) BaseURL has error: %v", period.Id, err)
+	}
+	ret := &ResolvedPeriod{
+		Period:         period,
+		Index:          -1,
+		ID:             period.Id,
+		BaseURL:        periodBaseURLs[0].URL,
+		BaseURLs:       periodBaseURLs,
+		AdaptationSets: make([]ResolvedAdaptationSet, len(period.AdaptationSet)),
+	}
+	for i := range period.AdaptationSet {
+		adapt, err := resolveAdaptationSet(period, &period.AdaptationSet[i], periodBaseURLs)
+		if err != nil {
+			return nil, err
+		}
+		ret.AdaptationSets[i] = *adapt
+	}
+	return ret, nil
+}
+
+//resolveAdaptationSet - Apply the DASH inheritance rules on AdaptationSet
+func resolveAdaptationSet(period *PeriodType, adapt *AdaptationSetType, periodBaseURLs []BaseURLCandidate) (*ResolvedAdaptationSet, error) {
+	adaptBaseURLs, err := resolveBaseURLs(periodBaseURLs, adapt.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("Adjusting to AdaptationSet(%v) BaseURL has error: %v", adapt.Id, err)
+	}
+	ret := &ResolvedAdaptationSet{
+		AdaptationSet:   adapt,
+		ID:              adapt.Id,
+		ContentType:     adapt.ContentType,
+		Lang:            adapt.Lang,
+		BaseURL:         adaptBaseURLs[0].URL,
+		BaseURLs:        adaptBaseURLs,
+		Representations: make([]ResolvedRepresentation, len(adapt.Representation)),
+	}
+	for i := range adapt.Representation {
+		rp := &adapt.Representation[i]
+		rpBaseURLs, err := resolveBaseURLs(adaptBaseURLs, rp.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("Adjusting to Representation(%v) BaseURL has error: %v", rp.Id, err)
+		}
+		ret.Representations[i] = ResolvedRepresentation{
+			Representation:    rp,
+			ID:                rp.Id,
+			Bandwidth:         rp.Bandwidth,
+			Codecs:            inheritString(adapt.Codecs, rp.Codecs),
+			MimeType:          inheritString(adapt.MimeType, rp.MimeType),
+			FrameRate:         FrameRateType(inheritString(string(adapt.FrameRate), string(rp.FrameRate))),
+			Width:             inheritUint(adapt.Width, rp.Width),
+			Height:            inheritUint(adapt.Height, rp.Height),
+			Sar:               RatioType(inheritString(string(adapt.Sar), string(rp.Sar))),
+			ScanType:          VideoScanType(inheritString(string(adapt.ScanType), string(rp.ScanType))),
+			AudioSamplingRate: inheritString(adapt.AudioSamplingRate, rp.AudioSamplingRate),
+			MaxPlayoutRate:    inheritFloat(adapt.MaxPlayoutRate, rp.MaxPlayoutRate),
+			BaseURL:           rpBaseURLs[0].URL,
+			BaseURLs:          rpBaseURLs,
+			SegmentBase:       *getSegmentBase(period, adapt, rp),
+			SegmentList:       *getSegmentList(period, adapt, rp),
+			SegmentTemplate:   *getSegmentTemplate(period, adapt, rp),
+			InbandEventStream: append(append([]EventStreamType{}, adapt.InbandEventStream...), rp.InbandEventStream...),
+		}
+		if len(ret.ContentType) <= 0 {
+			ret.ContentType = contentTypeFromMimeType(ret.Representations[i].MimeType)
+		}
+	}
+	if len(ret.ContentType) <= 0 {
+		ret.ContentType = contentTypeFromMimeType(adapt.MimeType)
+	}
+	return ret, nil
+}
+
+//addressing - Segment addressing of Representation
+func (r *ResolvedRepresentation) addressing() addressingMode {
+	if len(r.SegmentList.SegmentURL) > 0 {
+		return addressingSegmentList
+	}
+	if len(r.SegmentTemplate.Media) > 0 {
+		if len(r.SegmentTemplate.SegmentTimeline.S) > 0 {
+			return addressingTemplateTimeline
+		}
+		return addressingTemplateDuration
+	}
+	return addressingSegmentBase
+}
+
+//hasAddressing - Any Representation of AdaptationSet uses the Segment addressing
+func (a *ResolvedAdaptationSet) hasAddressing(mode addressingMode) bool {
+	for i := range a.Representations {
+		if a.Representations[i].addressing() == mode {
+			return true
+		}
+	}
+	return false
+}
+
+//contentTypeFromMimeType - "video/mp4" -> "video"
+func contentTypeFromMimeType(mimeType string) string {
+	if i := strings.IndexByte(mimeType, '/'); i > 0 {
+		return mimeType[:i]
+	}
+	return ""
+}
+
+//inheritString - child value if present else parent value
+func inheritString(parent string, child string) string {
+	if len(child) > 0 {
+		return child
+	}
+	return parent
+}
+
+//inheritUint - child value if present else parent value
+func inheritUint(parent uint, child uint) uint {
+	if child != 0 {
+		return child
+	}
+	return parent
+}
+
+//inheritUint64 - child value if present else parent value
+func inheritUint64(parent uint64, child uint64) uint64 {
+	if child != 0 {
+		return child
+	}
+	return parent
+}
+
+//inheritFloat - child value if present else parent value
+func inheritFloat(parent float64, child float64) float64 {
+	if child != 0 {
+		return child
+	}
+	return parent
+}
+
+//segmentAttr - Attribute of SegmentBase, SegmentList or SegmentTemplate
+type segmentAttr struct {
+	element string //local name of element
+	attr    string //local name of attribute
+}
+
+//inheritedSegmentAttrs - Attributes taken from the same element of the upper level when absent,
+// default when absent at every level
+var inheritedSegmentAttrs = []struct {
+	segmentAttr
+	value string //default
+}{
+	{segmentAttr{"SegmentBase", "availabilityTimeComplete"}, "true"},
+	{segmentAttr{"SegmentList", "availabilityTimeComplete"}, "true"},
+	{segmentAttr{"SegmentList", "startNumber"}, "1"},
+	{segmentAttr{"SegmentTemplate", "availabilityTimeComplete"}, "true"},
+	{segmentAttr{"SegmentTemplate", "startNumber"}, "1"},
+}
+
+//segmentAttrReader - Writes the value in effect of absent inheritedSegmentAttrs on the element
+// A present element then carries its value, an absent one (ZERO value) takes the upper level
+type segmentAttrReader struct {
+	r      xml.TokenReader
+	levels []map[segmentAttr]string //attributes set by Segment elements, per open element
+}
+
+//Token - Next token, Segment elements with inheritedSegmentAttrs written
+func (r *segmentAttrReader) Token() (xml.Token, error) {
+	token, err := r.r.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case xml.StartElement:
+		if t.Name.Space == mpdNamespace {
+			for _, v := range inheritedSegmentAttrs {
+				if v.element != t.Name.Local {
+					continue
+				}
+				value, ok := segmentAttrValue(t, v.attr)
+				if !ok {
+					value = r.inherited(v.segmentAttr, v.value)
+					t.Attr = append(t.Attr[:len(t.Attr):len(t.Attr)], xml.Attr{Name: xml.Name{Local: v.attr}, Value: value})
+				}
+				level := r.levels[len(r.levels)-1]
+				if level == nil {
+					level = map[segmentAttr]string{}
+					r.levels[len(r.levels)-1] = level
+				}
+				level[v.segmentAttr] = value
+			}
+		}
+		r.levels = append(r.levels, nil)
+		return t, nil
+	case xml.EndElement:
+		if len(r.levels) > 1 {
+			r.levels = r.levels[:len(r.levels)-1]
+		}
+	}
+	return token, nil
+}
+
+//inherited - Value set by the nearest upper level, default if none
+func (r *segmentAttrReader) inherited(attr segmentAttr, value string) string {
+	for i := len(r.levels) - 1; i >= 0; i-- {
+		if v, ok := r.levels[i][attr]; ok {
+			return v
+		}
+	}
+	return value
+}
+
+//segmentAttrValue - Value of attribute without namespace
+func segmentAttrValue(start xml.StartElement, name string) (string, bool) {
+	for _, a := range start.Attr {
+		if len(a.Name.Space) <= 0 && a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+//segmentAttrsBelow - inheritedSegmentAttrs in effect below a level, for remote elements decoded there
+// Parameters:
+//   1: attributes in effect at the level, nil at Period
+//   2-4: Segment elements of the level
+func segmentAttrsBelow(upper map[segmentAttr]string, base SegmentBaseType, list SegmentListType, template SegmentTemplateType) map[segmentAttr]string {
+	ret := make(map[segmentAttr]string, len(inheritedSegmentAttrs))
+	for k, v := range upper {
+		ret[k] = v
+	}
+	if !reflect.ValueOf(base).IsZero() {
+		ret[segmentAttr{"SegmentBase", "availabilityTimeComplete"}] = strconv.FormatBool(base.AvailabilityTimeComplete)
+	}
+	if !reflect.ValueOf(list).IsZero() {
+		ret[segmentAttr{"SegmentList", "availabilityTimeComplete"}] = strconv.FormatBool(list.AvailabilityTimeComplete)
+		ret[segmentAttr{"SegmentList", "startNumber"}] = strconv.FormatUint(uint64(list.StartNumber), 10)
+	}
+	if !reflect.ValueOf(template).IsZero() {
+		ret[segmentAttr{"SegmentTemplate", "availabilityTimeComplete"}] = strconv.FormatBool(template.AvailabilityTimeComplete)
+		ret[segmentAttr{"SegmentTemplate", "startNumber"}] = strconv.FormatUint(uint64(template.StartNumber), 10)
+	}
+	return ret
+}
+
+//inheritURL - child value if present else parent value
+func inheritURL(parent URLType, child URLType) URLType {
+	if len(child.SourceURL) > 0 || len(child.Range) > 0 {
+		return child
+	}
+	return parent
+}
+
+//mergeSegmentBase - SegmentBase of child level with missing values from parent level
+func mergeSegmentBase(parent SegmentBaseType, child SegmentBaseType) SegmentBaseType {
+	if reflect.ValueOf(child).IsZero() {
+		//Element absent at child level
+		return parent
+	}
+	ret := child
+	ret.Initialization = inheritURL(parent.Initialization, child.Initialization)
+	ret.RepresentationIndex = inheritURL(parent.RepresentationIndex, child.RepresentationIndex)
+	ret.Timescale = inheritUint(parent.Timescale, child.Timescale)
+	ret.PresentationTimeOffset = inheritUint64(parent.PresentationTimeOffset, child.PresentationTimeOffset)
+	ret.IndexRange = inheritString(parent.IndexRange, child.IndexRange)
+	ret.IndexRangeExact = parent.IndexRangeExact || child.IndexRangeExact
+	ret.AvailabilityTimeOffset = inheritFloat(parent.AvailabilityTimeOffset, child.AvailabilityTimeOffset)
+	return ret
+}
+
+//mergeSegmentList - SegmentList of child level with missing values from parent level
+func mergeSegmentList(parent SegmentListType, child SegmentListType) SegmentListType {
+	if reflect.ValueOf(child).IsZero() {
+		//Element absent at child level
+		return parent
+	}
+	ret := child
+	ret.Initialization = inheritURL(parent.Initialization, child.Initialization)
+	ret.RepresentationIndex = inheritURL(parent.RepresentationIndex, child.RepresentationIndex)
+	ret.BitstreamSwitching = inheritURL(parent.BitstreamSwitching, child.BitstreamSwitching)
+	//@duration and SegmentTimeline are exclusive, child choice wins
+	if len(child.SegmentTimeline.S) <= 0 && child.Duration == 0 {
+		ret.SegmentTimeline = parent.SegmentTimeline
+	}
+	if len(child.SegmentTimeline.S) <= 0 {
+		ret.Duration = parent.Duration
+	}
+	if len(child.SegmentURL) <= 0 {
+		ret.SegmentURL = parent.SegmentURL
+	}
+	ret.Duration = inheritUint(ret.Duration, child.Duration)
+	ret.Timescale = inheritUint(parent.Timescale, child.Timescale)
+	ret.PresentationTimeOffset = inheritUint64(parent.PresentationTimeOffset, child.PresentationTimeOffset)
+	ret.IndexRange = inheritString(parent.IndexRange, child.IndexRange)
+	ret.AvailabilityTimeOffset = inheritFloat(parent.AvailabilityTimeOffset, child.AvailabilityTimeOffset)
+	return ret
+}
+
+//mergeSegmentTemplate - SegmentTemplate of child level with missing values from parent level
+func mergeSegmentTemplate(parent SegmentTemplateType, child SegmentTemplateType) SegmentTemplateType {
+	if reflect.ValueOf(child).IsZero() {
+		//Element absent at child level
+		return parent
+	}
+	ret := child
+	ret.Initialization = inheritURL(parent.Initialization, child.Initialization)
+	ret.RepresentationIndex = inheritURL(parent.RepresentationIndex, child.RepresentationIndex)
+	ret.BitstreamSwitching = inheritURL(parent.BitstreamSwitching, child.BitstreamSwitching)
+	//@duration and SegmentTimeline are exclusive, child choice wins
+	if len(child.SegmentTimeline.S) <= 0 && child.Duration == 0 {
+		ret.SegmentTimeline = parent.SegmentTimeline
+	}
+	if len(child.SegmentTimeline.S) <= 0 {
+		ret.Duration = parent.Duration
+	}
+	ret.Media = inheritString(parent.Media, child.Media)
+	ret.Index = inheritString(parent.Index, child.Index)
+	ret.InitializationAttr = inheritString(parent.InitializationAttr, child.InitializationAttr)
+	ret.BitstreamSwitchingAttr = inheritString(parent.BitstreamSwitchingAttr, child.BitstreamSwitchingAttr)
+	ret.Duration = inheritUint(ret.Duration, child.Duration)
+	ret.Timescale = inheritUint(parent.Timescale, child.Timescale)
+	ret.PresentationTimeOffset = inheritUint64(parent.PresentationTimeOffset, child.PresentationTimeOffset)
+	ret.IndexRange = inheritString(parent.IndexRange, child.IndexRange)
+	ret.AvailabilityTimeOffset = inheritFloat(parent.AvailabilityTimeOffset, child.AvailabilityTimeOffset)
+	return ret
+}
@@ -0,0 +1,127 @@
+package dashreader_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestResolveMPD(t *testing.T) {
+	file := "test/static_inherit.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	mpdURL, _ := url.Parse("http://127.0.0.1/manifest.mpd")
+	resolved, err := dashreader.ResolveMPD(mpd, *mpdURL)
+	if err != nil {
+		t.Fatalf("Error resolving : %v", err)
+	}
+	if len(resolved.Periods) != 1 || resolved.Periods[0].Duration != 6*time.Second {
+		t.Fatalf("Period Exp: 1 of 6s Act: %v", resolved.Periods)
+	}
+	adapts := resolved.Periods[0].AdaptationSets
+	if len(adapts) != 2 {
+		t.Fatalf("AdaptationSets Exp: 2 Act: %v", len(adapts))
+	}
+	video := adapts[0].Representations
+	if video[0].Codecs != "avc1.64001e" || video[0].MimeType != "video/mp4" || video[0].FrameRate != "30" {
+		t.Errorf("V300 attributes not inherited: %v %v %v", video[0].Codecs, video[0].MimeType, video[0].FrameRate)
+	}
+	if video[1].Codecs != "avc1.64001f" {
+		t.Errorf("V800 codecs Exp: avc1.64001f Act: %v", video[1].Codecs)
+	}
+	if video[0].BaseURL.String() != "http://127.0.0.1/vod/p0/video/" {
+		t.Errorf("V300 BaseURL Exp: http://127.0.0.1/vod/p0/video/ Act: %v", video[0].BaseURL.String())
+	}
+	if video[0].SegmentTemplate.Media != "$RepresentationID$/$Number$.m4s" || video[0].SegmentTemplate.Duration != 2000 || video[0].SegmentTemplate.StartNumber != 1 {
+		t.Errorf("V300 SegmentTemplate not inherited from Period: %+v", video[0].SegmentTemplate)
+	}
+	if video[1].SegmentTemplate.StartNumber != 10 || video[1].SegmentTemplate.Timescale != 1000 {
+		t.Errorf("V800 SegmentTemplate Exp: startNumber 10 timescale 1000 Act: %v %v", video[1].SegmentTemplate.StartNumber, video[1].SegmentTemplate.Timescale)
+	}
+	if video[2].SegmentTemplate.StartNumber != 0 {
+		t.Errorf("V1200 SegmentTemplate Exp: startNumber 0 Act: %v", video[2].SegmentTemplate.StartNumber)
+	}
+	//@availabilityTimeComplete true if absent, explicit false kept
+	for i, exp := range []bool{true, false, true} {
+		if act := video[i].SegmentTemplate.AvailabilityTimeComplete; act != exp {
+			t.Errorf("%v availabilityTimeComplete Exp: %v Act: %v", video[i].ID, exp, act)
+		}
+	}
+	if adapts[1].ContentType != "audio" {
+		t.Errorf("ContentType from mimeType Exp: audio Act: %v", adapts[1].ContentType)
+	}
+	audio := adapts[1].Representations[0]
+	if audio.SegmentTemplate.Media != "$RepresentationID$/a$Number$.m4s" || audio.SegmentTemplate.InitializationAttr != "$RepresentationID$/init.mp4" {
+		t.Errorf("A64 SegmentTemplate Act: %+v", audio.SegmentTemplate)
+	}
+}
+
+//idRepresentationSelector - Selects the Representation with matching @id
+type idRepresentationSelector string
+
+func (s idRepresentationSelector) SelectRepresentation(reps []*dashreader.RepresentationType) *dashreader.RepresentationType {
+	for _, r := range reps {
+		if string(r.Id) == string(s) {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestReaderInheritance(t *testing.T) {
+	file := "test/static_inherit.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	seg := func(s string) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		return u
+	}
+	tests := []struct {
+		repSelector dashreader.RepresentationSelector
+		codecs      string
+		exp         []dashreader.ChunkURL
+	}{
+		{
+			repSelector: dashreader.MinBWRepresentationSelector{},
+			codecs:      "avc1.64001e",
+			exp: []dashreader.ChunkURL{
+				mustURL(t, "http://127.0.0.1/vod/p0/video/V300/init.mp4"),
+				seg("http://127.0.0.1/vod/p0/video/V300/1.m4s"),
+				seg("http://127.0.0.1/vod/p0/video/V300/2.m4s"),
+				seg("http://127.0.0.1/vod/p0/video/V300/3.m4s"),
+			},
+		},
+		{
+			repSelector: idRepresentationSelector("V800"),
+			codecs:      "avc1.64001f",
+			exp: []dashreader.ChunkURL{
+				mustURL(t, "http://127.0.0.1/vod/p0/video/V800/init.mp4"),
+				seg("http://127.0.0.1/vod/p0/video/V800/10.m4s"),
+				seg("http://127.0.0.1/vod/p0/video/V800/11.m4s"),
+				seg("http://127.0.0.1/vod/p0/video/V800/12.m4s"),
+			},
+		},
+	}
+	for _, test := range tests {
+		readCtx, err := rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: "video"}, test.repSelector)
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", test.codecs, err)
+		}
+		if readCtx.GetCodecs() != test.codecs || readCtx.GetFramerate() != 30 {
+			t.Errorf("Codecs/FrameRate Exp: %v/30 Act: %v/%v", test.codecs, readCtx.GetCodecs(), readCtx.GetFramerate())
+		}
+		checkURLs(t, test.codecs, readCtx, test.exp)
+	}
+}
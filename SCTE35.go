@@ -0,0 +1,524 @@
+package dashreader
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+//EventStream@schemeIdUri carrying SCTE-35 cues
+const (
+	SCTE35SchemeXML2013    = "urn:scte:scte35:2013:xml"     //Event has scte35:SpliceInfoSection
+	SCTE35SchemeXMLBin2014 = "urn:scte:scte35:2014:xml+bin" //Event has scte35:Signal/scte35:Binary
+	SCTE35SchemeBin2013    = "urn:scte:scte35:2013:bin"     //binary splice_info_section (emsg)
+)
+
+//SCTE-35 splice_command_type
+const (
+	SCTE35SpliceNull           = 0x00
+	SCTE35SpliceSchedule       = 0x04
+	SCTE35SpliceInsertCommand  = 0x05
+	SCTE35TimeSignalCommand    = 0x06
+	SCTE35BandwidthReservation = 0x07
+	SCTE35PrivateCommand       = 0xFF
+)
+
+const (
+	scte35TableID               = 0xFC
+	scte35SegmentationTag       = 0x02
+	scte35Identifier            = 0x43554549 //"CUEI"
+	scte35PTSMask               = 0x1FFFFFFFF
+	scte35Timescale             = 90000
+	scte35SpliceCommandLenUnset = 0xFFF
+)
+
+//SCTE35SpliceInfo - SCTE-35 splice_info_section, binary or XML (scte35:SpliceInfoSection)
+type SCTE35SpliceInfo struct {
+	ProtocolVersion         uint8                          `xml:"protocolVersion,attr,omitempty"`
+	PTSAdjustment           uint64                         `xml:"ptsAdjustment,attr,omitempty"`
+	Tier                    uint16                         `xml:"tier,attr,omitempty"`
+	SpliceCommandType       uint8                          `xml:"-"`
+	SpliceInsert            *SCTE35SpliceInsert            `xml:"SpliceInsert,omitempty"`
+	TimeSignal              *SCTE35TimeSignal              `xml:"TimeSignal,omitempty"`
+	SegmentationDescriptors []SCTE35SegmentationDescriptor `xml:"SegmentationDescriptor,omitempty"`
+}
+
+//SCTE35SpliceTime - splice_time(), PTSTime nil if time_specified_flag is 0
+type SCTE35SpliceTime struct {
+	PTSTime *uint64 `xml:"ptsTime,attr,omitempty"`
+}
+
+//SCTE35TimeSignal - time_signal()
+type SCTE35TimeSignal struct {
+	SpliceTime SCTE35SpliceTime `xml:"SpliceTime"`
+}
+
+//SCTE35Program - Program splice (program_splice_flag 1)
+type SCTE35Program struct {
+	SpliceTime SCTE35SpliceTime `xml:"SpliceTime"`
+}
+
+//SCTE35Component - Component splice (program_splice_flag 0)
+type SCTE35Component struct {
+	ComponentTag uint8            `xml:"componentTag,attr"`
+	SpliceTime   SCTE35SpliceTime `xml:"SpliceTime"`
+}
+
+//SCTE35BreakDuration - break_duration(), 90kHz
+type SCTE35BreakDuration struct {
+	AutoReturn bool   `xml:"autoReturn,attr"`
+	Duration   uint64 `xml:"duration,attr"`
+}
+
+//SCTE35SpliceInsert - splice_insert()
+type SCTE35SpliceInsert struct {
+	SpliceEventID              uint32               `xml:"spliceEventId,attr"`
+	SpliceEventCancelIndicator bool                 `xml:"spliceEventCancelIndicator,attr,omitempty"`
+	OutOfNetworkIndicator      bool                 `xml:"outOfNetworkIndicator,attr,omitempty"`
+	SpliceImmediateFlag        bool                 `xml:"spliceImmediateFlag,attr,omitempty"`
+	UniqueProgramID            uint16               `xml:"uniqueProgramId,attr,omitempty"`
+	AvailNum                   uint8                `xml:"availNum,attr,omitempty"`
+	AvailsExpected             uint8                `xml:"availsExpected,attr,omitempty"`
+	Program                    *SCTE35Program       `xml:"Program,omitempty"`
+	Component                  []SCTE35Component    `xml:"Component,omitempty"`
+	BreakDuration              *SCTE35BreakDuration `xml:"BreakDuration,omitempty"`
+}
+
+//SCTE35DeliveryRestrictions - Restrictions of segmentation_descriptor (delivery_not_restricted_flag 0)
+type SCTE35DeliveryRestrictions struct {
+	WebDeliveryAllowedFlag bool  `xml:"webDeliveryAllowedFlag,attr"`
+	NoRegionalBlackoutFlag bool  `xml:"noRegionalBlackoutFlag,attr"`
+	ArchiveAllowedFlag     bool  `xml:"archiveAllowedFlag,attr"`
+	DeviceRestrictions     uint8 `xml:"deviceRestrictions,attr"`
+}
+
+//SCTE35SegmentationUpid - segmentation_upid, Value in hex
+type SCTE35SegmentationUpid struct {
+	SegmentationUpidType   uint8  `xml:"segmentationUpidType,attr"`
+	SegmentationUpidFormat string `xml:"segmentationUpidFormat,attr,omitempty"`
+	Value                  string `xml:",chardata"`
+}
+
+//SCTE35SegmentationDescriptor - segmentation_descriptor()
+type SCTE35SegmentationDescriptor struct {
+	SegmentationEventID              uint32                      `xml:"segmentationEventId,attr"`
+	SegmentationEventCancelIndicator bool                        `xml:"segmentationEventCancelIndicator,attr,omitempty"`
+	SegmentationDuration             *uint64                     `xml:"segmentationDuration,attr,omitempty"`
+	SegmentationTypeID               uint8                       `xml:"segmentationTypeId,attr"`
+	SegmentNum                       uint8                       `xml:"segmentNum,attr"`
+	SegmentsExpected                 uint8                       `xml:"segmentsExpected,attr"`
+	SubSegmentNum                    uint8                       `xml:"subSegmentNum,attr,omitempty"`
+	SubSegmentsExpected              uint8                       `xml:"subSegmentsExpected,attr,omitempty"`
+	DeliveryRestrictions             *SCTE35DeliveryRestrictions `xml:"DeliveryRestrictions,omitempty"`
+	SegmentationUpid                 []SCTE35SegmentationUpid    `xml:"SegmentationUpid,omitempty"`
+}
+
+//SCTE35Signal - scte35:Signal, binary (base64) or XML splice_info_section
+type SCTE35Signal struct {
+	Binary            string            `xml:"Binary,omitempty"`
+	SpliceInfoSection *SCTE35SpliceInfo `xml:"SpliceInfoSection,omitempty"`
+}
+
+//IsSCTE35Scheme - EventStream@schemeIdUri carries SCTE-35?
+func IsSCTE35Scheme(schemeIdUri string) bool {
+	switch schemeIdUri {
+	case SCTE35SchemeXML2013, SCTE35SchemeXMLBin2014, SCTE35SchemeBin2013:
+		return true
+	}
+	return false
+}
+
+//DecodeSCTE35Event - SCTE-35 cue of an EventStream Event
+//  * scte35:Signal with scte35:Binary (base64) or scte35:SpliceInfoSection
+//  * scte35:SpliceInfoSection
+//  * Event@messageData base64 binary
+// Return:
+//   1: cue, not shared with the Event
+//   2: error
+func DecodeSCTE35Event(event *EventType) (*SCTE35SpliceInfo, error) {
+	for _, item := range event.Items {
+		info, err := decodeSCTE35Element(item)
+		if info != nil || err != nil {
+			return info, err
+		}
+	}
+	if len(event.MessageData) > 0 {
+		return ParseSCTE35Base64(event.MessageData)
+	}
+	return nil, fmt.Errorf("Event(%v) has no SCTE-35 cue", event.Id)
+}
+
+//decodeSCTE35Element - Cue of Event content element, XML as kept in EventType.Items
+// Return:
+//   1: cue, nil if element is not a cue
+//   2: error
+func decodeSCTE35Element(content string) (*SCTE35SpliceInfo, error) {
+	d := xml.NewDecoder(strings.NewReader(content))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("SCTE-35 XML decode failed: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "Signal":
+			var signal SCTE35Signal
+			if err := d.DecodeElement(&signal, &start); err != nil {
+				return nil, fmt.Errorf("SCTE-35 Signal decode failed: %w", err)
+			}
+			if len(strings.TrimSpace(signal.Binary)) > 0 {
+				return ParseSCTE35Base64(signal.Binary)
+			}
+			if signal.SpliceInfoSection != nil {
+				return copySCTE35XML(signal.SpliceInfoSection), nil
+			}
+		case "SpliceInfoSection":
+			var info SCTE35SpliceInfo
+			if err := d.DecodeElement(&info, &start); err != nil {
+				return nil, fmt.Errorf("SCTE-35 SpliceInfoSection decode failed: %w", err)
+			}
+			return copySCTE35XML(&info), nil
+		}
+		return nil, nil
+	}
+}
+
+//copySCTE35XML - Copy of XML cue with SpliceCommandType set
+func copySCTE35XML(info *SCTE35SpliceInfo) *SCTE35SpliceInfo {
+	ret := *info
+	switch {
+	case ret.SpliceInsert != nil:
+		ret.SpliceCommandType = SCTE35SpliceInsertCommand
+	case ret.TimeSignal != nil:
+		ret.SpliceCommandType = SCTE35TimeSignalCommand
+	default:
+		ret.SpliceCommandType = SCTE35SpliceNull
+	}
+	return &ret
+}
+
+//ParseSCTE35Base64 - splice_info_section in base64
+func ParseSCTE35Base64(value string) (*SCTE35SpliceInfo, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("SCTE-35 base64 invalid : %w", err)
+	}
+	return ParseSCTE35(data)
+}
+
+//ParseSCTE35 - Decode binary splice_info_section
+// splice_insert, time_signal and segmentation descriptors are decoded,
+// other commands and descriptors are skipped. Encrypted sections are not supported
+// Parameters:
+//   1: bytes from table_id till CRC_32
+// Return:
+//   1: cue
+//   2: error
+func ParseSCTE35(data []byte) (*SCTE35SpliceInfo, error) {
+	if len(data) < 3 {
+		return nil, fmt.Errorf("SCTE-35 section needs atleast 3 bytes, %v available", len(data))
+	}
+	if data[0] != scte35TableID {
+		return nil, fmt.Errorf("SCTE-35 table_id 0x%02X MUST be 0x%02X", data[0], scte35TableID)
+	}
+	sectionLength := int(binary.BigEndian.Uint16(data[1:3]) & 0x0FFF)
+	if 3+sectionLength > len(data) {
+		return nil, fmt.Errorf("SCTE-35 section_length %v beyond %v bytes", sectionLength, len(data))
+	}
+	data = data[:3+sectionLength]
+	if crc := scte35CRC32(data); crc != 0 {
+		return nil, fmt.Errorf("SCTE-35 CRC_32 mismatch")
+	}
+	r := &bitReader{data: data[3 : len(data)-4]}
+	ret := &SCTE35SpliceInfo{}
+	ret.ProtocolVersion = uint8(r.read(8))
+	if encrypted := r.read(1); encrypted != 0 {
+		return nil, fmt.Errorf("SCTE-35 encrypted_packet not supported")
+	}
+	r.read(6) //encryption_algorithm
+	ret.PTSAdjustment = r.read(33)
+	r.read(8) //cw_index
+	ret.Tier = uint16(r.read(12))
+	commandLength := int(r.read(12))
+	ret.SpliceCommandType = uint8(r.read(8))
+	commandStart := r.pos
+	switch ret.SpliceCommandType {
+	case SCTE35SpliceInsertCommand:
+		ret.SpliceInsert = readSCTE35SpliceInsert(r)
+	case SCTE35TimeSignalCommand:
+		ret.TimeSignal = &SCTE35TimeSignal{SpliceTime: readSCTE35SpliceTime(r)}
+	case SCTE35SpliceNull:
+	default:
+		if commandLength == scte35SpliceCommandLenUnset {
+			return nil, fmt.Errorf("SCTE-35 splice_command_type 0x%02X needs splice_command_length", ret.SpliceCommandType)
+		}
+	}
+	if commandLength != scte35SpliceCommandLenUnset {
+		r.pos = commandStart + commandLength*8
+	}
+	descriptorLength := int(r.read(16))
+	if r.err != nil {
+		return nil, fmt.Errorf("SCTE-35 splice command truncated")
+	}
+	end := r.pos/8 + descriptorLength
+	if end > len(r.data) {
+		return nil, fmt.Errorf("SCTE-35 descriptor_loop_length %v truncated", descriptorLength)
+	}
+	for r.pos/8+2 <= end {
+		tag := uint8(r.read(8))
+		length := int(r.read(8))
+		next := r.pos + length*8
+		if r.pos/8+length > end {
+			return nil, fmt.Errorf("SCTE-35 descriptor(0x%02X) length %v truncated", tag, length)
+		}
+		if tag == scte35SegmentationTag && length >= 4 && r.read(32) == scte35Identifier {
+			desc, err := readSCTE35Segmentation(&bitReader{data: r.data[r.pos/8 : next/8]})
+			if err != nil {
+				return nil, err
+			}
+			ret.SegmentationDescriptors = append(ret.SegmentationDescriptors, *desc)
+		}
+		r.pos = next
+	}
+	return ret, nil
+}
+
+//readSCTE35SpliceTime - splice_time()
+func readSCTE35SpliceTime(r *bitReader) SCTE35SpliceTime {
+	var ret SCTE35SpliceTime
+	if r.read(1) == 1 {
+		r.read(6)
+		pts := r.read(33)
+		ret.PTSTime = &pts
+	} else {
+		r.read(7)
+	}
+	return ret
+}
+
+//readSCTE35SpliceInsert - splice_insert()
+func readSCTE35SpliceInsert(r *bitReader) *SCTE35SpliceInsert {
+	ret := &SCTE35SpliceInsert{}
+	ret.SpliceEventID = uint32(r.read(32))
+	ret.SpliceEventCancelIndicator = r.read(1) == 1
+	r.read(7)
+	if ret.SpliceEventCancelIndicator {
+		return ret
+	}
+	ret.OutOfNetworkIndicator = r.read(1) == 1
+	programSplice := r.read(1) == 1
+	durationFlag := r.read(1) == 1
+	ret.SpliceImmediateFlag = r.read(1) == 1
+	r.read(4)
+	if programSplice {
+		ret.Program = &SCTE35Program{}
+		if !ret.SpliceImmediateFlag {
+			ret.Program.SpliceTime = readSCTE35SpliceTime(r)
+		}
+	} else {
+		count := int(r.read(8))
+		for i := 0; i < count; i++ {
+			component := SCTE35Component{ComponentTag: uint8(r.read(8))}
+			if !ret.SpliceImmediateFlag {
+				component.SpliceTime = readSCTE35SpliceTime(r)
+			}
+			ret.Component = append(ret.Component, component)
+		}
+	}
+	if durationFlag {
+		ret.BreakDuration = &SCTE35BreakDuration{AutoReturn: r.read(1) == 1}
+		r.read(6)
+		ret.BreakDuration.Duration = r.read(33)
+	}
+	ret.UniqueProgramID = uint16(r.read(16))
+	ret.AvailNum = uint8(r.read(8))
+	ret.AvailsExpected = uint8(r.read(8))
+	return ret
+}
+
+//readSCTE35Segmentation - segmentation_descriptor() after identifier
+func readSCTE35Segmentation(r *bitReader) (*SCTE35SegmentationDescriptor, error) {
+	ret := &SCTE35SegmentationDescriptor{}
+	ret.SegmentationEventID = uint32(r.read(32))
+	ret.SegmentationEventCancelIndicator = r.read(1) == 1
+	r.read(7)
+	if !ret.SegmentationEventCancelIndicator {
+		programSegmentation := r.read(1) == 1
+		durationFlag := r.read(1) == 1
+		if deliveryNotRestricted := r.read(1) == 1; deliveryNotRestricted {
+			r.read(5)
+		} else {
+			ret.DeliveryRestrictions = &SCTE35DeliveryRestrictions{
+				WebDeliveryAllowedFlag: r.read(1) == 1,
+				NoRegionalBlackoutFlag: r.read(1) == 1,
+				ArchiveAllowedFlag:     r.read(1) == 1,
+				DeviceRestrictions:     uint8(r.read(2)),
+			}
+		}
+		if !programSegmentation {
+			//component_tag(8) reserved(7) pts_offset(33)
+			count := int(r.read(8))
+			r.pos += count * 48
+		}
+		if durationFlag {
+			duration := r.read(40)
+			ret.SegmentationDuration = &duration
+		}
+		upid := SCTE35SegmentationUpid{SegmentationUpidType: uint8(r.read(8)), SegmentationUpidFormat: "hexbinary"}
+		length := int(r.read(8))
+		if r.pos/8+length <= len(r.data) {
+			upid.Value = strings.ToUpper(hex.EncodeToString(r.data[r.pos/8 : r.pos/8+length]))
+		}
+		r.pos += length * 8
+		ret.SegmentationUpid = append(ret.SegmentationUpid, upid)
+		ret.SegmentationTypeID = uint8(r.read(8))
+		ret.SegmentNum = uint8(r.read(8))
+		ret.SegmentsExpected = uint8(r.read(8))
+		//sub_segment_num and sub_segments_expected are optional in older encoders
+		if r.err == nil && r.pos/8+2 <= len(r.data) {
+			ret.SubSegmentNum = uint8(r.read(8))
+			ret.SubSegmentsExpected = uint8(r.read(8))
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("SCTE-35 segmentation_descriptor(%v) truncated", ret.SegmentationEventID)
+	}
+	return ret, nil
+}
+
+//SpliceTime - PTS of splice_insert (program) or time_signal with pts_adjustment
+// Return:
+//   1: PTS 90kHz
+//   2: splice time present?
+func (s *SCTE35SpliceInfo) SpliceTime() (uint64, bool) {
+	var spliceTime *SCTE35SpliceTime
+	switch {
+	case s.SpliceInsert != nil && s.SpliceInsert.Program != nil:
+		spliceTime = &s.SpliceInsert.Program.SpliceTime
+	case s.SpliceInsert != nil && len(s.SpliceInsert.Component) > 0:
+		spliceTime = &s.SpliceInsert.Component[0].SpliceTime
+	case s.TimeSignal != nil:
+		spliceTime = &s.TimeSignal.SpliceTime
+	}
+	if spliceTime == nil || spliceTime.PTSTime == nil {
+		return 0, false
+	}
+	return (*spliceTime.PTSTime + s.PTSAdjustment) & scte35PTSMask, true
+}
+
+//BreakDuration - Duration of the ad break
+// splice_insert break_duration, else first segmentation_duration
+// Return:
+//   1: duration
+//   2: duration present?
+func (s *SCTE35SpliceInfo) BreakDuration() (time.Duration, bool) {
+	if s.SpliceInsert != nil && s.SpliceInsert.BreakDuration != nil {
+		return scte35Duration(s.SpliceInsert.BreakDuration.Duration), true
+	}
+	for _, desc := range s.SegmentationDescriptors {
+		if desc.SegmentationDuration != nil {
+			return scte35Duration(*desc.SegmentationDuration), true
+		}
+	}
+	return 0, false
+}
+
+//IsBreakStart - Cue starts an ad break / placement opportunity
+//  * splice_insert with out_of_network_indicator
+//  * segmentation_type_id of Break/Advertisement/Placement Opportunity Start
+func (s *SCTE35SpliceInfo) IsBreakStart() bool {
+	if s.SpliceInsert != nil && !s.SpliceInsert.SpliceEventCancelIndicator {
+		return s.SpliceInsert.OutOfNetworkIndicator
+	}
+	for _, desc := range s.SegmentationDescriptors {
+		if !desc.SegmentationEventCancelIndicator && isSCTE35BreakType(desc.SegmentationTypeID, true) {
+			return true
+		}
+	}
+	return false
+}
+
+//IsBreakEnd - Cue ends an ad break / placement opportunity
+//  * splice_insert without out_of_network_indicator (return to network)
+//  * segmentation_type_id of Break/Advertisement/Placement Opportunity End
+func (s *SCTE35SpliceInfo) IsBreakEnd() bool {
+	if s.SpliceInsert != nil && !s.SpliceInsert.SpliceEventCancelIndicator {
+		return !s.SpliceInsert.OutOfNetworkIndicator
+	}
+	for _, desc := range s.SegmentationDescriptors {
+		if !desc.SegmentationEventCancelIndicator && isSCTE35BreakType(desc.SegmentationTypeID, false) {
+			return true
+		}
+	}
+	return false
+}
+
+//isSCTE35BreakType - segmentation_type_id of break start or end
+// Break 0x22/0x23, Provider/Distributor Advertisement 0x30-0x33,
+// Provider/Distributor (Overlay) Placement Opportunity 0x34-0x3B,
+// Provider/Distributor Ad Block 0x44-0x47
+func isSCTE35BreakType(typeID uint8, start bool) bool {
+	switch {
+	case typeID == 0x22 || typeID == 0x23,
+		typeID >= 0x30 && typeID <= 0x3B,
+		typeID >= 0x44 && typeID <= 0x47:
+		//Start is even, End is odd
+		return (typeID%2 == 0) == start
+	}
+	return false
+}
+
+//scte35Duration - 90kHz ticks as Duration
+func scte35Duration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / scte35Timescale
+}
+
+//scte35CRC32 - CRC_32 of MPEG-2 sections, ZERO over a section with valid CRC_32
+func scte35CRC32(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = (crc << 1) ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+//bitReader - MSB first reader of bit fields
+type bitReader struct {
+	data []byte //bytes read
+	pos  int    //bit position
+	err  error  //set when reading past data
+}
+
+//read - Next n (<= 64) bits, ZERO past the end of data
+func (r *bitReader) read(n int) uint64 {
+	if r.pos+n > len(r.data)*8 {
+		r.err = fmt.Errorf("read %v bits at %v beyond %v bytes", n, r.pos, len(r.data))
+		r.pos += n
+		return 0
+	}
+	var ret uint64
+	for i := 0; i < n; i++ {
+		bit := (r.data[(r.pos+i)/8] >> (7 - uint((r.pos+i)%8))) & 1
+		ret = ret<<1 | uint64(bit)
+	}
+	r.pos += n
+	return ret
+}
@@ -0,0 +1,172 @@
+package dashreader_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+const (
+	//SCTE-35 2019 14.1 time_signal - Placement Opportunity Start
+	scte35TimeSignal = "/DA0AAAAAAAA///wBQb+cr0AUAAeAhxDVUVJSAAAjn/PAAGlmbAICAAAAAAsoKGKNAIAmsnRfg=="
+	//SCTE-35 2019 14.2 splice_insert
+	scte35SpliceInsert = "/DAvAAAAAAAA///wFAVIAACPf+/+c2nALv4AUsz1AAAAAAAKAAhDVUVJAAABNWLbowo="
+)
+
+func TestSCTE35Parse(t *testing.T) {
+	cue, err := dashreader.ParseSCTE35Base64(scte35TimeSignal)
+	if err != nil {
+		t.Fatalf("time_signal : %v", err)
+	}
+	if cue.SpliceCommandType != dashreader.SCTE35TimeSignalCommand || cue.TimeSignal == nil || cue.Tier != 0xFFF {
+		t.Fatalf("time_signal %+v", cue)
+	}
+	if pts, ok := cue.SpliceTime(); !ok || pts != 0x072BD0050 {
+		t.Errorf("time_signal pts Exp: %v Act: %v %v", 0x072BD0050, pts, ok)
+	}
+	if len(cue.SegmentationDescriptors) != 1 {
+		t.Fatalf("time_signal descriptors Exp: 1 Act: %v", len(cue.SegmentationDescriptors))
+	}
+	desc := cue.SegmentationDescriptors[0]
+	if desc.SegmentationEventID != 0x4800008E || desc.SegmentationTypeID != 0x34 || desc.SegmentNum != 2 ||
+		desc.SegmentationDuration == nil || *desc.SegmentationDuration != 27630000 ||
+		desc.DeliveryRestrictions == nil || !desc.DeliveryRestrictions.NoRegionalBlackoutFlag || desc.DeliveryRestrictions.DeviceRestrictions != 3 ||
+		len(desc.SegmentationUpid) != 1 || desc.SegmentationUpid[0].SegmentationUpidType != 8 || desc.SegmentationUpid[0].Value != "000000002CA0A18A" {
+		t.Errorf("segmentation_descriptor %+v", desc)
+	}
+	if d, ok := cue.BreakDuration(); !ok || d != 307*time.Second {
+		t.Errorf("time_signal duration Exp: 307s Act: %v %v", d, ok)
+	}
+	if !cue.IsBreakStart() || cue.IsBreakEnd() {
+		t.Errorf("time_signal Placement Opportunity Start Act: start %v end %v", cue.IsBreakStart(), cue.IsBreakEnd())
+	}
+
+	cue, err = dashreader.ParseSCTE35Base64(scte35SpliceInsert)
+	if err != nil {
+		t.Fatalf("splice_insert : %v", err)
+	}
+	insert := cue.SpliceInsert
+	if cue.SpliceCommandType != dashreader.SCTE35SpliceInsertCommand || insert == nil {
+		t.Fatalf("splice_insert %+v", cue)
+	}
+	if insert.SpliceEventID != 0x4800008F || !insert.OutOfNetworkIndicator || insert.SpliceImmediateFlag ||
+		insert.BreakDuration == nil || !insert.BreakDuration.AutoReturn || insert.BreakDuration.Duration != 0x00052CCF5 {
+		t.Errorf("splice_insert %+v", insert)
+	}
+	if pts, ok := cue.SpliceTime(); !ok || pts != 0x07369C02E {
+		t.Errorf("splice_insert pts Exp: %v Act: %v %v", 0x07369C02E, pts, ok)
+	}
+	if d, ok := cue.BreakDuration(); !ok || d != 60293566666*time.Nanosecond {
+		t.Errorf("splice_insert duration Exp: 60.293566666s Act: %v %v", d, ok)
+	}
+	if !cue.IsBreakStart() || cue.IsBreakEnd() {
+		t.Errorf("splice_insert out of network Act: start %v end %v", cue.IsBreakStart(), cue.IsBreakEnd())
+	}
+}
+
+func TestSCTE35ParseInvalid(t *testing.T) {
+	valid, _ := base64.StdEncoding.DecodeString(scte35SpliceInsert)
+	corrupt := append([]byte{}, valid...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	wrongTable := append([]byte{}, valid...)
+	wrongTable[0] = 0xFD
+	testCases := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"crc", corrupt, "CRC_32"},
+		{"table_id", wrongTable, "table_id"},
+		{"truncated", valid[:20], "section_length"},
+		{"short", valid[:2], "3 bytes"},
+	}
+	for _, tc := range testCases {
+		_, err := dashreader.ParseSCTE35(tc.data)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: Expected error with %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestSCTE35Events(t *testing.T) {
+	ast := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mpd, err := dashreader.ReadMPDFromFile("test/live_scte35.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	statz := &eventCapture{}
+	dispatcher := &dashreader.EventDispatcher{StatzAgg: statz}
+	factory := dashreader.ReaderFactory{Clock: dashreader.NewFakeClock(ast.Add(10 * time.Second)), EventDispatcher: dispatcher}
+	if _, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd); err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	events := dispatcher.Events()
+	if len(events) != 3 {
+		t.Fatalf("Events Exp: 3 Act: %v", len(events))
+	}
+	//scte35:Signal/scte35:Binary
+	evt := events[0]
+	if evt.ID != 1 || evt.SCTE35 == nil || evt.SCTE35.SpliceInsert == nil || !evt.SCTE35.IsBreakStart() {
+		t.Errorf("Event 1 %+v cue %+v", evt, evt.SCTE35)
+	}
+	//scte35:SpliceInfoSection
+	evt = events[1]
+	if evt.ID != 3 || evt.SCTE35 == nil || evt.SCTE35.SpliceCommandType != dashreader.SCTE35TimeSignalCommand || !evt.SCTE35.IsBreakStart() {
+		t.Fatalf("Event 3 %+v cue %+v", evt, evt.SCTE35)
+	}
+	if d, ok := evt.SCTE35.BreakDuration(); !ok || d != 30*time.Second {
+		t.Errorf("Event 3 duration Exp: 30s Act: %v %v", d, ok)
+	}
+	if pts, ok := evt.SCTE35.SpliceTime(); !ok || pts != 2000000 {
+		t.Errorf("Event 3 pts Exp: 2000000 Act: %v %v", pts, ok)
+	}
+	desc := evt.SCTE35.SegmentationDescriptors[0]
+	if desc.SegmentationEventID != 1207959694 || desc.DeliveryRestrictions == nil || !desc.DeliveryRestrictions.ArchiveAllowedFlag ||
+		len(desc.SegmentationUpid) != 1 || desc.SegmentationUpid[0].Value != "000000002CA0A18A" {
+		t.Errorf("Event 3 segmentation_descriptor %+v", desc)
+	}
+	//CRC_32 corrupted
+	evt = events[2]
+	if evt.ID != 2 || evt.SCTE35 != nil {
+		t.Errorf("Event 2 %+v", evt)
+	}
+	if len(statz.find(dashreader.EvtSCTE35DecodeFailed)) != 1 {
+		t.Errorf("Expected 1 %v event", dashreader.EvtSCTE35DecodeFailed)
+	}
+}
+
+func TestSCTE35EventEncode(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/live_scte35.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	//Event content kept as XML
+	event := &mpd.Period[0].EventStream[0].Event[0]
+	if len(event.Items) != 1 || !strings.HasPrefix(event.Items[0], "<Signal") {
+		t.Fatalf("Event Items %q", event.Items)
+	}
+	//Cues survive an encode, as done by MPD Patch
+	data, err := xml.Marshal(mpd)
+	if err != nil {
+		t.Fatalf("Error encoding : %v", err)
+	}
+	decoded, err := dashreader.ReadMPDFromStream(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error decoding : %v", err)
+	}
+	for i, stream := range decoded.Period[0].EventStream {
+		for j := range stream.Event {
+			exp, expErr := dashreader.DecodeSCTE35Event(&mpd.Period[0].EventStream[i].Event[j])
+			act, actErr := dashreader.DecodeSCTE35Event(&stream.Event[j])
+			if (expErr == nil) != (actErr == nil) || (exp == nil) != (act == nil) ||
+				(exp != nil && exp.SpliceCommandType != act.SpliceCommandType) {
+				t.Errorf("Event(%v) Exp: %+v %v Act: %+v %v", stream.Event[j].Id, exp, expErr, act, actErr)
+			}
+		}
+	}
+}
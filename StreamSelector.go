@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/PaesslerAG/gval"
 )
@@ -19,14 +21,84 @@ import (
 //Langs supported - regex of ISO 639-2 lang code
 //        if empty anything is accepted
 //        e.g. eng, spa
+//Widths, Heights supported (pixels) - expressions on w, h
+//        if empty anything is accepted
+//        e.g. <=1920, >=720
+//FrameRates supported - expressions on fps (@frameRate evaluated, 30000/1001 is 29.97)
+//        e.g. <=30
+//Sars supported - expressions on sar (@sar as ratio, 1:1 is 1)
+//        e.g. ==1
+//ScanTypes supported - expressions on scan
+//        e.g. =="progressive"
+//MaxPlayoutRates supported - expressions on mpr
+//        e.g. >=2
+//Video attributes are checked on AdaptationSet @maxWidth, @maxHeight, @maxFrameRate
+//or common attributes first, then on each Representation
+//Roles supported - urn:mpeg:dash:role:2011 values in preference order
+//        if empty anything is accepted, AdaptationSet without Role is main
+//        e.g. main, alternate
+//Accessibilities supported - Accessibility descriptors in preference order
+//        if empty anything is accepted
+//        empty SchemeIDURI is an AdaptationSet without Accessibility
+//Labels supported - regex of Label in preference order
+//        if empty anything is accepted
+//AdaptationSet matching an earlier entry is preferred, Roles first then
+//Accessibilities then Labels
 type StreamSelector struct {
-	ID          string   `json:"id,omitempty"`
-	ContentType string   `json:"contentType"`
-	BitRates    []string `json:"bitratesexprs,omitempty"`
-	Codecs      []string `json:"codecsregexs,omitempty"`
-	Langs       []string `json:"langsregexs,omitempty"`
+	ID              string              `json:"id,omitempty"`
+	ContentType     string              `json:"contentType"`
+	BitRates        []string            `json:"bitratesexprs,omitempty"`
+	Codecs          []string            `json:"codecsregexs,omitempty"`
+	Langs           []string            `json:"langsregexs,omitempty"`
+	Widths          []string            `json:"widthsexprs,omitempty"`
+	Heights         []string            `json:"heightsexprs,omitempty"`
+	FrameRates      []string            `json:"frameratesexprs,omitempty"`
+	Sars            []string            `json:"sarsexprs,omitempty"`
+	ScanTypes       []string            `json:"scantypesexprs,omitempty"`
+	MaxPlayoutRates []string            `json:"maxplayoutratesexprs,omitempty"`
+	Roles           []string            `json:"roles,omitempty"`
+	Accessibilities []DescriptorMatcher `json:"accessibilities,omitempty"`
+	Labels          []string            `json:"labelsregexs,omitempty"`
 }
 
+//DescriptorMatcher - Descriptor @schemeIdUri and regex of @value
+//Value if empty any @value is accepted
+type DescriptorMatcher struct {
+	SchemeIDURI string `json:"schemeIdUri,omitempty"`
+	Value       string `json:"valueregex,omitempty"`
+}
+
+//Role and Accessibility schemes
+const (
+	//RoleSchemeIDURI - DASH Role scheme, also used for Accessibility
+	RoleSchemeIDURI = "urn:mpeg:dash:role:2011"
+	//AudioPurposeSchemeIDURI - TV-Anytime AudioPurpose, DVB-DASH Accessibility
+	AudioPurposeSchemeIDURI = "urn:tva:metadata:cs:AudioPurposeCS:2007"
+	//CEA608SchemeIDURI - CEA-608 captions in the video, @value e.g. CC1=eng
+	CEA608SchemeIDURI = "urn:scte:dash:cc:cea-608:2015"
+	//CEA708SchemeIDURI - CEA-708 captions in the video
+	CEA708SchemeIDURI = "urn:scte:dash:cc:cea-708:2015"
+)
+
+//Role values of urn:mpeg:dash:role:2011
+const (
+	RoleMain        = "main"
+	RoleAlternate   = "alternate"
+	RoleCommentary  = "commentary"
+	RoleDub         = "dub"
+	RoleDescription = "description"
+	RoleCaption     = "caption"
+	RoleSubtitle    = "subtitle"
+)
+
+//Values of AudioPurposeSchemeIDURI
+const (
+	//AudioPurposeVisualImpaired - Audio description
+	AudioPurposeVisualImpaired = "1"
+	//AudioPurposeHardOfHearing - Clean audio
+	AudioPurposeHardOfHearing = "2"
+)
+
 const (
 	//MatchResultDontCare - don't care
 	MatchResultDontCare = -1
@@ -99,9 +171,161 @@ func (s *StreamSelector) IsMatch(adaptSet AdaptationSetType) int {
 	if ret1 > ret {
 		ret = ret1
 	}
+	ret1 = s.matchAttrs(adaptSet)
+	switch ret1 {
+	case MatchResultNotFound:
+		return ret1
+	case MatchResultPartial:
+		//Only some of the Representations are good
+		ret = ret1
+	case MatchResultFound:
+		if ret1 > ret {
+			ret = ret1
+		}
+	}
+	ret1 = s.matchPreferences(adaptSet)
+	switch ret1 {
+	case MatchResultNotFound:
+		return ret1
+	}
+	if ret1 > ret {
+		ret = ret1
+	}
 	return ret
 }
 
+//matchPreferences - finds if Role, Accessibility and Label present and required matches
+// adaptSet : AdaptationSet
+// return -
+//   -1 - Don't Care
+//    0 - Not Found
+//    2 - Full match
+func (s *StreamSelector) matchPreferences(adaptSet AdaptationSetType) int {
+	if len(s.Roles) == 0 && len(s.Accessibilities) == 0 && len(s.Labels) == 0 {
+		return MatchResultDontCare
+	}
+	for _, pref := range s.preferences(adaptSet) {
+		if pref < 0 {
+			return MatchResultNotFound
+		}
+	}
+	return MatchResultFound
+}
+
+//ComparePreference - Compares AdaptationSets on Roles, Accessibilities and Labels order
+// adaptSet : AdaptationSet
+// other : AdaptationSet compared to
+// return -
+//   -1 - adaptSet preferred
+//    0 - Same preference
+//    1 - other preferred
+func (s *StreamSelector) ComparePreference(adaptSet AdaptationSetType, other AdaptationSetType) int {
+	prefs := s.preferences(adaptSet)
+	otherPrefs := s.preferences(other)
+	for i := range prefs {
+		//Not found is last
+		pref, otherPref := uint(prefs[i]), uint(otherPrefs[i])
+		switch {
+		case pref < otherPref:
+			return -1
+		case pref > otherPref:
+			return 1
+		}
+	}
+	return 0
+}
+
+//preferences - Index of the first Roles, Accessibilities, Labels entry matching
+// adaptSet : AdaptationSet
+// return -
+//   index for each, 0 if there are no entries, -1 if none matches
+func (s *StreamSelector) preferences(adaptSet AdaptationSetType) []int {
+	return []int{
+		s.rolePreference(adaptSet),
+		s.accessibilityPreference(adaptSet),
+		s.labelPreference(adaptSet),
+	}
+}
+
+//rolePreference - Index of the first Roles entry in AdaptationSet Role
+func (s *StreamSelector) rolePreference(adaptSet AdaptationSetType) int {
+	if len(s.Roles) == 0 {
+		return 0
+	}
+	roles := []string{}
+	for _, role := range adaptSet.Role {
+		if role.SchemeIdUri == RoleSchemeIDURI {
+			roles = append(roles, role.Value)
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, RoleMain)
+	}
+	for i, pref := range s.Roles {
+		for _, role := range roles {
+			if role == pref {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//accessibilityPreference - Index of the first Accessibilities entry in AdaptationSet Accessibility
+func (s *StreamSelector) accessibilityPreference(adaptSet AdaptationSetType) int {
+	if len(s.Accessibilities) == 0 {
+		return 0
+	}
+	for i, pref := range s.Accessibilities {
+		if len(pref.SchemeIDURI) == 0 {
+			if len(adaptSet.Accessibility) == 0 {
+				return i
+			}
+			continue
+		}
+		for _, accessibility := range adaptSet.Accessibility {
+			if pref.isMatch(accessibility) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//labelPreference - Index of the first Labels entry matching AdaptationSet Label
+func (s *StreamSelector) labelPreference(adaptSet AdaptationSetType) int {
+	if len(s.Labels) == 0 {
+		return 0
+	}
+	for i, pref := range s.Labels {
+		re, err := regexp.Compile(pref)
+		if err != nil {
+			continue //Regular expression is not good
+		}
+		for _, label := range adaptSet.Label {
+			if re.MatchString(label.Value) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+//isMatch - Descriptor has the scheme and a matching value
+func (m DescriptorMatcher) isMatch(descriptor DescriptorType) bool {
+	if descriptor.SchemeIdUri != m.SchemeIDURI {
+		return false
+	}
+	if len(m.Value) == 0 {
+		return true
+	}
+	re, err := regexp.Compile(m.Value)
+	if err != nil {
+		return false //Regular expression is not good
+	}
+	return re.MatchString(descriptor.Value)
+}
+
 //matchLang - finds if lang present and required matches
 // adaptSet : AdaptationSet
 // codecExpected : Decoder codec supported
@@ -185,6 +409,16 @@ func (s *StreamSelector) IsMatchRepresentation(representation RepresentationType
 	if ret1 > ret {
 		ret = ret1
 	}
+	for _, attr := range s.attrFilters() {
+		ret1 = attr.matchRep(representation)
+		switch ret1 {
+		case MatchResultNotFound:
+			return ret1
+		}
+		if ret1 > ret {
+			ret = ret1
+		}
+	}
 	return ret
 }
 
@@ -200,19 +434,28 @@ func (s *StreamSelector) matchBitrateRep(representation RepresentationType) int
 	if len(s.BitRates) == 0 {
 		return MatchResultDontCare
 	}
-	const bitrateStr = "br"
-	rateValue := map[string]interface{}{
-		bitrateStr: representation.Bandwidth,
+	return matchExprs("br", representation.Bandwidth, s.BitRates)
+}
+
+//matchExprs - finds if value satisfies all the expressions
+// name : variable the expressions are on
+// value : value of the variable
+// exprs : expressions, AND() of all is used
+// return -
+//    0 - Not Found
+//    2 - Full match
+func matchExprs(name string, value interface{}, exprs []string) int {
+	values := map[string]interface{}{
+		name: value,
 	}
-	//When more than one bw is given AND() is used
-	for _, bitrateexpr := range s.BitRates {
-		expr := bitrateStr + bitrateexpr
-		value, err := gval.Evaluate(expr, rateValue)
+	for _, valueexpr := range exprs {
+		expr := name + valueexpr
+		result, err := gval.Evaluate(expr, values)
 		if err != nil {
 			//the experession was wrong
 			return MatchResultNotFound
 		}
-		switch v := reflect.ValueOf(value); v.Kind() {
+		switch v := reflect.ValueOf(result); v.Kind() {
 		case reflect.Bool:
 			if !v.Bool() {
 				return MatchResultNotFound
@@ -226,6 +469,228 @@ func (s *StreamSelector) matchBitrateRep(representation RepresentationType) int
 	return MatchResultFound
 }
 
+//attrFilter - expressions on one Representation attribute
+type attrFilter struct {
+	name  string   //variable in expressions
+	exprs []string //expressions
+	//adaptValue - upper bound (@max*) or common attribute of the AdaptationSet
+	adaptValue func(adaptSet AdaptationSetType) (interface{}, bool)
+	//repValue - attribute of the Representation, AdaptationSet common attribute if absent
+	repValue func(adaptSet *AdaptationSetType, representation RepresentationType) (interface{}, bool)
+}
+
+//attrFilters - video attribute filters of the selector
+func (s *StreamSelector) attrFilters() []attrFilter {
+	return []attrFilter{
+		{
+			name:  "w",
+			exprs: s.Widths,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return uintValue(a.MaxWidth, a.Width)
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if a == nil {
+					return uintValue(r.Width, 0)
+				}
+				return uintValue(r.Width, a.Width)
+			},
+		},
+		{
+			name:  "h",
+			exprs: s.Heights,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return uintValue(a.MaxHeight, a.Height)
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if a == nil {
+					return uintValue(r.Height, 0)
+				}
+				return uintValue(r.Height, a.Height)
+			},
+		},
+		{
+			name:  "fps",
+			exprs: s.FrameRates,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return frameRateValue(a.MaxFrameRate, a.FrameRate)
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if a == nil {
+					return frameRateValue(r.FrameRate, "")
+				}
+				return frameRateValue(r.FrameRate, a.FrameRate)
+			},
+		},
+		{
+			name:  "sar",
+			exprs: s.Sars,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return ratioValue(a.Sar, "")
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if a == nil {
+					return ratioValue(r.Sar, "")
+				}
+				return ratioValue(r.Sar, a.Sar)
+			},
+		},
+		{
+			name:  "scan",
+			exprs: s.ScanTypes,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return string(a.ScanType), len(a.ScanType) > 0
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if len(r.ScanType) == 0 && a != nil {
+					return string(a.ScanType), len(a.ScanType) > 0
+				}
+				return string(r.ScanType), len(r.ScanType) > 0
+			},
+		},
+		{
+			name:  "mpr",
+			exprs: s.MaxPlayoutRates,
+			adaptValue: func(a AdaptationSetType) (interface{}, bool) {
+				return a.MaxPlayoutRate, a.MaxPlayoutRate > 0
+			},
+			repValue: func(a *AdaptationSetType, r RepresentationType) (interface{}, bool) {
+				if r.MaxPlayoutRate <= 0 && a != nil {
+					return a.MaxPlayoutRate, a.MaxPlayoutRate > 0
+				}
+				return r.MaxPlayoutRate, r.MaxPlayoutRate > 0
+			},
+		},
+	}
+}
+
+//matchAttrs - finds if video attributes present and required matches
+// adaptSet : AdaptationSet
+// return -
+//   -1 - Don't Care
+//    0 - Not Found
+//    1 - Partial match, only some Representations are good
+//    2 - Full match
+func (s *StreamSelector) matchAttrs(adaptSet AdaptationSetType) int {
+	ret := MatchResultDontCare
+	for _, attr := range s.attrFilters() {
+		ret1 := attr.matchAdapt(adaptSet)
+		switch ret1 {
+		case MatchResultNotFound:
+			return ret1
+		case MatchResultDontCare:
+			continue
+		}
+		//Lowest match of the attributes
+		if ret == MatchResultDontCare || ret1 < ret {
+			ret = ret1
+		}
+	}
+	return ret
+}
+
+//matchAdapt - finds if attribute of AdaptationSet and its Representations matches
+// adaptSet : AdaptationSet
+// return -
+//   -1 - Don't Care
+//    0 - Not Found
+//    1 - Partial match
+//    2 - Full match
+func (f attrFilter) matchAdapt(adaptSet AdaptationSetType) int {
+	if len(f.exprs) == 0 {
+		return MatchResultDontCare
+	}
+	ret := MatchResultDontCare
+	if value, ok := f.adaptValue(adaptSet); ok {
+		if matchExprs(f.name, value, f.exprs) == MatchResultFound {
+			//Upper bound good, all Representations good
+			return MatchResultFound
+		}
+		//Upper bound not good, Representations could be
+		ret = MatchResultPartial
+	}
+	found, notFound := false, false
+	for _, representation := range adaptSet.Representation {
+		value, ok := f.repValue(&adaptSet, representation)
+		if !ok {
+			continue
+		}
+		if matchExprs(f.name, value, f.exprs) == MatchResultFound {
+			found = true
+		} else {
+			notFound = true
+		}
+	}
+	switch {
+	case found && !notFound:
+		return MatchResultFound
+	case found:
+		return MatchResultPartial
+	case notFound:
+		return MatchResultNotFound
+	}
+	return ret
+}
+
+//matchRep - finds if attribute of Representation present and required matches
+// representation : Representation
+// return -
+//   -1 - Don't Care
+//    0 - Not Found
+//    2 - Full match
+func (f attrFilter) matchRep(representation RepresentationType) int {
+	if len(f.exprs) == 0 {
+		return MatchResultDontCare
+	}
+	value, ok := f.repValue(nil, representation)
+	if !ok {
+		return MatchResultDontCare
+	}
+	return matchExprs(f.name, value, f.exprs)
+}
+
+//uintValue - value, inherited value if absent
+func uintValue(value uint, inherited uint) (interface{}, bool) {
+	if value == 0 {
+		value = inherited
+	}
+	return value, value > 0
+}
+
+//frameRateValue - @frameRate evaluated, inherited value if absent
+func frameRateValue(frameRate FrameRateType, inherited FrameRateType) (interface{}, bool) {
+	if len(frameRate) == 0 {
+		frameRate = inherited
+	}
+	if len(frameRate) == 0 {
+		return nil, false
+	}
+	value, err := GetFrameRate(string(frameRate))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+//ratioValue - @sar/@par "x:y" as x/y, inherited value if absent
+func ratioValue(ratio RatioType, inherited RatioType) (interface{}, bool) {
+	if len(ratio) == 0 {
+		ratio = inherited
+	}
+	parts := strings.Split(string(ratio), ":")
+	if len(parts) != 2 {
+		return nil, false
+	}
+	x, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, false
+	}
+	y, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || y == 0 {
+		return nil, false
+	}
+	return x / y, true
+}
+
 //matchCodecRep - finds if codec present and required matches
 // adaptSet : AdaptationSet
 // codecExpected : Decoder codec supported
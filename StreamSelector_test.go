@@ -0,0 +1,258 @@
+package dashreader_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//videoAdaptSet - 360p/720p/1080p ladder
+func videoAdaptSet() dashreader.AdaptationSetType {
+	return dashreader.AdaptationSetType{
+		ContentType:  "video",
+		MaxWidth:     1920,
+		MaxHeight:    1080,
+		MaxFrameRate: "60",
+		Sar:          "1:1",
+		Representation: []dashreader.RepresentationType{
+			{Id: "V360", Bandwidth: 300000, Width: 640, Height: 360, FrameRate: "30000/1001"},
+			{Id: "V720", Bandwidth: 1000000, Width: 1280, Height: 720, FrameRate: "30000/1001", ScanType: "progressive"},
+			{Id: "V1080", Bandwidth: 3000000, Width: 1920, Height: 1080, FrameRate: "60", ScanType: "interlaced", MaxPlayoutRate: 2},
+		},
+	}
+}
+
+func TestStreamSelectorVideoAttrs(t *testing.T) {
+	adaptSet := videoAdaptSet()
+	testCases := []struct {
+		name     string
+		selector dashreader.StreamSelector
+		adapt    int
+		reps     []int
+	}{
+		{"none", dashreader.StreamSelector{},
+			dashreader.MatchResultDontCare, []int{dashreader.MatchResultDontCare, dashreader.MatchResultDontCare, dashreader.MatchResultDontCare}},
+		{"max height good", dashreader.StreamSelector{Heights: []string{"<=1080"}},
+			dashreader.MatchResultFound, []int{dashreader.MatchResultFound, dashreader.MatchResultFound, dashreader.MatchResultFound}},
+		{"720p cap", dashreader.StreamSelector{Widths: []string{"<=1280"}, Heights: []string{"<=720"}},
+			dashreader.MatchResultPartial, []int{dashreader.MatchResultFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}},
+		{"range", dashreader.StreamSelector{Heights: []string{">=480", "<=720"}},
+			dashreader.MatchResultPartial, []int{dashreader.MatchResultNotFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}},
+		{"too small", dashreader.StreamSelector{Heights: []string{"<=240"}},
+			dashreader.MatchResultNotFound, []int{dashreader.MatchResultNotFound, dashreader.MatchResultNotFound, dashreader.MatchResultNotFound}},
+		{"30fps", dashreader.StreamSelector{FrameRates: []string{"<=30"}},
+			dashreader.MatchResultPartial, []int{dashreader.MatchResultFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}},
+		{"sar", dashreader.StreamSelector{Sars: []string{"==1"}},
+			dashreader.MatchResultFound, []int{dashreader.MatchResultDontCare, dashreader.MatchResultDontCare, dashreader.MatchResultDontCare}},
+		{"sar not square", dashreader.StreamSelector{Sars: []string{">1"}},
+			dashreader.MatchResultNotFound, []int{dashreader.MatchResultDontCare, dashreader.MatchResultDontCare, dashreader.MatchResultDontCare}},
+		{"progressive", dashreader.StreamSelector{ScanTypes: []string{`=="progressive"`}},
+			dashreader.MatchResultPartial, []int{dashreader.MatchResultDontCare, dashreader.MatchResultFound, dashreader.MatchResultNotFound}},
+		{"trick play", dashreader.StreamSelector{MaxPlayoutRates: []string{">=2"}},
+			dashreader.MatchResultFound, []int{dashreader.MatchResultDontCare, dashreader.MatchResultDontCare, dashreader.MatchResultFound}},
+		{"bad expression", dashreader.StreamSelector{Widths: []string{"<=="}},
+			dashreader.MatchResultNotFound, []int{dashreader.MatchResultNotFound, dashreader.MatchResultNotFound, dashreader.MatchResultNotFound}},
+	}
+	for _, tc := range testCases {
+		tc.selector.ContentType = "video"
+		if act := tc.selector.IsMatch(adaptSet); act != tc.adapt {
+			t.Errorf("%v: AdaptationSet Exp: %v Act: %v", tc.name, tc.adapt, act)
+		}
+		for i, rep := range adaptSet.Representation {
+			if act := tc.selector.IsMatchRepresentation(rep); act != tc.reps[i] {
+				t.Errorf("%v: Representation %v Exp: %v Act: %v", tc.name, rep.Id, tc.reps[i], act)
+			}
+		}
+	}
+
+	//Without @max* the Representations decide
+	adaptSet.MaxHeight = 0
+	selector := dashreader.StreamSelector{ContentType: "video", Heights: []string{"<=1080"}}
+	if act := selector.IsMatch(adaptSet); act != dashreader.MatchResultFound {
+		t.Errorf("Representations good Exp: %v Act: %v", dashreader.MatchResultFound, act)
+	}
+	//Common attribute inherited by the Representations
+	adaptSet.MaxFrameRate = ""
+	adaptSet.FrameRate = "25"
+	for i := range adaptSet.Representation {
+		adaptSet.Representation[i].FrameRate = ""
+	}
+	selector = dashreader.StreamSelector{ContentType: "video", FrameRates: []string{"<=24"}}
+	if act := selector.IsMatch(adaptSet); act != dashreader.MatchResultNotFound {
+		t.Errorf("Inherited frameRate Exp: %v Act: %v", dashreader.MatchResultNotFound, act)
+	}
+}
+
+func TestStreamSelectorVideoAttrsReader(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	//Better match preferred over an AdaptationSet only partially good
+	hd := mpd.Period[0].AdaptationSet[0]
+	hd.Id = 1
+	hd.MaxHeight = 720
+	hd.Representation = append([]dashreader.RepresentationType{}, hd.Representation...)
+	for i := range hd.Representation {
+		hd.Representation[i].Id = "HD" + hd.Representation[i].Id
+	}
+	sd := mpd.Period[0].AdaptationSet[0]
+	sd.Id = 2
+	sd.MaxHeight = 360
+	sd.Representation = sd.Representation[:1]
+	mpd.Period[0].AdaptationSet = []dashreader.AdaptationSetType{hd, sd}
+
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	testCases := []struct {
+		name     string
+		selector dashreader.StreamSelector
+		exp      string
+	}{
+		{"no cap", dashreader.StreamSelector{ID: "1", ContentType: "video"}, "http://127.0.0.1/vod/HDV1000/init.mp4"},
+		{"720p", dashreader.StreamSelector{ID: "1", ContentType: "video", Heights: []string{"<=720"}}, "http://127.0.0.1/vod/HDV1000/init.mp4"},
+		{"480p", dashreader.StreamSelector{ID: "1", ContentType: "video", Heights: []string{"<=480"}}, "http://127.0.0.1/vod/V300/init.mp4"},
+		{"30fps", dashreader.StreamSelector{ID: "1", ContentType: "video", Heights: []string{"<=720"}, FrameRates: []string{"<=30"}}, "http://127.0.0.1/vod/HDV1000/init.mp4"},
+	}
+	for _, tc := range testCases {
+		readCtx, err := rdr.MakeDASHReaderContext(nil, tc.selector, dashreader.MaxBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", tc.name, err)
+		}
+		nextURLs(t, tc.name, readCtx, []string{tc.exp})
+	}
+	//Nothing good
+	selector := dashreader.StreamSelector{ID: "1", ContentType: "video", Heights: []string{"<=240"}}
+	if _, err := rdr.MakeDASHReaderContext(nil, selector, dashreader.MaxBWRepresentationSelector{}); err == nil {
+		t.Errorf("Expected error when no AdaptationSet matches")
+	}
+}
+
+//audioAdaptSets - English main, audio description and commentary
+func audioAdaptSets() []dashreader.AdaptationSetType {
+	rep := func(id string) []dashreader.RepresentationType {
+		return []dashreader.RepresentationType{{Id: dashreader.StringNoWhitespaceType(id), Bandwidth: 128000}}
+	}
+	role := func(value string) []dashreader.DescriptorType {
+		return []dashreader.DescriptorType{{SchemeIdUri: dashreader.RoleSchemeIDURI, Value: value}}
+	}
+	return []dashreader.AdaptationSetType{
+		{Id: 1, ContentType: "audio", Lang: "eng", Representation: rep("main")},
+		{Id: 2, ContentType: "audio", Lang: "eng", Role: role(dashreader.RoleAlternate),
+			Accessibility:  []dashreader.DescriptorType{{SchemeIdUri: dashreader.AudioPurposeSchemeIDURI, Value: dashreader.AudioPurposeVisualImpaired}},
+			Label:          []dashreader.LabelType{{Value: "English AD"}},
+			Representation: rep("ad")},
+		{Id: 3, ContentType: "audio", Lang: "eng", Role: role(dashreader.RoleCommentary),
+			Label:          []dashreader.LabelType{{Value: "Director commentary"}},
+			Representation: rep("commentary")},
+	}
+}
+
+func TestStreamSelectorPreferences(t *testing.T) {
+	var selector dashreader.StreamSelector
+	config := `{"contentType":"audio","roles":["main","alternate"],
+		"accessibilities":[{"schemeIdUri":"urn:tva:metadata:cs:AudioPurposeCS:2007","valueregex":"^1$"},{}],
+		"labelsregexs":["AD$","."]}`
+	if err := json.Unmarshal([]byte(config), &selector); err != nil {
+		t.Fatalf("Error decoding : %v", err)
+	}
+	if len(selector.Roles) != 2 || len(selector.Accessibilities) != 2 || len(selector.Labels) != 2 ||
+		selector.Accessibilities[0].SchemeIDURI != dashreader.AudioPurposeSchemeIDURI || selector.Accessibilities[0].Value != "^1$" {
+		t.Fatalf("Decoded selector unexpected %+v", selector)
+	}
+	adaptSets := audioAdaptSets()
+	main, ad, commentary := adaptSets[0], adaptSets[1], adaptSets[2]
+	testCases := []struct {
+		name     string
+		selector dashreader.StreamSelector
+		matches  []int
+		prefer   int //ComparePreference(main, ad)
+	}{
+		{"none", dashreader.StreamSelector{},
+			[]int{dashreader.MatchResultDontCare, dashreader.MatchResultDontCare, dashreader.MatchResultDontCare}, 0},
+		{"main else alternate", dashreader.StreamSelector{Roles: []string{dashreader.RoleMain, dashreader.RoleAlternate}},
+			[]int{dashreader.MatchResultFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}, -1},
+		{"alternate else main", dashreader.StreamSelector{Roles: []string{dashreader.RoleAlternate, dashreader.RoleMain}},
+			[]int{dashreader.MatchResultFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}, 1},
+		{"audio description", dashreader.StreamSelector{Accessibilities: []dashreader.DescriptorMatcher{
+			{SchemeIDURI: dashreader.AudioPurposeSchemeIDURI, Value: "^" + dashreader.AudioPurposeVisualImpaired + "$"}}},
+			[]int{dashreader.MatchResultNotFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}, 1},
+		{"without accessibility", dashreader.StreamSelector{Accessibilities: []dashreader.DescriptorMatcher{{}}},
+			[]int{dashreader.MatchResultFound, dashreader.MatchResultNotFound, dashreader.MatchResultFound}, -1},
+		{"labels", dashreader.StreamSelector{Labels: []string{"commentary", "AD$"}},
+			[]int{dashreader.MatchResultNotFound, dashreader.MatchResultFound, dashreader.MatchResultFound}, 1},
+		{"role before label", dashreader.StreamSelector{Roles: []string{dashreader.RoleMain, dashreader.RoleAlternate}, Labels: []string{"AD$", "."}},
+			[]int{dashreader.MatchResultNotFound, dashreader.MatchResultFound, dashreader.MatchResultNotFound}, -1},
+	}
+	for _, tc := range testCases {
+		tc.selector.ContentType = "audio"
+		for i, adaptSet := range adaptSets {
+			if act := tc.selector.IsMatch(adaptSet); act != tc.matches[i] {
+				t.Errorf("%v: AdaptationSet %v Exp: %v Act: %v", tc.name, adaptSet.Id, tc.matches[i], act)
+			}
+		}
+		if act := tc.selector.ComparePreference(main, ad); act != tc.prefer {
+			t.Errorf("%v: Preference Exp: %v Act: %v", tc.name, tc.prefer, act)
+		}
+	}
+	//Not matching is last
+	selector = dashreader.StreamSelector{ContentType: "audio", Roles: []string{dashreader.RoleCommentary}}
+	if act := selector.ComparePreference(commentary, main); act != -1 {
+		t.Errorf("Commentary preferred Exp: -1 Act: %v", act)
+	}
+
+	//CEA-608 captions in the video
+	video := dashreader.AdaptationSetType{ContentType: "video",
+		Accessibility: []dashreader.DescriptorType{{SchemeIdUri: dashreader.CEA608SchemeIDURI, Value: "CC1=eng;CC3=spa"}}}
+	selector = dashreader.StreamSelector{ContentType: "video", Accessibilities: []dashreader.DescriptorMatcher{{SchemeIDURI: dashreader.CEA608SchemeIDURI, Value: "=spa"}}}
+	if act := selector.IsMatch(video); act != dashreader.MatchResultFound {
+		t.Errorf("CEA-608 Exp: %v Act: %v", dashreader.MatchResultFound, act)
+	}
+}
+
+func TestStreamSelectorPreferencesReader(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_abr.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	//Audio description first in the MPD
+	template := mpd.Period[0].AdaptationSet[0].SegmentTemplate
+	adaptSets := audioAdaptSets()
+	adaptSets[0], adaptSets[1] = adaptSets[1], adaptSets[0]
+	for i := range adaptSets {
+		adaptSets[i].SegmentTemplate = template
+	}
+	mpd.Period[0].AdaptationSet = adaptSets
+	factory := dashreader.ReaderFactory{}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	testCases := []struct {
+		name     string
+		selector dashreader.StreamSelector
+		exp      string
+	}{
+		{"first", dashreader.StreamSelector{Langs: []string{"eng"}}, "http://127.0.0.1/vod/ad/init.mp4"},
+		{"main", dashreader.StreamSelector{Langs: []string{"eng"}, Roles: []string{dashreader.RoleMain, dashreader.RoleAlternate}}, "http://127.0.0.1/vod/main/init.mp4"},
+		{"dub else alternate", dashreader.StreamSelector{Roles: []string{dashreader.RoleDub, dashreader.RoleAlternate}}, "http://127.0.0.1/vod/ad/init.mp4"},
+		{"commentary", dashreader.StreamSelector{Labels: []string{"(?i)commentary"}}, "http://127.0.0.1/vod/commentary/init.mp4"},
+	}
+	for _, tc := range testCases {
+		tc.selector.ID = "1"
+		tc.selector.ContentType = "audio"
+		readCtx, err := rdr.MakeDASHReaderContext(nil, tc.selector, dashreader.MaxBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", tc.name, err)
+		}
+		nextURLs(t, tc.name, readCtx, []string{tc.exp})
+	}
+	selector := dashreader.StreamSelector{ID: "1", ContentType: "audio", Roles: []string{dashreader.RoleDub}}
+	if _, err := rdr.MakeDASHReaderContext(nil, selector, dashreader.MaxBWRepresentationSelector{}); err == nil {
+		t.Errorf("Expected error when no Role matches")
+	}
+}
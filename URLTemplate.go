@@ -0,0 +1,187 @@
+package dashreader
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	templateRepresentationID = "RepresentationID"
+	templateNumber           = "Number"
+	templateBandwidth        = "Bandwidth"
+	templateTime             = "Time"
+	templateSubNumber        = "SubNumber"
+)
+
+//TemplateValues - Values substituted for SegmentTemplate identifiers
+type TemplateValues struct {
+	RepresentationID string //$RepresentationID$
+	Number           uint64 //$Number$
+	Bandwidth        uint64 //$Bandwidth$
+	Time             uint64 //$Time$
+	SubNumber        uint64 //$SubNumber$
+}
+
+//urlTemplatePart - literal text or identifier of URLTemplate
+type urlTemplatePart struct {
+	literal    string //text when identifier is empty
+	identifier string //identifier without $
+	width      int    //minimum width from %0[width]d, ZERO if absent
+}
+
+//URLTemplate - Parsed SegmentTemplate@media/initialization/index
+type URLTemplate struct {
+	template string
+	parts    []urlTemplatePart
+}
+
+//ParseURLTemplate - Parse template as per ISO/IEC 23009-1 5.3.9.4.4
+// Identifiers $RepresentationID$ $Number$ $Bandwidth$ $Time$ $SubNumber$
+// Format tag %0[width]d allowed except for $RepresentationID$
+// $$ is an escaped $
+// Parameters:
+//   1: template string
+// Return:
+//   1: URLTemplate
+//   2: error for malformed identifiers
+func ParseURLTemplate(template string) (*URLTemplate, error) {
+	ret := &URLTemplate{template: template}
+	rest := template
+	literal := ""
+	for len(rest) > 0 {
+		start := strings.IndexByte(rest, '$')
+		if start < 0 {
+			literal += rest
+			break
+		}
+		literal += rest[:start]
+		rest = rest[start+1:]
+		end := strings.IndexByte(rest, '$')
+		if end < 0 {
+			return nil, fmt.Errorf("Template(%v) has unterminated $", template)
+		}
+		identifier := rest[:end]
+		rest = rest[end+1:]
+		if len(identifier) <= 0 {
+			//$$ escape
+			literal += "$"
+			continue
+		}
+		part, err := parseTemplateIdentifier(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("Template(%v) %w", template, err)
+		}
+		if len(literal) > 0 {
+			ret.parts = append(ret.parts, urlTemplatePart{literal: literal})
+			literal = ""
+		}
+		ret.parts = append(ret.parts, part)
+	}
+	if len(literal) > 0 {
+		ret.parts = append(ret.parts, urlTemplatePart{literal: literal})
+	}
+	return ret, nil
+}
+
+//parseTemplateIdentifier - Parse identifier with optional format tag
+func parseTemplateIdentifier(identifier string) (urlTemplatePart, error) {
+	name := identifier
+	format := ""
+	if i := strings.IndexByte(identifier, '%'); i >= 0 {
+		name = identifier[:i]
+		format = identifier[i:]
+	}
+	switch name {
+	case templateRepresentationID:
+		if len(format) > 0 {
+			return urlTemplatePart{}, fmt.Errorf("identifier $%v$ MUST NOT have format tag", identifier)
+		}
+		return urlTemplatePart{identifier: name}, nil
+	case templateNumber, templateBandwidth, templateTime, templateSubNumber:
+	default:
+		return urlTemplatePart{}, fmt.Errorf("identifier $%v$ not known", identifier)
+	}
+	if len(format) <= 0 {
+		return urlTemplatePart{identifier: name}, nil
+	}
+	//%0[width]d
+	if len(format) < 4 || !strings.HasPrefix(format, "%0") || !strings.HasSuffix(format, "d") {
+		return urlTemplatePart{}, fmt.Errorf("identifier $%v$ format tag MUST be %%0[width]d", identifier)
+	}
+	width, err := strconv.ParseUint(format[2:len(format)-1], 10, 8)
+	if err != nil || width <= 0 {
+		return urlTemplatePart{}, fmt.Errorf("identifier $%v$ format tag width not correct", identifier)
+	}
+	return urlTemplatePart{identifier: name, width: int(width)}, nil
+}
+
+//String - template as given
+func (t *URLTemplate) String() string {
+	return t.template
+}
+
+//Has - Checks if identifier (without $) is used in template
+func (t *URLTemplate) Has(identifier string) bool {
+	for _, part := range t.parts {
+		if part.identifier == identifier {
+			return true
+		}
+	}
+	return false
+}
+
+//Expand - Substitute identifiers with values
+func (t *URLTemplate) Expand(values TemplateValues) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		var value uint64
+		switch part.identifier {
+		case "":
+			b.WriteString(part.literal)
+			continue
+		case templateRepresentationID:
+			b.WriteString(values.RepresentationID)
+			continue
+		case templateNumber:
+			value = values.Number
+		case templateBandwidth:
+			value = values.Bandwidth
+		case templateTime:
+			value = values.Time
+		case templateSubNumber:
+			value = values.SubNumber
+		}
+		s := strconv.FormatUint(value, 10)
+		for i := len(s); i < part.width; i++ {
+			b.WriteByte('0')
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+//ExpandURLTemplate - Parse and expand template
+// Parameters:
+//   1: template string
+//   2: values for identifiers
+// Return:
+//   1: expanded string
+//   2: error for malformed identifiers
+func ExpandURLTemplate(template string, values TemplateValues) (string, error) {
+	t, err := ParseURLTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return t.Expand(values), nil
+}
+
+//resolve - Expand template and resolve against base url
+func (t *URLTemplate) resolve(baseURL url.URL, values TemplateValues) (url.URL, error) {
+	v, err := AdjustURLPath(baseURL, []BaseURLType{}, t.Expand(values))
+	if err != nil {
+		return url.URL{}, err
+	}
+	return *v, nil
+}
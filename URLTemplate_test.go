@@ -0,0 +1,90 @@
+package dashreader_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anbangisak/dashreader"
+)
+
+func TestExpandURLTemplate(t *testing.T) {
+	values := dashreader.TemplateValues{
+		RepresentationID: "V300",
+		Number:           42,
+		Bandwidth:        300000,
+		Time:             123456,
+		SubNumber:        3,
+	}
+	tests := []struct {
+		template string
+		exp      string
+	}{
+		{"$RepresentationID$/$Number$.m4s", "V300/42.m4s"},
+		{"$RepresentationID$/$Number%05d$.m4s", "V300/00042.m4s"},
+		{"t$Time%010d$.m4s", "t0000123456.m4s"},
+		{"$Number%01d$", "42"},
+		{"b$Bandwidth$/n$Number$_$SubNumber$.m4s", "b300000/n42_3.m4s"},
+		{"cost$$/$Time$$$.m4s", "cost$/123456$.m4s"},
+		{"init.mp4", "init.mp4"},
+	}
+	for _, test := range tests {
+		act, err := dashreader.ExpandURLTemplate(test.template, values)
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", test.template, err)
+			continue
+		}
+		if act != test.exp {
+			t.Errorf("%v: Exp: %v Act: %v", test.template, test.exp, act)
+		}
+	}
+}
+
+func TestParseURLTemplateErrors(t *testing.T) {
+	templates := []string{
+		"$Number",
+		"$Numbr$.m4s",
+		"$RepresentationID%05d$",
+		"$Number%5d$",
+		"$Number%0d$",
+		"$Number%05x$",
+		"$Number%0ad$",
+	}
+	for _, template := range templates {
+		if _, err := dashreader.ParseURLTemplate(template); err == nil {
+			t.Errorf("%v: Expected error", template)
+		}
+	}
+}
+
+func TestFactoryTemplateValidation(t *testing.T) {
+	file := "test/static_template.mpd"
+	tests := []struct {
+		name   string
+		mutate func(segTemplate *dashreader.SegmentTemplateType)
+	}{
+		{"media format tag", func(segTemplate *dashreader.SegmentTemplateType) {
+			segTemplate.Media = strings.ReplaceAll(segTemplate.Media, "$Number$", "$Number%5d$")
+		}},
+		{"media unknown identifier", func(segTemplate *dashreader.SegmentTemplateType) {
+			segTemplate.Media = strings.ReplaceAll(segTemplate.Media, "$Time$", "$Tme$")
+		}},
+		{"init with $Number$", func(segTemplate *dashreader.SegmentTemplateType) {
+			segTemplate.InitializationAttr = strings.ReplaceAll(segTemplate.InitializationAttr, "init", "$Number$")
+		}},
+	}
+	for _, test := range tests {
+		mpd, err := dashreader.ReadMPDFromFile(file)
+		if err != nil {
+			t.Fatalf("Error reading %s:%v", file, err)
+		}
+		for i := range mpd.Period {
+			for j := range mpd.Period[i].AdaptationSet {
+				test.mutate(&mpd.Period[i].AdaptationSet[j].SegmentTemplate)
+			}
+		}
+		factory := dashreader.ReaderFactory{}
+		if _, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd); err == nil {
+			t.Errorf("%v: Expected factory error", test.name)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package dashreader
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eswarantg/statzagg"
+)
+
+//UTCTiming@schemeIdUri supported by ClockSync
+const (
+	UTCTimingHTTPXSDate   = "urn:mpeg:dash:utc:http-xsdate:2014" //GET, body xs:dateTime
+	UTCTimingHTTPISO      = "urn:mpeg:dash:utc:http-iso:2014"    //GET, body ISO 8601
+	UTCTimingHTTPNTP      = "urn:mpeg:dash:utc:http-ntp:2014"    //GET, body 64 bit NTP timestamp
+	UTCTimingHTTPHead     = "urn:mpeg:dash:utc:http-head:2014"   //HEAD, Date header
+	UTCTimingDirect       = "urn:mpeg:dash:utc:direct:2014"      //@value is xs:dateTime
+	UTCTimingHTTPXSDate12 = "urn:mpeg:dash:utc:http-xsdate:2012" //2012 variant of http-xsdate
+	UTCTimingHTTPISO12    = "urn:mpeg:dash:utc:http-iso:2012"    //2012 variant of http-iso
+	UTCTimingHTTPNTP12    = "urn:mpeg:dash:utc:http-ntp:2012"    //2012 variant of http-ntp
+	UTCTimingHTTPHead12   = "urn:mpeg:dash:utc:http-head:2012"   //2012 variant of http-head
+	UTCTimingDirect12     = "urn:mpeg:dash:utc:direct:2012"      //2012 variant of direct
+)
+
+const (
+	ntpEpochOffset     = 2208988800      //seconds 1900-01-01 to 1970-01-01
+	defaultSyncTimeout = 5 * time.Second //ClockSync.Timeout if not set
+)
+
+//ClockSync - Offset of the server clock (MPD UTCTiming) from the local clock
+// Live readers use it to locate the live point and to convert
+// ChunkURL.FetchAt to the local clock
+type ClockSync struct {
+	//ID - ID for the events
+	ID string
+	//Client - http.Client to use, http.DefaultClient if nil
+	Client *http.Client
+	//StatzAgg - Statz Agg for events
+	StatzAgg statzagg.StatzAgg
+	//Clock - Local clock, ReaderFactory.Clock if nil when the Reader is made
+	Clock Clock
+	//Timeout - Limit for one Sync, 5s if ZERO
+	Timeout time.Duration
+
+	mutex  sync.RWMutex  //gaurd fields below
+	offset time.Duration //server clock - local clock
+	synced bool          //offset measured atleast once
+}
+
+//Offset - server clock - local clock
+// Return:
+//   1: offset, ZERO if not synced
+//   2: synced atleast once
+func (s *ClockSync) Offset() (time.Duration, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.offset, s.synced
+}
+
+//Now - Current time as per server clock
+func (s *ClockSync) Now() time.Time {
+	if s == nil {
+		return time.Now()
+	}
+	offset, _ := s.Offset()
+	return timeNow(s.Clock).Add(offset)
+}
+
+//ToLocal - Server clock time converted to local clock
+func (s *ClockSync) ToLocal(serverTime time.Time) time.Time {
+	offset, _ := s.Offset()
+	return serverTime.Add(-1 * offset)
+}
+
+//SetOffset - Set the offset measured outside
+// Parameters:
+//   server clock - local clock
+func (s *ClockSync) SetOffset(offset time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.offset = offset
+	s.synced = true
+}
+
+//Sync - Measure the offset with UTCTiming of MPD
+// UTCTiming elements are tried in MPD order till one succeeds,
+// offset is unchanged if all fail or Timeout expires
+// Parameters:
+//   1: context for cancellation
+//   2: MPD.UTCTiming
+//   3: url of the MPD, relative @value is resolved with it
+// Return:
+//   1: error if no UTCTiming succeeded
+func (s *ClockSync) Sync(ctx context.Context, timings []DescriptorType, mpdURL url.URL) error {
+	if len(timings) <= 0 {
+		return fmt.Errorf("MPD.UTCTiming not present")
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultSyncTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, timing := range timings {
+		offset, rtt, err := s.measure(ctx, timing, mpdURL)
+		if err != nil {
+			s.postEvent(EvtClockSyncFailed, timing.SchemeIdUri, timing.Value, err.Error())
+			continue
+		}
+		s.SetOffset(offset)
+		s.postEvent(EvtClockSync, timing.SchemeIdUri, offset, rtt)
+		return nil
+	}
+	return fmt.Errorf("MPD.UTCTiming(%v) none succeeded", len(timings))
+}
+
+//measure - Offset with one UTCTiming
+// Return:
+//   1: offset
+//   2: round trip time of request, ZERO for direct
+//   3: error
+func (s *ClockSync) measure(ctx context.Context, timing DescriptorType, mpdURL url.URL) (time.Duration, time.Duration, error) {
+	method := http.MethodGet
+	var parse func(*http.Response, []byte) (time.Time, error)
+	switch timing.SchemeIdUri {
+	case UTCTimingDirect, UTCTimingDirect12:
+		serverTime, err := parseUTCTimingDate([]byte(timing.Value))
+		if err != nil {
+			return 0, 0, err
+		}
+		return serverTime.Sub(timeNow(s.Clock)), 0, nil
+	case UTCTimingHTTPXSDate, UTCTimingHTTPXSDate12, UTCTimingHTTPISO, UTCTimingHTTPISO12:
+		parse = func(resp *http.Response, body []byte) (time.Time, error) {
+			return parseUTCTimingDate(body)
+		}
+	case UTCTimingHTTPNTP, UTCTimingHTTPNTP12:
+		parse = func(resp *http.Response, body []byte) (time.Time, error) {
+			return parseNTPTimestamp(body)
+		}
+	case UTCTimingHTTPHead, UTCTimingHTTPHead12:
+		method = http.MethodHead
+		parse = func(resp *http.Response, body []byte) (time.Time, error) {
+			return http.ParseTime(resp.Header.Get("Date"))
+		}
+	default:
+		return 0, 0, fmt.Errorf("scheme not supported")
+	}
+	var lastErr error
+	//@value is whitespace separated list of urls
+	for _, value := range strings.Fields(timing.Value) {
+		offset, rtt, err := s.request(ctx, method, value, mpdURL, parse)
+		if err == nil {
+			return offset, rtt, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("@value has no url")
+	}
+	return 0, 0, lastErr
+}
+
+//request - Offset from one http request
+// Server time is taken at the middle of the round trip
+func (s *ClockSync) request(ctx context.Context, method string, value string, mpdURL url.URL, parse func(*http.Response, []byte) (time.Time, error)) (time.Duration, time.Duration, error) {
+	ref, err := url.Parse(value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("url(%v) not correct: %w", value, err)
+	}
+	reqURL := mpdURL.ResolveReference(ref)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("request for %v failed: %w", reqURL.String(), err)
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	start := timeNow(s.Clock)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("fetch %v failed: %w", reqURL.String(), err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	end := timeNow(s.Clock)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading %v failed: %w", reqURL.String(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("fetch %v failed: %v", reqURL.String(), resp.Status)
+	}
+	serverTime, err := parse(resp, body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("response of %v not correct: %w", reqURL.String(), err)
+	}
+	rtt := end.Sub(start)
+	return serverTime.Sub(start.Add(rtt / 2)), rtt, nil
+}
+
+//utcTimingChanged - UTCTiming of the MPDs differ, offset to be measured again
+func utcTimingChanged(old []DescriptorType, timings []DescriptorType) bool {
+	if len(old) != len(timings) {
+		return true
+	}
+	for i := range timings {
+		if old[i].SchemeIdUri != timings[i].SchemeIdUri || old[i].Value != timings[i].Value {
+			return true
+		}
+	}
+	return false
+}
+
+//parseUTCTimingDate - xs:dateTime or ISO 8601 time
+func parseUTCTimingDate(data []byte) (time.Time, error) {
+	var ret xsdDateTime
+	if err := ret.UnmarshalText(data); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time(ret), nil
+}
+
+//parseNTPTimestamp - 64 bit NTP timestamp, seconds and fraction from 1900
+func parseNTPTimestamp(data []byte) (time.Time, error) {
+	if len(data) != 8 {
+		return time.Time{}, fmt.Errorf("NTP timestamp MUST be 8 bytes, got %v", len(data))
+	}
+	seconds := binary.BigEndian.Uint32(data[0:4])
+	fraction := binary.BigEndian.Uint32(data[4:8])
+	nsec := (uint64(fraction) * uint64(time.Second)) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, int64(nsec)).UTC(), nil
+}
+
+//postEvent - Post clock event
+func (s *ClockSync) postEvent(name string, values ...interface{}) {
+	if s.StatzAgg == nil {
+		return
+	}
+	s.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+		EventClock: timeNow(s.Clock),
+		ID:         s.ID,
+		Name:       name,
+		Values:     values,
+	})
+}
@@ -0,0 +1,225 @@
+package dashreader_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//timeServer - Serves server time one hour ahead of local clock
+func timeServer() *httptest.Server {
+	ahead := func() time.Time { return time.Now().Add(time.Hour).UTC() }
+	mux := http.NewServeMux()
+	mux.HandleFunc("/xsdate", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(ahead().Format(time.RFC3339Nano)))
+	})
+	mux.HandleFunc("/head", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Date", ahead().Format(http.TimeFormat))
+	})
+	mux.HandleFunc("/ntp", func(w http.ResponseWriter, req *http.Request) {
+		now := ahead()
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint32(data[0:4], uint32(now.Unix()+2208988800))
+		binary.BigEndian.PutUint32(data[4:8], uint32((uint64(now.Nanosecond())<<32)/uint64(time.Second)))
+		w.Write(data)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestClockSync(t *testing.T) {
+	ts := timeServer()
+	defer ts.Close()
+	mpdURL, _ := url.Parse(ts.URL + "/live/manifest.mpd")
+	testCases := []struct {
+		name    string
+		timings []dashreader.DescriptorType
+		//Date header has only seconds
+		tolerance time.Duration
+		failures  int
+	}{
+		{"xsdate", []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPXSDate, Value: "/xsdate"}}, 100 * time.Millisecond, 0},
+		{"iso2012", []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPISO12, Value: ts.URL + "/xsdate"}}, 100 * time.Millisecond, 0},
+		{"head", []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPHead, Value: "/head"}}, 1100 * time.Millisecond, 0},
+		{"ntp", []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPNTP, Value: "/ntp"}}, 100 * time.Millisecond, 0},
+		{"direct", []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingDirect, Value: time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)}}, 100 * time.Millisecond, 0},
+		{"fallback", []dashreader.DescriptorType{
+			{SchemeIdUri: "urn:example:unknown", Value: "/xsdate"},
+			{SchemeIdUri: dashreader.UTCTimingHTTPXSDate, Value: "/fail"},
+			{SchemeIdUri: dashreader.UTCTimingHTTPXSDate, Value: "/fail /xsdate"},
+		}, 100 * time.Millisecond, 2},
+	}
+	for _, tc := range testCases {
+		events := &eventCapture{}
+		clock := &dashreader.ClockSync{ID: tc.name, Client: ts.Client(), StatzAgg: events}
+		if err := clock.Sync(context.TODO(), tc.timings, *mpdURL); err != nil {
+			t.Errorf("%v: Sync failed : %v", tc.name, err)
+			continue
+		}
+		offset, synced := clock.Offset()
+		if !synced {
+			t.Errorf("%v: not synced", tc.name)
+		}
+		if diff := offset - time.Hour; diff > tc.tolerance || diff < -1*tc.tolerance {
+			t.Errorf("%v: offset %v, expected %v", tc.name, offset, time.Hour)
+		}
+		if n := len(events.find(dashreader.EvtClockSyncFailed)); n != tc.failures {
+			t.Errorf("%v: %v failure events, expected %v", tc.name, n, tc.failures)
+		}
+		if n := len(events.find(dashreader.EvtClockSync)); n != 1 {
+			t.Errorf("%v: %v sync events, expected 1", tc.name, n)
+		}
+	}
+	//All fail, offset kept
+	clock := &dashreader.ClockSync{Client: ts.Client()}
+	clock.SetOffset(time.Minute)
+	err := clock.Sync(context.TODO(), []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPHead, Value: "/fail"}}, *mpdURL)
+	if err == nil {
+		t.Errorf("Sync expected to fail")
+	}
+	if offset, _ := clock.Offset(); offset != time.Minute {
+		t.Errorf("Offset changed to %v on failure", offset)
+	}
+	//Unresponsive server, Sync bounded by Timeout
+	clock = &dashreader.ClockSync{Client: ts.Client(), Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	err = clock.Sync(context.TODO(), []dashreader.DescriptorType{{SchemeIdUri: dashreader.UTCTimingHTTPXSDate, Value: "/slow"}}, *mpdURL)
+	if err == nil {
+		t.Errorf("Sync expected to fail on timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Sync took %v, Timeout 50ms", elapsed)
+	}
+	if _, synced := clock.Offset(); synced {
+		t.Errorf("Synced on timeout")
+	}
+}
+
+func TestClockSyncOnUpdate(t *testing.T) {
+	direct := func(ahead time.Duration) []dashreader.DescriptorType {
+		return []dashreader.DescriptorType{{
+			SchemeIdUri: dashreader.UTCTimingDirect,
+			Value:       time.Now().Add(ahead).UTC().Format(time.RFC3339Nano),
+		}}
+	}
+	readMPD := func(publishTime time.Time, timings []dashreader.DescriptorType) *dashreader.MPDtype {
+		mpd, err := dashreader.ReadMPDFromFile("test/live_seglist.mpd")
+		if err != nil {
+			t.Fatalf("Error reading : %v", err)
+		}
+		mpd.PublishTime = publishTime
+		mpd.UTCTiming = timings
+		return mpd
+	}
+	checkOffset := func(name string, clock *dashreader.ClockSync, exp time.Duration) {
+		offset, _ := clock.Offset()
+		if diff := offset - exp; diff > 100*time.Millisecond || diff < -100*time.Millisecond {
+			t.Errorf("%v: offset %v, expected %v", name, offset, exp)
+		}
+	}
+	publishTime := time.Now().Truncate(time.Second)
+	timings := direct(time.Minute)
+	clock := &dashreader.ClockSync{}
+	factory := dashreader.ReaderFactory{ClockSync: clock}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", readMPD(publishTime, timings))
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	checkOffset("initial", clock, time.Minute)
+	//Same UTCTiming, not synced again
+	clock.SetOffset(0)
+	publishTime = publishTime.Add(time.Second)
+	if _, err := rdr.Update(readMPD(publishTime, timings)); err != nil {
+		t.Fatalf("Update failed : %v", err)
+	}
+	checkOffset("same UTCTiming", clock, 0)
+	//Changed UTCTiming, synced again
+	publishTime = publishTime.Add(time.Second)
+	if _, err := rdr.Update(readMPD(publishTime, direct(time.Hour))); err != nil {
+		t.Fatalf("Update failed : %v", err)
+	}
+	checkOffset("changed UTCTiming", clock, time.Hour)
+	//Older MPD ignored, not synced
+	if _, err := rdr.Update(readMPD(publishTime.Add(-1*time.Minute), direct(2*time.Hour))); err == nil {
+		t.Errorf("Older MPD expected to fail")
+	}
+	checkOffset("older MPD", clock, time.Hour)
+}
+
+func TestReaderClockSync(t *testing.T) {
+	//Segments of 2s available at AST+2s, +4s ... +10s
+	ast := time.Now().Add(-5 * time.Second).Truncate(time.Millisecond)
+	makeReader := func(clock *dashreader.ClockSync) dashreader.ReaderContext {
+		mpd, err := dashreader.ReadMPDFromFile("test/live_seglist.mpd")
+		if err != nil {
+			t.Fatalf("Error reading : %v", err)
+		}
+		mpd.AvailabilityStartTime = ast
+		//Server clock 4s ahead
+		mpd.UTCTiming = []dashreader.DescriptorType{{
+			SchemeIdUri: dashreader.UTCTimingDirect,
+			Value:       time.Now().Add(4 * time.Second).UTC().Format(time.RFC3339Nano),
+		}}
+		factory := dashreader.ReaderFactory{ClockSync: clock}
+		rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/live/manifest.mpd", mpd)
+		if err != nil {
+			t.Fatalf("Error getting reader : %v", err)
+		}
+		streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "video"}
+		readCtx, err := rdr.MakeDASHReaderContext(nil, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("Error getting context : %v", err)
+		}
+		return readCtx
+	}
+	seg := func(s string, fetchAt time.Time) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		u.FetchAt = fetchAt
+		return u
+	}
+	//Local clock: AST+5s, live point is segment 2
+	readCtx := makeReader(nil)
+	readCtx.NextURL()
+	checkURLs(t, "local clock", readCtx, []dashreader.ChunkURL{
+		seg("http://127.0.0.1/live/v/2.m4s", ast.Add(4*time.Second)),
+		seg("http://127.0.0.1/live/v/3.m4s", ast.Add(6*time.Second)),
+		seg("http://127.0.0.1/live/v/4.m4s", ast.Add(8*time.Second)),
+		seg("http://127.0.0.1/live/v/5.m4s", ast.Add(10*time.Second)),
+	})
+	//Server clock: AST+9s, live point is segment 4, FetchAt in local clock
+	clock := &dashreader.ClockSync{}
+	readCtx = makeReader(clock)
+	offset, synced := clock.Offset()
+	if !synced || offset < 3*time.Second {
+		t.Fatalf("Clock not synced by factory : %v %v", offset, synced)
+	}
+	readCtx.NextURL()
+	for i, exp := range []dashreader.ChunkURL{
+		seg("http://127.0.0.1/live/v/4.m4s", ast.Add(8*time.Second).Add(-1*offset)),
+		seg("http://127.0.0.1/live/v/5.m4s", ast.Add(10*time.Second).Add(-1*offset)),
+	} {
+		chunkURL, err := readCtx.NextURL()
+		if err != nil {
+			t.Fatalf("server clock: URL %v error : %v", i, err)
+		}
+		if chunkURL.ChunkURL.String() != exp.ChunkURL.String() || !chunkURL.FetchAt.Equal(exp.FetchAt) {
+			t.Errorf("server clock: URL %v Exp: %v %v Act: %v %v", i, exp.ChunkURL.String(), exp.FetchAt.UTC(),
+				chunkURL.ChunkURL.String(), chunkURL.FetchAt.UTC())
+		}
+	}
+}
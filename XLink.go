@@ -0,0 +1,353 @@
+package dashreader
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/eswarantg/statzagg"
+)
+
+const (
+	//XLinkDefaultMaxDepth - Remote elements nested deeper are not resolved
+	XLinkDefaultMaxDepth = 5
+	//mpdNamespace - Namespace of MPD elements
+	mpdNamespace = "urn:mpeg:dash:schema:mpd:2011"
+)
+
+//XLinkResolver - Resolves remote elements (xlink:href) of MPD
+// Run on the MPD before ReaderFactory.GetDASHReader and Reader.Update
+//  * Period - always resolved by Resolve, Period timing of the MPD needs it
+//  * AdaptationSet, SegmentList, EventStream
+//    - xlink:actuate="onLoad" resolved by Resolve
+//    - xlink:actuate="onRequest" (default) resolved by ResolveOnRequest
+//      Readers call it when a Period is first used
+//  * xlink:href="urn:mpeg:dash:resolve-to-zero:2013" removes the element
+//  * Element failing to resolve is removed and EvtXLinkResolveFailed posted
+type XLinkResolver struct {
+	//ID - ID for the events
+	ID string
+	//Fetcher - Fetcher for remote elements, HTTPIndexFetcher if nil
+	Fetcher IndexFetcher
+	//StatzAgg - Statz Agg for events
+	StatzAgg statzagg.StatzAgg
+	//MaxDepth - Nesting of remote elements allowed, XLinkDefaultMaxDepth if ZERO
+	MaxDepth int
+	//Clock - Clock for event timestamps, ReaderFactory.Clock if nil when the Reader is made
+	Clock Clock
+
+	mutex sync.Mutex //serialize resolution, MPD is modified in place
+}
+
+//Resolve - Resolve remote Periods and onLoad remote elements
+// Parameters:
+//   1: MPD to resolve, modified in place
+//   2: url the MPD was fetched from, relative xlink:href is resolved with it
+// Return:
+//   1: first resolution failure, failed elements are already removed
+func (x *XLinkResolver) Resolve(mpd *MPDtype, mpdURL url.URL) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	var errs xlinkErrors
+	mpd.Period = x.resolvePeriods(mpd.Period, mpdURL, 0, &errs)
+	for i := range mpd.Period {
+		x.resolvePeriodChildren(&mpd.Period[i], mpdURL, XLinkActuateOnLoad, 0, &errs)
+	}
+	return errs.first
+}
+
+//ResolveOnRequest - Resolve onRequest remote elements of a Period
+// Parameters:
+//   1: Period to resolve, modified in place
+//   2: url the MPD was fetched from, relative xlink:href is resolved with it
+// Return:
+//   1: first resolution failure, failed elements are already removed
+func (x *XLinkResolver) ResolveOnRequest(period *PeriodType, mpdURL url.URL) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	var errs xlinkErrors
+	x.resolvePeriodChildren(period, mpdURL, XLinkActuateOnRequest, 0, &errs)
+	return errs.first
+}
+
+//xlinkErrors - Failures during one resolution
+type xlinkErrors struct {
+	first error
+}
+
+//add - Record failure
+func (e *xlinkErrors) add(err error) {
+	if e.first == nil {
+		e.first = err
+	}
+}
+
+//isActuate - element with xlink:actuate to be resolved now
+func isActuate(href string, actuate ActuateType, want ActuateType) bool {
+	if len(href) <= 0 {
+		return false
+	}
+	if len(actuate) <= 0 {
+		actuate = XLinkActuateOnRequest
+	}
+	return actuate == want
+}
+
+//resolvePeriodChildren - Resolve remote elements inside Period
+func (x *XLinkResolver) resolvePeriodChildren(period *PeriodType, docURL url.URL, actuate ActuateType, depth int, errs *xlinkErrors) {
+	x.resolveSegmentList(&period.SegmentList, nil, docURL, actuate, depth, errs)
+	period.EventStream = x.resolveEventStreams(period.EventStream, docURL, actuate, depth, errs)
+	inherited := segmentAttrsBelow(nil, period.SegmentBase, period.SegmentList, period.SegmentTemplate)
+	period.AdaptationSet = x.resolveAdaptationSets(period.AdaptationSet, inherited, docURL, actuate, depth, errs)
+	for i := range period.AdaptationSet {
+		adapt := &period.AdaptationSet[i]
+		x.resolveSegmentList(&adapt.SegmentList, inherited, docURL, actuate, depth, errs)
+		adaptInherited := segmentAttrsBelow(inherited, adapt.SegmentBase, adapt.SegmentList, adapt.SegmentTemplate)
+		for j := range adapt.Representation {
+			x.resolveSegmentList(&adapt.Representation[j].SegmentList, adaptInherited, docURL, actuate, depth, errs)
+		}
+	}
+}
+
+//resolvePeriods - Periods with remote Periods replaced
+func (x *XLinkResolver) resolvePeriods(periods []PeriodType, docURL url.URL, depth int, errs *xlinkErrors) []PeriodType {
+	ret := make([]PeriodType, 0, len(periods))
+	for i := range periods {
+		href := periods[i].Href
+		if len(href) <= 0 {
+			ret = append(ret, periods[i])
+			continue
+		}
+		var remote []PeriodType
+		remoteURL, err := x.fetch(href, docURL, depth, "Period", nil, func(d *xml.Decoder, start xml.StartElement) error {
+			var v PeriodType
+			if err := d.DecodeElement(&v, &start); err != nil {
+				return err
+			}
+			remote = append(remote, v)
+			return nil
+		})
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		for j := range remote {
+			absPeriodHrefs(&remote[j], remoteURL)
+		}
+		ret = append(ret, x.resolvePeriods(remote, remoteURL, depth+1, errs)...)
+	}
+	return ret
+}
+
+//resolveAdaptationSets - AdaptationSets with remote AdaptationSets replaced
+// inheritedSegmentAttrs of the Period given for decoding remote AdaptationSets
+func (x *XLinkResolver) resolveAdaptationSets(sets []AdaptationSetType, inherited map[segmentAttr]string, docURL url.URL, actuate ActuateType, depth int, errs *xlinkErrors) []AdaptationSetType {
+	ret := make([]AdaptationSetType, 0, len(sets))
+	for i := range sets {
+		if !isActuate(sets[i].Href, sets[i].Actuate, actuate) {
+			ret = append(ret, sets[i])
+			continue
+		}
+		var remote []AdaptationSetType
+		remoteURL, err := x.fetch(sets[i].Href, docURL, depth, "AdaptationSet", inherited, func(d *xml.Decoder, start xml.StartElement) error {
+			var v AdaptationSetType
+			if err := d.DecodeElement(&v, &start); err != nil {
+				return err
+			}
+			remote = append(remote, v)
+			return nil
+		})
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		for j := range remote {
+			absAdaptationSetHrefs(&remote[j], remoteURL)
+		}
+		ret = append(ret, x.resolveAdaptationSets(remote, inherited, remoteURL, XLinkActuateOnLoad, depth+1, errs)...)
+	}
+	return ret
+}
+
+//resolveEventStreams - EventStreams with remote EventStreams replaced
+func (x *XLinkResolver) resolveEventStreams(streams []EventStreamType, docURL url.URL, actuate ActuateType, depth int, errs *xlinkErrors) []EventStreamType {
+	ret := make([]EventStreamType, 0, len(streams))
+	for i := range streams {
+		if !isActuate(streams[i].Href, streams[i].Actuate, actuate) {
+			ret = append(ret, streams[i])
+			continue
+		}
+		var remote []EventStreamType
+		remoteURL, err := x.fetch(streams[i].Href, docURL, depth, "EventStream", nil, func(d *xml.Decoder, start xml.StartElement) error {
+			var v EventStreamType
+			if err := d.DecodeElement(&v, &start); err != nil {
+				return err
+			}
+			remote = append(remote, v)
+			return nil
+		})
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		remote = x.resolveEventStreams(remote, remoteURL, XLinkActuateOnLoad, depth+1, errs)
+		ret = append(ret, remote...)
+	}
+	return ret
+}
+
+//resolveSegmentList - SegmentList replaced by remote SegmentList
+// Removed (ZERO value) on resolve-to-zero or failure,
+// inheritedSegmentAttrs of the upper levels given for decoding remote SegmentList
+func (x *XLinkResolver) resolveSegmentList(segList *SegmentListType, inherited map[segmentAttr]string, docURL url.URL, actuate ActuateType, depth int, errs *xlinkErrors) {
+	if !isActuate(segList.Href, segList.Actuate, actuate) {
+		return
+	}
+	var remote []SegmentListType
+	remoteURL, err := x.fetch(segList.Href, docURL, depth, "SegmentList", inherited, func(d *xml.Decoder, start xml.StartElement) error {
+		var v SegmentListType
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		remote = append(remote, v)
+		return nil
+	})
+	if err == nil && len(remote) > 1 {
+		err = fmt.Errorf("XLink(%v) SegmentList remote entity has %v elements, ONE expected", segList.Href, len(remote))
+		x.postEvent(EvtXLinkResolveFailed, segList.Href, err.Error())
+	}
+	if err != nil {
+		errs.add(err)
+	}
+	if err != nil || len(remote) <= 0 {
+		*segList = SegmentListType{}
+		return
+	}
+	*segList = remote[0]
+	x.resolveSegmentList(segList, inherited, remoteURL, XLinkActuateOnLoad, depth+1, errs)
+}
+
+//absHref - xlink:href made absolute with url of the remote entity
+// Elements taken out of remote entity keep resolving to the right place
+func absHref(href string, docURL url.URL) string {
+	if len(href) <= 0 || href == XLinkResolveToZero {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		//Reported when resolved
+		return href
+	}
+	return docURL.ResolveReference(ref).String()
+}
+
+//absPeriodHrefs - xlink:href inside remote Period made absolute
+func absPeriodHrefs(period *PeriodType, docURL url.URL) {
+	period.Href = absHref(period.Href, docURL)
+	period.SegmentList.Href = absHref(period.SegmentList.Href, docURL)
+	for i := range period.EventStream {
+		period.EventStream[i].Href = absHref(period.EventStream[i].Href, docURL)
+	}
+	for i := range period.AdaptationSet {
+		absAdaptationSetHrefs(&period.AdaptationSet[i], docURL)
+	}
+}
+
+//absAdaptationSetHrefs - xlink:href inside remote AdaptationSet made absolute
+func absAdaptationSetHrefs(adapt *AdaptationSetType, docURL url.URL) {
+	adapt.Href = absHref(adapt.Href, docURL)
+	adapt.SegmentList.Href = absHref(adapt.SegmentList.Href, docURL)
+	for i := range adapt.Representation {
+		adapt.Representation[i].SegmentList.Href = absHref(adapt.Representation[i].SegmentList.Href, docURL)
+	}
+}
+
+//fetch - Fetch remote entity and decode its elements
+// Parameters:
+//   1: xlink:href
+//   2: url of the document having the element
+//   3: nesting depth
+//   4: element name expected in remote entity
+//   5: inheritedSegmentAttrs where the elements are placed
+//   6: decode function for each element
+// Return:
+//   1: url of remote entity, for nested xlink:href
+//   2: error, EvtXLinkResolveFailed is posted
+func (x *XLinkResolver) fetch(href string, docURL url.URL, depth int, name string, inherited map[segmentAttr]string, decode func(*xml.Decoder, xml.StartElement) error) (url.URL, error) {
+	if href == XLinkResolveToZero {
+		x.postEvent(EvtXLinkResolved, href, 0)
+		return docURL, nil
+	}
+	ret, count, err := x.fetchElements(href, docURL, depth, name, inherited, decode)
+	if err != nil {
+		err = fmt.Errorf("XLink(%v) %v: %w", href, name, err)
+		x.postEvent(EvtXLinkResolveFailed, href, err.Error())
+		return ret, err
+	}
+	x.postEvent(EvtXLinkResolved, href, count)
+	return ret, nil
+}
+
+//fetchElements - Fetch remote entity and decode its top level elements
+func (x *XLinkResolver) fetchElements(href string, docURL url.URL, depth int, name string, inherited map[segmentAttr]string, decode func(*xml.Decoder, xml.StartElement) error) (url.URL, int, error) {
+	maxDepth := x.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = XLinkDefaultMaxDepth
+	}
+	if depth >= maxDepth {
+		return docURL, 0, fmt.Errorf("nested deeper than %v", maxDepth)
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return docURL, 0, fmt.Errorf("href not correct: %w", err)
+	}
+	remoteURL := *docURL.ResolveReference(ref)
+	fetcher := x.Fetcher
+	if fetcher == nil {
+		fetcher = HTTPIndexFetcher{}
+	}
+	data, err := fetcher.FetchRange(remoteURL, "")
+	if err != nil {
+		return remoteURL, 0, err
+	}
+	d := newMPDDecoder(bytes.NewReader(data), mpdNamespace, inherited)
+	count := 0
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return remoteURL, count, fmt.Errorf("decode failed: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != name {
+			return remoteURL, count, fmt.Errorf("remote entity has %v, expected %v", start.Name.Local, name)
+		}
+		if err := decode(d, start); err != nil {
+			return remoteURL, count, fmt.Errorf("decode failed: %w", err)
+		}
+		count++
+	}
+	return remoteURL, count, nil
+}
+
+//postEvent - Post xlink event
+func (x *XLinkResolver) postEvent(name string, values ...interface{}) {
+	if x.StatzAgg == nil {
+		return
+	}
+	x.StatzAgg.PostEventStats(context.TODO(), &statzagg.EventStats{
+		EventClock: timeNow(x.Clock),
+		ID:         x.ID,
+		Name:       name,
+		Values:     values,
+	})
+}
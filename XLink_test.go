@@ -0,0 +1,136 @@
+package dashreader_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anbangisak/dashreader"
+)
+
+//fileFetcher - Serves http://127.0.0.1/vod/... from test/...
+type fileFetcher struct {
+	fetched []string
+}
+
+func (f *fileFetcher) FetchRange(u url.URL, byteRange string) ([]byte, error) {
+	f.fetched = append(f.fetched, u.String())
+	if !strings.HasPrefix(u.Path, "/vod/") {
+		return nil, fmt.Errorf("%v not found", u.String())
+	}
+	return ioutil.ReadFile("test/" + strings.TrimPrefix(u.Path, "/vod/"))
+}
+
+func TestXLinkResolve(t *testing.T) {
+	file := "test/static_xlink.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	events := &eventCapture{}
+	fetcher := &fileFetcher{}
+	factory := dashreader.ReaderFactory{
+		XLinkResolver: &dashreader.XLinkResolver{ID: "client1", Fetcher: fetcher, StatzAgg: events},
+	}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	//Remote Period replaced, resolve-to-zero and failed Periods removed
+	if len(mpd.Period) != 2 || mpd.Period[0].Id != "p0" || mpd.Period[1].Id != "p1" {
+		t.Fatalf("Periods after resolve : %v", len(mpd.Period))
+	}
+	if len(events.find(dashreader.EvtXLinkResolveFailed)) != 1 || len(events.find(dashreader.EvtXLinkResolved)) != 3 {
+		t.Errorf("Events Exp: 1 failed 3 resolved Act: %v failed %v resolved",
+			len(events.find(dashreader.EvtXLinkResolveFailed)), len(events.find(dashreader.EvtXLinkResolved)))
+	}
+	//onRequest AdaptationSet of p0 not fetched yet
+	expFetched := []string{
+		"http://127.0.0.1/vod/xlink/period.xml",
+		"http://127.0.0.1/vod/missing.xml",
+		"http://127.0.0.1/vod/xlink_audio.xml",
+	}
+	if strings.Join(fetcher.fetched, ",") != strings.Join(expFetched, ",") {
+		t.Errorf("Fetched Exp: %v Act: %v", expFetched, fetcher.fetched)
+	}
+	if len(mpd.Period[0].AdaptationSet[1].Href) <= 0 {
+		t.Errorf("onRequest AdaptationSet resolved on load")
+	}
+	seg := func(s string) dashreader.ChunkURL {
+		u := mustURL(t, s)
+		u.Duration = 2 * time.Second
+		return u
+	}
+	//@startNumber of remote AdaptationSet SegmentTemplate inherited from the Period
+	exp := []dashreader.ChunkURL{
+		mustURL(t, "http://127.0.0.1/vod/A64/init.mp4"),
+		seg("http://127.0.0.1/vod/A64/3.m4s"),
+		seg("http://127.0.0.1/vod/A64/4.m4s"),
+	}
+	streamSelector := dashreader.StreamSelector{ID: "1", ContentType: "audio"}
+	var readCtx dashreader.ReaderContext
+	for _, periodID := range []string{"p0", "p1"} {
+		readCtx, err = rdr.MakeDASHReaderContext(readCtx, streamSelector, dashreader.MinBWRepresentationSelector{})
+		if err != nil {
+			t.Fatalf("%v: Error getting context : %v", periodID, err)
+		}
+		if readCtx.GetLang() != "en" {
+			t.Errorf("%v: Lang Exp: en Act: %v", periodID, readCtx.GetLang())
+		}
+		checkURLs(t, periodID, readCtx, exp)
+	}
+	if len(fetcher.fetched) != len(expFetched)+1 {
+		t.Errorf("onRequest AdaptationSet not resolved once when Period used : %v", fetcher.fetched)
+	}
+	//Resolved in a copy, MPD shared by the contexts not modified
+	if len(mpd.Period[0].AdaptationSet[1].Href) <= 0 {
+		t.Errorf("onRequest AdaptationSet resolved in the shared MPD")
+	}
+	//MPD not newer rejected before resolution
+	same, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	periods := len(same.Period)
+	fetched := len(fetcher.fetched)
+	if updated, err := rdr.Update(same); updated || err != nil {
+		t.Errorf("Update of same MPD Exp: false <nil> Act: %v %v", updated, err)
+	}
+	if len(same.Period) != periods || len(fetcher.fetched) != fetched {
+		t.Errorf("Rejected MPD resolved Periods %v->%v Fetched %v", periods, len(same.Period), fetcher.fetched[fetched:])
+	}
+}
+
+func TestXLinkOnRequestConcurrent(t *testing.T) {
+	file := "test/static_xlink.mpd"
+	mpd, err := dashreader.ReadMPDFromFile(file)
+	if err != nil {
+		t.Fatalf("Error reading %s:%v", file, err)
+	}
+	factory := dashreader.ReaderFactory{
+		XLinkResolver: &dashreader.XLinkResolver{ID: "client1", Fetcher: &fileFetcher{}},
+	}
+	rdr, err := factory.GetDASHReader("client1", "http://127.0.0.1/vod/manifest.mpd", mpd)
+	if err != nil {
+		t.Fatalf("Error getting reader : %v", err)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, contentType := range []string{"video", "audio"} {
+		wg.Add(1)
+		go func(i int, contentType string) {
+			defer wg.Done()
+			_, errs[i] = rdr.MakeDASHReaderContext(nil, dashreader.StreamSelector{ID: "1", ContentType: contentType}, dashreader.MinBWRepresentationSelector{})
+		}(i, contentType)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Context %v error : %v", i, err)
+		}
+	}
+}
@@ -4,11 +4,29 @@ const (
 	//LiveProfile - String for Live Profile, Field: MPD@Profiles
 	LiveProfile = "urn:mpeg:dash:profile:isoff-live:2011"
 	//OnDemandProfile - String for OnDemandProfile Profile, Field: MPD@Profiles
+	//
+	// Deprecated: misspelled, MPDs list ISOOnDemandProfile
 	OnDemandProfile = "urn:mpeg:dash:profile:isoff-ondemand:2011"
+	//ISOOnDemandProfile - String for On Demand Profile (ISO/IEC 23009-1), Field: MPD@Profiles
+	ISOOnDemandProfile = "urn:mpeg:dash:profile:isoff-on-demand:2011"
+	//MainProfile - String for Main Profile, Field: MPD@Profiles
+	MainProfile = "urn:mpeg:dash:profile:isoff-main:2011"
+	//FullProfile - String for Full Profile, Field: MPD@Profiles
+	FullProfile = "urn:mpeg:dash:profile:full:2011"
 	//RepresentationIDToken - Token part of SegmentTemplate@Media or SegmentTemplate@Index
 	RepresentationIDToken = "$RepresentationID$"
 	//TimeToken - Token part of SegmentTemplate@Media
 	TimeToken = "$Time$"
 	//NumberToken - Token part of SegmentTemplate@Media
 	NumberToken = "$Number$"
+	//BandwidthToken - Token part of SegmentTemplate@Media or SegmentTemplate@Initialization
+	BandwidthToken = "$Bandwidth$"
+	//SubNumberToken - Token part of SegmentTemplate@Media
+	SubNumberToken = "$SubNumber$"
+	//XLinkActuateOnLoad - xlink:actuate, remote element resolved when MPD is loaded
+	XLinkActuateOnLoad ActuateType = "onLoad"
+	//XLinkActuateOnRequest - xlink:actuate, remote element resolved when needed (default)
+	XLinkActuateOnRequest ActuateType = "onRequest"
+	//XLinkResolveToZero - xlink:href removing the element without fetch
+	XLinkResolveToZero = "urn:mpeg:dash:resolve-to-zero:2013"
 )
@@ -9,5 +9,22 @@ const (
 	EvtMPDNoActivePeriod              = "MPD_NO_ACTIVE_PERIOD"               //Period active not found
 	EvtMPDNoAdaptAfterFilter          = "MPD_NO_ADAPT_AFTER_FILTER"          //No AdaptationSets after filter
 	EvtMPDNoRepresentationAfterFilter = "MPD_NO_REPRESENTATION_AFTER_FILTER" //No Representations after filter
+	EvtMPDPeriodChange                = "MPD_PERIOD_CHANGE"                  //Moved to next Period - From, To, PeriodStart
+	EvtMPDTimeShiftBufferSkip         = "MPD_TSB_SKIP"                       //Segments dropped out of TimeShiftBuffer - From, To
+	EvtBaseURLFailover                = "BASEURL_FAILOVER"                   //Moved to next BaseURL after fetch failure - From, To
+	EvtXLinkResolved                  = "XLINK_RESOLVED"                     //Remote element resolved - href, elements count
+	EvtXLinkResolveFailed             = "XLINK_RESOLVE_FAILED"               //Remote element failed, element removed - href, error
+	EvtMPDRefreshFailed               = "MPD_REFRESH_FAILED"                 //MPD fetch or update failed - error, retry after
+	EvtClockSync                      = "CLOCK_SYNC"                         //Clock offset measured - scheme, offset, round trip
+	EvtClockSyncFailed                = "CLOCK_SYNC_FAILED"                  //UTCTiming failed, next one tried - scheme, value, error
+	EvtMPDPatchApplied                = "MPD_PATCH_APPLIED"                  //MPD Patch applied instead of full fetch - patch url, PublishTime
+	EvtMPDPatchFailed                 = "MPD_PATCH_FAILED"                   //MPD Patch failed, full MPD fetched - patch url, error
+	EvtMPDExpired                     = "MPD_EXPIRED"                        //Inband MPD validity expiration, early refresh - value, PublishTime, presentation time
+	EvtMPDEventStart                  = "MPD_EVENT_START"                    //EventStream event started - schemeIdUri, id, start
+	EvtMPDEventEnd                    = "MPD_EVENT_END"                      //EventStream event ended - schemeIdUri, id, start
+	EvtSCTE35DecodeFailed             = "SCTE35_DECODE_FAILED"               //SCTE-35 cue of event not decoded - schemeIdUri, id, error
+	EvtRepresentationSwitch           = "REPRESENTATION_SWITCH"              //Representation changed keeping position - From, To, bandwidth
+	EvtMPDUpdateAdjustFailed          = "MPD_UPDATE_ADJUST_FAILED"           //Position not kept over MPD update, live point located again - error
+	EvtRepresentationReselectFailed   = "REPRESENTATION_RESELECT_FAILED"     //Representation selected again not switched to, selection unchanged - Representation, error
 
 )
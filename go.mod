@@ -3,9 +3,12 @@ module github.com/anbangisak/dashreader
 go 1.17
 
 require (
-	github.com/PaesslerAG/gval v1.1.2 // indirect
-	github.com/eswarantg/statzagg v0.0.0-20200802190621-f6d851c08ef8 // indirect
-	github.com/rickb777/date v1.17.0 // indirect
+	github.com/PaesslerAG/gval v1.1.2
+	github.com/eswarantg/statzagg v0.0.0-20200802190621-f6d851c08ef8
+	github.com/rickb777/date v1.17.0
+)
+
+require (
 	github.com/rickb777/plural v1.4.1 // indirect
 	github.com/tcnksm/go-httpstat v0.2.0 // indirect
 )
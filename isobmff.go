@@ -0,0 +1,263 @@
+package dashreader
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	isoBoxHeaderSize     = 8  //size(4) + type(4)
+	isoBoxLargeSizeExtra = 8  //largesize(8) when size == 1
+	isoFullBoxExtra      = 4  //version(1) + flags(3)
+	sidxReferenceSize    = 12 //reference_type/size(4) + duration(4) + SAP(4)
+)
+
+//isoBox - ISOBMFF box located in a byte buffer
+type isoBox struct {
+	boxType    string //four character code
+	size       uint64 //size of the box including header
+	headerSize uint64 //size of the header
+	payload    []byte //bytes after the header
+}
+
+//readISOBox - Reads the box present at the start of data
+// Parameters:
+//   bytes starting at a box boundary
+// Return:
+//   1: Box read
+//   2: error
+func readISOBox(data []byte) (*isoBox, error) {
+	if len(data) < isoBoxHeaderSize {
+		return nil, fmt.Errorf("ISOBMFF box header needs %v bytes, %v available", isoBoxHeaderSize, len(data))
+	}
+	box := &isoBox{
+		size:       uint64(binary.BigEndian.Uint32(data[0:4])),
+		boxType:    string(data[4:8]),
+		headerSize: isoBoxHeaderSize,
+	}
+	switch box.size {
+	case 0:
+		//box extends to the end of data
+		box.size = uint64(len(data))
+	case 1:
+		if len(data) < isoBoxHeaderSize+isoBoxLargeSizeExtra {
+			return nil, fmt.Errorf("ISOBMFF box(%v) largesize truncated", box.boxType)
+		}
+		box.size = binary.BigEndian.Uint64(data[8:16])
+		box.headerSize += isoBoxLargeSizeExtra
+	}
+	if box.size < box.headerSize || box.size > uint64(len(data)) {
+		return nil, fmt.Errorf("ISOBMFF box(%v) size %v invalid for %v bytes", box.boxType, box.size, len(data))
+	}
+	box.payload = data[box.headerSize:box.size]
+	return box, nil
+}
+
+//findISOBox - Finds the first top level box of given type
+// Parameters:
+//   1: bytes starting at a box boundary
+//   2: four character code to look for
+// Return:
+//   1: Box found
+//   2: Offset of the box within data
+//   3: error
+func findISOBox(data []byte, boxType string) (*isoBox, uint64, error) {
+	var offset uint64
+	for offset < uint64(len(data)) {
+		box, err := readISOBox(data[offset:])
+		if err != nil {
+			return nil, offset, err
+		}
+		if box.boxType == boxType {
+			return box, offset, nil
+		}
+		offset += box.size
+	}
+	return nil, offset, fmt.Errorf("ISOBMFF box(%v) not found", boxType)
+}
+
+//sidxReference - One reference entry of a sidx box
+type sidxReference struct {
+	referenceType      uint8  //0 - media, 1 - sidx
+	referencedSize     uint32 //bytes in the referenced material
+	subsegmentDuration uint32 //in sidx timescale
+	startsWithSAP      bool
+	sapType            uint8
+	sapDeltaTime       uint32
+}
+
+//sidxBox - Segment Index Box (ISO/IEC 14496-12 8.16.3)
+type sidxBox struct {
+	referenceID              uint32
+	timescale                uint32
+	earliestPresentationTime uint64
+	firstOffset              uint64
+	references               []sidxReference
+}
+
+//parseSidx - Parses the sidx box present in data
+// Parameters:
+//   bytes read from the index range
+// Return:
+//   1: sidx box
+//   2: offset within data where sidx ends (first_offset is relative to it)
+//   3: error
+func parseSidx(data []byte) (*sidxBox, uint64, error) {
+	box, offset, err := findISOBox(data, "sidx")
+	if err != nil {
+		return nil, 0, err
+	}
+	p := box.payload
+	if len(p) < isoFullBoxExtra+8 {
+		return nil, 0, fmt.Errorf("sidx truncated (%v bytes)", len(p))
+	}
+	version := p[0]
+	ret := &sidxBox{}
+	ret.referenceID = binary.BigEndian.Uint32(p[4:8])
+	ret.timescale = binary.BigEndian.Uint32(p[8:12])
+	pos := 12
+	if version == 0 {
+		if len(p) < pos+8 {
+			return nil, 0, fmt.Errorf("sidx v0 truncated (%v bytes)", len(p))
+		}
+		ret.earliestPresentationTime = uint64(binary.BigEndian.Uint32(p[pos : pos+4]))
+		ret.firstOffset = uint64(binary.BigEndian.Uint32(p[pos+4 : pos+8]))
+		pos += 8
+	} else {
+		if len(p) < pos+16 {
+			return nil, 0, fmt.Errorf("sidx v1 truncated (%v bytes)", len(p))
+		}
+		ret.earliestPresentationTime = binary.BigEndian.Uint64(p[pos : pos+8])
+		ret.firstOffset = binary.BigEndian.Uint64(p[pos+8 : pos+16])
+		pos += 16
+	}
+	if len(p) < pos+4 {
+		return nil, 0, fmt.Errorf("sidx reference_count truncated")
+	}
+	//reserved(2)
+	count := int(binary.BigEndian.Uint16(p[pos+2 : pos+4]))
+	pos += 4
+	if len(p) < pos+count*sidxReferenceSize {
+		return nil, 0, fmt.Errorf("sidx has %v references, only %v bytes present", count, len(p)-pos)
+	}
+	if ret.timescale == 0 {
+		return nil, 0, fmt.Errorf("sidx timescale MUST be non ZERO")
+	}
+	ret.references = make([]sidxReference, count)
+	for i := range ret.references {
+		v := binary.BigEndian.Uint32(p[pos : pos+4])
+		sap := binary.BigEndian.Uint32(p[pos+8 : pos+12])
+		ret.references[i] = sidxReference{
+			referenceType:      uint8(v >> 31),
+			referencedSize:     v & 0x7FFFFFFF,
+			subsegmentDuration: binary.BigEndian.Uint32(p[pos+4 : pos+8]),
+			startsWithSAP:      (sap >> 31) == 1,
+			sapType:            uint8((sap >> 28) & 0x7),
+			sapDeltaTime:       sap & 0x0FFFFFFF,
+		}
+		pos += sidxReferenceSize
+	}
+	return ret, offset + box.size, nil
+}
+
+//emsgBox - Event Message Box (ISO/IEC 23009-1 5.10.3.3)
+type emsgBox struct {
+	version               uint8
+	schemeIdUri           string
+	value                 string
+	timescale             uint32
+	presentationTimeDelta uint32 //v0, from earliest presentation time of segment
+	presentationTime      uint64 //v1, media presentation time
+	eventDuration         uint32 //0xFFFFFFFF if unknown
+	id                    uint32
+	messageData           []byte
+}
+
+//parseEmsg - Parses emsg box v0 or v1
+func parseEmsg(box *isoBox) (*emsgBox, error) {
+	p := box.payload
+	if len(p) < isoFullBoxExtra {
+		return nil, fmt.Errorf("emsg truncated (%v bytes)", len(p))
+	}
+	ret := &emsgBox{version: p[0]}
+	pos := isoFullBoxExtra
+	var err error
+	switch ret.version {
+	case 0:
+		if ret.schemeIdUri, pos, err = readISOString(p, pos); err != nil {
+			return nil, fmt.Errorf("emsg scheme_id_uri : %w", err)
+		}
+		if ret.value, pos, err = readISOString(p, pos); err != nil {
+			return nil, fmt.Errorf("emsg value : %w", err)
+		}
+		if len(p) < pos+16 {
+			return nil, fmt.Errorf("emsg v0 truncated (%v bytes)", len(p))
+		}
+		ret.timescale = binary.BigEndian.Uint32(p[pos : pos+4])
+		ret.presentationTimeDelta = binary.BigEndian.Uint32(p[pos+4 : pos+8])
+		ret.eventDuration = binary.BigEndian.Uint32(p[pos+8 : pos+12])
+		ret.id = binary.BigEndian.Uint32(p[pos+12 : pos+16])
+		pos += 16
+	case 1:
+		if len(p) < pos+20 {
+			return nil, fmt.Errorf("emsg v1 truncated (%v bytes)", len(p))
+		}
+		ret.timescale = binary.BigEndian.Uint32(p[pos : pos+4])
+		ret.presentationTime = binary.BigEndian.Uint64(p[pos+4 : pos+12])
+		ret.eventDuration = binary.BigEndian.Uint32(p[pos+12 : pos+16])
+		ret.id = binary.BigEndian.Uint32(p[pos+16 : pos+20])
+		pos += 20
+		if ret.schemeIdUri, pos, err = readISOString(p, pos); err != nil {
+			return nil, fmt.Errorf("emsg scheme_id_uri : %w", err)
+		}
+		if ret.value, pos, err = readISOString(p, pos); err != nil {
+			return nil, fmt.Errorf("emsg value : %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("emsg version %v not supported", ret.version)
+	}
+	if ret.timescale == 0 {
+		return nil, fmt.Errorf("emsg timescale MUST be non ZERO")
+	}
+	ret.messageData = p[pos:]
+	return ret, nil
+}
+
+//readISOString - Null terminated string
+// Return:
+//   1: string without terminator
+//   2: position after terminator
+//   3: error if not terminated
+func readISOString(p []byte, pos int) (string, int, error) {
+	for i := pos; i < len(p); i++ {
+		if p[i] == 0 {
+			return string(p[pos:i]), i + 1, nil
+		}
+	}
+	return "", pos, fmt.Errorf("string not terminated")
+}
+
+//parseTfdt - baseMediaDecodeTime of the first moof/traf/tfdt
+// Parameters:
+//   moof box
+// Return:
+//   1: baseMediaDecodeTime
+//   2: error if not found
+func parseTfdt(moof *isoBox) (uint64, error) {
+	traf, _, err := findISOBox(moof.payload, "traf")
+	if err != nil {
+		return 0, err
+	}
+	tfdt, _, err := findISOBox(traf.payload, "tfdt")
+	if err != nil {
+		return 0, err
+	}
+	p := tfdt.payload
+	switch {
+	case len(p) >= isoFullBoxExtra+8 && p[0] == 1:
+		return binary.BigEndian.Uint64(p[4:12]), nil
+	case len(p) >= isoFullBoxExtra+4 && p[0] == 0:
+		return uint64(binary.BigEndian.Uint32(p[4:8])), nil
+	}
+	return 0, fmt.Errorf("tfdt truncated or version not supported (%v bytes)", len(p))
+}
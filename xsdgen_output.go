@@ -14,56 +14,59 @@ import (
 type ActuateType string
 
 type AdaptationSetType struct {
-	Items                     []string               `xml:",any"`
-	FramePacking              []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
-	AudioChannelConfiguration []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
-	ContentProtection         []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
-	EssentialProperty         []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
-	SupplementalProperty      []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
-	InbandEventStream         []EventStreamType      `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
-	Accessibility             []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 Accessibility,omitempty"`
-	Role                      []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 Role,omitempty"`
-	Rating                    []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 Rating,omitempty"`
-	Viewpoint                 []DescriptorType       `xml:"urn:mpeg:dash:schema:mpd:2011 Viewpoint,omitempty"`
-	ContentComponent          []ContentComponentType `xml:"urn:mpeg:dash:schema:mpd:2011 ContentComponent,omitempty"`
-	BaseURL                   []BaseURLType          `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
-	SegmentBase               SegmentBaseType        `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
-	SegmentList               SegmentListType        `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
-	SegmentTemplate           SegmentTemplateType    `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
-	Representation            []RepresentationType   `xml:"urn:mpeg:dash:schema:mpd:2011 Representation,omitempty"`
-	Href                      string                 `xml:"href,attr,omitempty"`
-	Actuate                   ActuateType            `xml:"actuate,attr,omitempty"`
-	Id                        uint                   `xml:"id,attr,omitempty"`
-	Group                     uint                   `xml:"group,attr,omitempty"`
-	Lang                      string                 `xml:"lang,attr,omitempty"`
-	ContentType               string                 `xml:"contentType,attr,omitempty"`
-	Par                       RatioType              `xml:"par,attr,omitempty"`
-	MinBandwidth              uint                   `xml:"minBandwidth,attr,omitempty"`
-	MaxBandwidth              uint                   `xml:"maxBandwidth,attr,omitempty"`
-	MinWidth                  uint                   `xml:"minWidth,attr,omitempty"`
-	MaxWidth                  uint                   `xml:"maxWidth,attr,omitempty"`
-	MinHeight                 uint                   `xml:"minHeight,attr,omitempty"`
-	MaxHeight                 uint                   `xml:"maxHeight,attr,omitempty"`
-	MinFrameRate              FrameRateType          `xml:"minFrameRate,attr,omitempty"`
-	MaxFrameRate              FrameRateType          `xml:"maxFrameRate,attr,omitempty"`
-	SegmentAlignment          ConditionalUintType    `xml:"segmentAlignment,attr,omitempty"`
-	SubsegmentAlignment       ConditionalUintType    `xml:"subsegmentAlignment,attr,omitempty"`
-	SubsegmentStartsWithSAP   uint                   `xml:"subsegmentStartsWithSAP,attr,omitempty"`
-	BitstreamSwitching        bool                   `xml:"bitstreamSwitching,attr,omitempty"`
-	Profiles                  string                 `xml:"profiles,attr,omitempty"`
-	Width                     uint                   `xml:"width,attr,omitempty"`
-	Height                    uint                   `xml:"height,attr,omitempty"`
-	Sar                       RatioType              `xml:"sar,attr,omitempty"`
-	FrameRate                 FrameRateType          `xml:"frameRate,attr,omitempty"`
-	AudioSamplingRate         string                 `xml:"audioSamplingRate,attr,omitempty"`
-	MimeType                  string                 `xml:"mimeType,attr,omitempty"`
-	SegmentProfiles           string                 `xml:"segmentProfiles,attr,omitempty"`
-	Codecs                    string                 `xml:"codecs,attr,omitempty"`
-	MaximumSAPPeriod          float64                `xml:"maximumSAPPeriod,attr,omitempty"`
-	StartWithSAP              uint                   `xml:"startWithSAP,attr,omitempty"`
-	MaxPlayoutRate            float64                `xml:"maxPlayoutRate,attr,omitempty"`
-	CodingDependency          bool                   `xml:"codingDependency,attr,omitempty"`
-	ScanType                  VideoScanType          `xml:"scanType,attr,omitempty"`
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	Accessibility             []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Accessibility,omitempty"`
+	Role                      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Role,omitempty"`
+	Rating                    []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Rating,omitempty"`
+	Viewpoint                 []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Viewpoint,omitempty"`
+	ContentComponent          []ContentComponentType      `xml:"urn:mpeg:dash:schema:mpd:2011 ContentComponent,omitempty"`
+	BaseURL                   []BaseURLType               `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
+	SegmentBase               SegmentBaseType             `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
+	SegmentList               SegmentListType             `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
+	SegmentTemplate           SegmentTemplateType         `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
+	Representation            []RepresentationType        `xml:"urn:mpeg:dash:schema:mpd:2011 Representation,omitempty"`
+	Href                      string                      `xml:"href,attr,omitempty"`
+	Actuate                   ActuateType                 `xml:"actuate,attr,omitempty"`
+	Id                        uint                        `xml:"id,attr,omitempty"`
+	Group                     uint                        `xml:"group,attr,omitempty"`
+	Lang                      string                      `xml:"lang,attr,omitempty"`
+	ContentType               string                      `xml:"contentType,attr,omitempty"`
+	Par                       RatioType                   `xml:"par,attr,omitempty"`
+	MinBandwidth              uint                        `xml:"minBandwidth,attr,omitempty"`
+	MaxBandwidth              uint                        `xml:"maxBandwidth,attr,omitempty"`
+	MinWidth                  uint                        `xml:"minWidth,attr,omitempty"`
+	MaxWidth                  uint                        `xml:"maxWidth,attr,omitempty"`
+	MinHeight                 uint                        `xml:"minHeight,attr,omitempty"`
+	MaxHeight                 uint                        `xml:"maxHeight,attr,omitempty"`
+	MinFrameRate              FrameRateType               `xml:"minFrameRate,attr,omitempty"`
+	MaxFrameRate              FrameRateType               `xml:"maxFrameRate,attr,omitempty"`
+	SegmentAlignment          ConditionalUintType         `xml:"segmentAlignment,attr,omitempty"`
+	SubsegmentAlignment       ConditionalUintType         `xml:"subsegmentAlignment,attr,omitempty"`
+	SubsegmentStartsWithSAP   uint                        `xml:"subsegmentStartsWithSAP,attr,omitempty"`
+	BitstreamSwitching        bool                        `xml:"bitstreamSwitching,attr,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
 }
 
 func (t *AdaptationSetType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -89,6 +92,8 @@ type BaseURLType struct {
 	ByteRange                string  `xml:"byteRange,attr,omitempty"`
 	AvailabilityTimeOffset   float64 `xml:"availabilityTimeOffset,attr,omitempty"`
 	AvailabilityTimeComplete bool    `xml:"availabilityTimeComplete,attr,omitempty"`
+	Priority                 uint    `xml:"priority,attr,omitempty"`
+	Weight                   uint    `xml:"weight,attr,omitempty"`
 }
 
 type ConditionalUintType string
@@ -105,6 +110,26 @@ type ContentComponentType struct {
 	Par           RatioType        `xml:"par,attr,omitempty"`
 }
 
+type ContentSteeringType struct {
+	Value                  string `xml:",chardata"`
+	DefaultServiceLocation string `xml:"defaultServiceLocation,attr,omitempty"`
+	QueryBeforeStart       bool   `xml:"queryBeforeStart,attr,omitempty"`
+	ClientRequirement      bool   `xml:"clientRequirement,attr,omitempty"`
+}
+
+func (t *ContentSteeringType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T ContentSteeringType
+	var overlay struct {
+		*T
+		QueryBeforeStart  *bool `xml:"queryBeforeStart,attr,omitempty"`
+		ClientRequirement *bool `xml:"clientRequirement,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.QueryBeforeStart = (*bool)(&overlay.T.QueryBeforeStart)
+	overlay.ClientRequirement = (*bool)(&overlay.T.ClientRequirement)
+	return d.DecodeElement(&overlay, &start)
+}
+
 type DescriptorType struct {
 	Items       []string `xml:",any"`
 	SchemeIdUri string   `xml:"schemeIdUri,attr"`
@@ -113,23 +138,26 @@ type DescriptorType struct {
 }
 
 type EventStreamType struct {
-	Items       []string    `xml:",any"`
-	Event       []EventType `xml:"urn:mpeg:dash:schema:mpd:2011 Event,omitempty"`
-	Href        string      `xml:"href,attr,omitempty"`
-	Actuate     ActuateType `xml:"actuate,attr,omitempty"`
-	SchemeIdUri string      `xml:"schemeIdUri,attr"`
-	Value       string      `xml:"value,attr,omitempty"`
-	Timescale   uint        `xml:"timescale,attr,omitempty"`
+	Items                  []string    `xml:",any"`
+	Event                  []EventType `xml:"urn:mpeg:dash:schema:mpd:2011 Event,omitempty"`
+	Href                   string      `xml:"href,attr,omitempty"`
+	Actuate                ActuateType `xml:"actuate,attr,omitempty"`
+	SchemeIdUri            string      `xml:"schemeIdUri,attr"`
+	Value                  string      `xml:"value,attr,omitempty"`
+	Timescale              uint        `xml:"timescale,attr,omitempty"`
+	PresentationTimeOffset uint64      `xml:"presentationTimeOffset,attr,omitempty"`
 }
 
 func (t *EventStreamType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	type T EventStreamType
 	var overlay struct {
 		*T
-		Actuate *ActuateType `xml:"actuate,attr,omitempty"`
+		Actuate                *ActuateType `xml:"actuate,attr,omitempty"`
+		PresentationTimeOffset *uint64      `xml:"presentationTimeOffset,attr,omitempty"`
 	}
 	overlay.T = (*T)(t)
 	overlay.Actuate = (*ActuateType)(&overlay.T.Actuate)
+	overlay.PresentationTimeOffset = (*uint64)(&overlay.T.PresentationTimeOffset)
 	return d.DecodeElement(&overlay, &start)
 }
 
@@ -155,11 +183,94 @@ func (t *EventType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 // Must match the pattern [0-9]*[0-9](/[0-9]*[0-9])?
 type FrameRateType string
 
+type InitializationSetType struct {
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	Accessibility             []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Accessibility,omitempty"`
+	Role                      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Role,omitempty"`
+	Rating                    []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Rating,omitempty"`
+	Viewpoint                 []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Viewpoint,omitempty"`
+	Href                      string                      `xml:"href,attr,omitempty"`
+	Actuate                   ActuateType                 `xml:"actuate,attr,omitempty"`
+	Id                        uint                        `xml:"id,attr"`
+	InAllPeriods              bool                        `xml:"inAllPeriods,attr,omitempty"`
+	ContentType               string                      `xml:"contentType,attr,omitempty"`
+	Par                       RatioType                   `xml:"par,attr,omitempty"`
+	MaxWidth                  uint                        `xml:"maxWidth,attr,omitempty"`
+	MaxHeight                 uint                        `xml:"maxHeight,attr,omitempty"`
+	MaxFrameRate              FrameRateType               `xml:"maxFrameRate,attr,omitempty"`
+	Initialization            string                      `xml:"initialization,attr,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
+}
+
+func (t *InitializationSetType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T InitializationSetType
+	var overlay struct {
+		*T
+		Actuate      *ActuateType `xml:"actuate,attr,omitempty"`
+		InAllPeriods *bool        `xml:"inAllPeriods,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.Actuate = (*ActuateType)(&overlay.T.Actuate)
+	overlay.InAllPeriods = (*bool)(&overlay.T.InAllPeriods)
+	return d.DecodeElement(&overlay, &start)
+}
+
+type LabelType struct {
+	Value string `xml:",chardata"`
+	Id    uint   `xml:"id,attr,omitempty"`
+	Lang  string `xml:"lang,attr,omitempty"`
+}
+
+func (t *LabelType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T LabelType
+	var overlay struct {
+		*T
+		Id *uint `xml:"id,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.Id = (*uint)(&overlay.T.Id)
+	return d.DecodeElement(&overlay, &start)
+}
+
+type LatencyType struct {
+	Items       []string `xml:",any"`
+	ReferenceId uint     `xml:"referenceId,attr,omitempty"`
+	Target      uint     `xml:"target,attr,omitempty"`
+	Max         uint     `xml:"max,attr,omitempty"`
+	Min         uint     `xml:"min,attr,omitempty"`
+}
+
 type MPDtype struct {
 	Items                      []string                 `xml:",any"`
 	ProgramInformation         []ProgramInformationType `xml:"urn:mpeg:dash:schema:mpd:2011 ProgramInformation,omitempty"`
 	BaseURL                    []BaseURLType            `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
 	Location                   []string                 `xml:"urn:mpeg:dash:schema:mpd:2011 Location,omitempty"`
+	PatchLocation              []PatchLocationType      `xml:"urn:mpeg:dash:schema:mpd:2011 PatchLocation,omitempty"`
+	ServiceDescription         []ServiceDescriptionType `xml:"urn:mpeg:dash:schema:mpd:2011 ServiceDescription,omitempty"`
+	InitializationSet          []InitializationSetType  `xml:"urn:mpeg:dash:schema:mpd:2011 InitializationSet,omitempty"`
+	ContentSteering            ContentSteeringType      `xml:"urn:mpeg:dash:schema:mpd:2011 ContentSteering,omitempty"`
 	Period                     []PeriodType             `xml:"urn:mpeg:dash:schema:mpd:2011 Period"`
 	Metrics                    []MetricsType            `xml:"urn:mpeg:dash:schema:mpd:2011 Metrics,omitempty"`
 	EssentialProperty          []DescriptorType         `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
@@ -251,23 +362,70 @@ func (t *MultipleSegmentBaseType) UnmarshalXML(d *xml.Decoder, start xml.StartEl
 	return d.DecodeElement(&overlay, &start)
 }
 
+type OperatingBandwidthType struct {
+	Items     []string `xml:",any"`
+	MediaType string   `xml:"mediaType,attr,omitempty"`
+	Min       uint     `xml:"min,attr,omitempty"`
+	Max       uint     `xml:"max,attr,omitempty"`
+	Target    uint     `xml:"target,attr,omitempty"`
+}
+
+func (t *OperatingBandwidthType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T OperatingBandwidthType
+	var overlay struct {
+		*T
+		MediaType *string `xml:"mediaType,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.MediaType = (*string)(&overlay.T.MediaType)
+	return d.DecodeElement(&overlay, &start)
+}
+
+type OperatingQualityType struct {
+	Items                []string `xml:",any"`
+	MediaType            string   `xml:"mediaType,attr,omitempty"`
+	Min                  uint     `xml:"min,attr,omitempty"`
+	Max                  uint     `xml:"max,attr,omitempty"`
+	Target               uint     `xml:"target,attr,omitempty"`
+	Type                 string   `xml:"type,attr,omitempty"`
+	MaxQualityDifference uint     `xml:"maxQualityDifference,attr,omitempty"`
+}
+
+func (t *OperatingQualityType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T OperatingQualityType
+	var overlay struct {
+		*T
+		MediaType *string `xml:"mediaType,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.MediaType = (*string)(&overlay.T.MediaType)
+	return d.DecodeElement(&overlay, &start)
+}
+
+type PatchLocationType struct {
+	Value string  `xml:",chardata"`
+	Ttl   float64 `xml:"ttl,attr,omitempty"`
+}
+
 type PeriodType struct {
-	Items                []string            `xml:",any"`
-	BaseURL              []BaseURLType       `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
-	SegmentBase          SegmentBaseType     `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
-	SegmentList          SegmentListType     `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
-	SegmentTemplate      SegmentTemplateType `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
-	AssetIdentifier      DescriptorType      `xml:"urn:mpeg:dash:schema:mpd:2011 AssetIdentifier,omitempty"`
-	EventStream          []EventStreamType   `xml:"urn:mpeg:dash:schema:mpd:2011 EventStream,omitempty"`
-	AdaptationSet        []AdaptationSetType `xml:"urn:mpeg:dash:schema:mpd:2011 AdaptationSet,omitempty"`
-	Subset               []SubsetType        `xml:"urn:mpeg:dash:schema:mpd:2011 Subset,omitempty"`
-	SupplementalProperty []DescriptorType    `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
-	Href                 string              `xml:"href,attr,omitempty"`
-	Actuate              ActuateType         `xml:"actuate,attr,omitempty"`
-	Id                   string              `xml:"id,attr,omitempty"`
-	Start                string              `xml:"start,attr,omitempty"`
-	Duration             string              `xml:"duration,attr,omitempty"`
-	BitstreamSwitching   bool                `xml:"bitstreamSwitching,attr,omitempty"`
+	Items                []string                 `xml:",any"`
+	BaseURL              []BaseURLType            `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
+	SegmentBase          SegmentBaseType          `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
+	SegmentList          SegmentListType          `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
+	SegmentTemplate      SegmentTemplateType      `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
+	AssetIdentifier      DescriptorType           `xml:"urn:mpeg:dash:schema:mpd:2011 AssetIdentifier,omitempty"`
+	EventStream          []EventStreamType        `xml:"urn:mpeg:dash:schema:mpd:2011 EventStream,omitempty"`
+	ServiceDescription   []ServiceDescriptionType `xml:"urn:mpeg:dash:schema:mpd:2011 ServiceDescription,omitempty"`
+	AdaptationSet        []AdaptationSetType      `xml:"urn:mpeg:dash:schema:mpd:2011 AdaptationSet,omitempty"`
+	Subset               []SubsetType             `xml:"urn:mpeg:dash:schema:mpd:2011 Subset,omitempty"`
+	SupplementalProperty []DescriptorType         `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	Preselection         []PreselectionType       `xml:"urn:mpeg:dash:schema:mpd:2011 Preselection,omitempty"`
+	Href                 string                   `xml:"href,attr,omitempty"`
+	Actuate              ActuateType              `xml:"actuate,attr,omitempty"`
+	Id                   string                   `xml:"id,attr,omitempty"`
+	Start                string                   `xml:"start,attr,omitempty"`
+	Duration             string                   `xml:"duration,attr,omitempty"`
+	BitstreamSwitching   bool                     `xml:"bitstreamSwitching,attr,omitempty"`
 }
 
 func (t *PeriodType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -283,9 +441,93 @@ func (t *PeriodType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	return d.DecodeElement(&overlay, &start)
 }
 
+type PlaybackRateType struct {
+	Items []string `xml:",any"`
+	Max   float64  `xml:"max,attr,omitempty"`
+	Min   float64  `xml:"min,attr,omitempty"`
+}
+
+// May be one of undefined, time-ordered, fully-ordered
+type PreselectionOrderType string
+
+type PreselectionType struct {
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	Accessibility             []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Accessibility,omitempty"`
+	Role                      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Role,omitempty"`
+	Rating                    []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Rating,omitempty"`
+	Viewpoint                 []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 Viewpoint,omitempty"`
+	Id                        StringNoWhitespaceType      `xml:"id,attr,omitempty"`
+	PreselectionComponents    StringVectorType            `xml:"preselectionComponents,attr"`
+	Lang                      string                      `xml:"lang,attr,omitempty"`
+	Order                     PreselectionOrderType       `xml:"order,attr,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
+}
+
+func (t *PreselectionType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T PreselectionType
+	var overlay struct {
+		*T
+		Id    *StringNoWhitespaceType `xml:"id,attr,omitempty"`
+		Order *PreselectionOrderType  `xml:"order,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.Id = (*StringNoWhitespaceType)(&overlay.T.Id)
+	overlay.Order = (*PreselectionOrderType)(&overlay.T.Order)
+	return d.DecodeElement(&overlay, &start)
+}
+
 // May be one of static, dynamic
 type PresentationType string
 
+type ProducerReferenceTimeType struct {
+	Items             []string                      `xml:",any"`
+	UTCTiming         DescriptorType                `xml:"urn:mpeg:dash:schema:mpd:2011 UTCTiming,omitempty"`
+	Id                uint                          `xml:"id,attr"`
+	Inband            bool                          `xml:"inband,attr,omitempty"`
+	Type              ProducerReferenceTimeTypeType `xml:"type,attr,omitempty"`
+	ApplicationScheme string                        `xml:"applicationScheme,attr,omitempty"`
+	WallClockTime     string                        `xml:"wallClockTime,attr"`
+	PresentationTime  uint64                        `xml:"presentationTime,attr"`
+}
+
+func (t *ProducerReferenceTimeType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T ProducerReferenceTimeType
+	var overlay struct {
+		*T
+		Inband *bool                          `xml:"inband,attr,omitempty"`
+		Type   *ProducerReferenceTimeTypeType `xml:"type,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.Inband = (*bool)(&overlay.T.Inband)
+	overlay.Type = (*ProducerReferenceTimeTypeType)(&overlay.T.Type)
+	return d.DecodeElement(&overlay, &start)
+}
+
+// May be one of encoder, captured, application
+type ProducerReferenceTimeTypeType string
+
 type ProgramInformationType struct {
 	Items              []string `xml:",any"`
 	Title              string   `xml:"urn:mpeg:dash:schema:mpd:2011 Title,omitempty"`
@@ -304,61 +546,91 @@ type RangeType struct {
 type RatioType string
 
 type RepresentationBaseType struct {
-	Items                     []string          `xml:",any"`
-	FramePacking              []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
-	AudioChannelConfiguration []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
-	ContentProtection         []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
-	EssentialProperty         []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
-	SupplementalProperty      []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
-	InbandEventStream         []EventStreamType `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
-	Profiles                  string            `xml:"profiles,attr,omitempty"`
-	Width                     uint              `xml:"width,attr,omitempty"`
-	Height                    uint              `xml:"height,attr,omitempty"`
-	Sar                       RatioType         `xml:"sar,attr,omitempty"`
-	FrameRate                 FrameRateType     `xml:"frameRate,attr,omitempty"`
-	AudioSamplingRate         string            `xml:"audioSamplingRate,attr,omitempty"`
-	MimeType                  string            `xml:"mimeType,attr,omitempty"`
-	SegmentProfiles           string            `xml:"segmentProfiles,attr,omitempty"`
-	Codecs                    string            `xml:"codecs,attr,omitempty"`
-	MaximumSAPPeriod          float64           `xml:"maximumSAPPeriod,attr,omitempty"`
-	StartWithSAP              uint              `xml:"startWithSAP,attr,omitempty"`
-	MaxPlayoutRate            float64           `xml:"maxPlayoutRate,attr,omitempty"`
-	CodingDependency          bool              `xml:"codingDependency,attr,omitempty"`
-	ScanType                  VideoScanType     `xml:"scanType,attr,omitempty"`
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
 }
 
 type RepresentationType struct {
-	Items                     []string                `xml:",any"`
-	FramePacking              []DescriptorType        `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
-	AudioChannelConfiguration []DescriptorType        `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
-	ContentProtection         []DescriptorType        `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
-	EssentialProperty         []DescriptorType        `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
-	SupplementalProperty      []DescriptorType        `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
-	InbandEventStream         []EventStreamType       `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
-	BaseURL                   []BaseURLType           `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
-	SubRepresentation         []SubRepresentationType `xml:"urn:mpeg:dash:schema:mpd:2011 SubRepresentation,omitempty"`
-	SegmentBase               SegmentBaseType         `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
-	SegmentList               SegmentListType         `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
-	SegmentTemplate           SegmentTemplateType     `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
-	Id                        StringNoWhitespaceType  `xml:"id,attr"`
-	Bandwidth                 uint                    `xml:"bandwidth,attr"`
-	QualityRanking            uint                    `xml:"qualityRanking,attr,omitempty"`
-	DependencyId              StringVectorType        `xml:"dependencyId,attr,omitempty"`
-	MediaStreamStructureId    StringVectorType        `xml:"mediaStreamStructureId,attr,omitempty"`
-	Profiles                  string                  `xml:"profiles,attr,omitempty"`
-	Width                     uint                    `xml:"width,attr,omitempty"`
-	Height                    uint                    `xml:"height,attr,omitempty"`
-	Sar                       RatioType               `xml:"sar,attr,omitempty"`
-	FrameRate                 FrameRateType           `xml:"frameRate,attr,omitempty"`
-	AudioSamplingRate         string                  `xml:"audioSamplingRate,attr,omitempty"`
-	MimeType                  string                  `xml:"mimeType,attr,omitempty"`
-	SegmentProfiles           string                  `xml:"segmentProfiles,attr,omitempty"`
-	Codecs                    string                  `xml:"codecs,attr,omitempty"`
-	MaximumSAPPeriod          float64                 `xml:"maximumSAPPeriod,attr,omitempty"`
-	StartWithSAP              uint                    `xml:"startWithSAP,attr,omitempty"`
-	MaxPlayoutRate            float64                 `xml:"maxPlayoutRate,attr,omitempty"`
-	CodingDependency          bool                    `xml:"codingDependency,attr,omitempty"`
-	ScanType                  VideoScanType           `xml:"scanType,attr,omitempty"`
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	BaseURL                   []BaseURLType               `xml:"urn:mpeg:dash:schema:mpd:2011 BaseURL,omitempty"`
+	SubRepresentation         []SubRepresentationType     `xml:"urn:mpeg:dash:schema:mpd:2011 SubRepresentation,omitempty"`
+	SegmentBase               SegmentBaseType             `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentBase,omitempty"`
+	SegmentList               SegmentListType             `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentList,omitempty"`
+	SegmentTemplate           SegmentTemplateType         `xml:"urn:mpeg:dash:schema:mpd:2011 SegmentTemplate,omitempty"`
+	Id                        StringNoWhitespaceType      `xml:"id,attr"`
+	Bandwidth                 uint                        `xml:"bandwidth,attr"`
+	QualityRanking            uint                        `xml:"qualityRanking,attr,omitempty"`
+	DependencyId              StringVectorType            `xml:"dependencyId,attr,omitempty"`
+	MediaStreamStructureId    StringVectorType            `xml:"mediaStreamStructureId,attr,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
+}
+
+type ResyncType struct {
+	Items  []string `xml:",any"`
+	Type   uint     `xml:"type,attr,omitempty"`
+	DT     uint     `xml:"dT,attr,omitempty"`
+	DImax  float64  `xml:"dImax,attr,omitempty"`
+	DImin  float64  `xml:"dImin,attr,omitempty"`
+	Marker bool     `xml:"marker,attr,omitempty"`
+}
+
+func (t *ResyncType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type T ResyncType
+	var overlay struct {
+		*T
+		Type   *uint    `xml:"type,attr,omitempty"`
+		DImin  *float64 `xml:"dImin,attr,omitempty"`
+		Marker *bool    `xml:"marker,attr,omitempty"`
+	}
+	overlay.T = (*T)(t)
+	overlay.Type = (*uint)(&overlay.T.Type)
+	overlay.DImin = (*float64)(&overlay.T.DImin)
+	overlay.Marker = (*bool)(&overlay.T.Marker)
+	return d.DecodeElement(&overlay, &start)
 }
 
 type S struct {
@@ -478,6 +750,16 @@ type SegmentURLType struct {
 	IndexRange string   `xml:"indexRange,attr,omitempty"`
 }
 
+type ServiceDescriptionType struct {
+	Items              []string                 `xml:",any"`
+	Scope              []DescriptorType         `xml:"urn:mpeg:dash:schema:mpd:2011 Scope,omitempty"`
+	Latency            LatencyType              `xml:"urn:mpeg:dash:schema:mpd:2011 Latency,omitempty"`
+	PlaybackRate       PlaybackRateType         `xml:"urn:mpeg:dash:schema:mpd:2011 PlaybackRate,omitempty"`
+	OperatingQuality   []OperatingQualityType   `xml:"urn:mpeg:dash:schema:mpd:2011 OperatingQuality,omitempty"`
+	OperatingBandwidth []OperatingBandwidthType `xml:"urn:mpeg:dash:schema:mpd:2011 OperatingBandwidth,omitempty"`
+	Id                 uint                     `xml:"id,attr,omitempty"`
+}
+
 // Must match the pattern [^\r\n\t \p{Z}]*
 type StringNoWhitespaceType string
 
@@ -498,31 +780,34 @@ func (x *StringVectorType) UnmarshalText(text []byte) error {
 }
 
 type SubRepresentationType struct {
-	Items                     []string          `xml:",any"`
-	FramePacking              []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
-	AudioChannelConfiguration []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
-	ContentProtection         []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
-	EssentialProperty         []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
-	SupplementalProperty      []DescriptorType  `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
-	InbandEventStream         []EventStreamType `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
-	Level                     uint              `xml:"level,attr,omitempty"`
-	DependencyLevel           UIntVectorType    `xml:"dependencyLevel,attr,omitempty"`
-	Bandwidth                 uint              `xml:"bandwidth,attr,omitempty"`
-	ContentComponent          StringVectorType  `xml:"contentComponent,attr,omitempty"`
-	Profiles                  string            `xml:"profiles,attr,omitempty"`
-	Width                     uint              `xml:"width,attr,omitempty"`
-	Height                    uint              `xml:"height,attr,omitempty"`
-	Sar                       RatioType         `xml:"sar,attr,omitempty"`
-	FrameRate                 FrameRateType     `xml:"frameRate,attr,omitempty"`
-	AudioSamplingRate         string            `xml:"audioSamplingRate,attr,omitempty"`
-	MimeType                  string            `xml:"mimeType,attr,omitempty"`
-	SegmentProfiles           string            `xml:"segmentProfiles,attr,omitempty"`
-	Codecs                    string            `xml:"codecs,attr,omitempty"`
-	MaximumSAPPeriod          float64           `xml:"maximumSAPPeriod,attr,omitempty"`
-	StartWithSAP              uint              `xml:"startWithSAP,attr,omitempty"`
-	MaxPlayoutRate            float64           `xml:"maxPlayoutRate,attr,omitempty"`
-	CodingDependency          bool              `xml:"codingDependency,attr,omitempty"`
-	ScanType                  VideoScanType     `xml:"scanType,attr,omitempty"`
+	Items                     []string                    `xml:",any"`
+	FramePacking              []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 FramePacking,omitempty"`
+	AudioChannelConfiguration []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 AudioChannelConfiguration,omitempty"`
+	ContentProtection         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 ContentProtection,omitempty"`
+	EssentialProperty         []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 EssentialProperty,omitempty"`
+	SupplementalProperty      []DescriptorType            `xml:"urn:mpeg:dash:schema:mpd:2011 SupplementalProperty,omitempty"`
+	InbandEventStream         []EventStreamType           `xml:"urn:mpeg:dash:schema:mpd:2011 InbandEventStream,omitempty"`
+	Label                     []LabelType                 `xml:"urn:mpeg:dash:schema:mpd:2011 Label,omitempty"`
+	ProducerReferenceTime     []ProducerReferenceTimeType `xml:"urn:mpeg:dash:schema:mpd:2011 ProducerReferenceTime,omitempty"`
+	Resync                    []ResyncType                `xml:"urn:mpeg:dash:schema:mpd:2011 Resync,omitempty"`
+	Level                     uint                        `xml:"level,attr,omitempty"`
+	DependencyLevel           UIntVectorType              `xml:"dependencyLevel,attr,omitempty"`
+	Bandwidth                 uint                        `xml:"bandwidth,attr,omitempty"`
+	ContentComponent          StringVectorType            `xml:"contentComponent,attr,omitempty"`
+	Profiles                  string                      `xml:"profiles,attr,omitempty"`
+	Width                     uint                        `xml:"width,attr,omitempty"`
+	Height                    uint                        `xml:"height,attr,omitempty"`
+	Sar                       RatioType                   `xml:"sar,attr,omitempty"`
+	FrameRate                 FrameRateType               `xml:"frameRate,attr,omitempty"`
+	AudioSamplingRate         string                      `xml:"audioSamplingRate,attr,omitempty"`
+	MimeType                  string                      `xml:"mimeType,attr,omitempty"`
+	SegmentProfiles           string                      `xml:"segmentProfiles,attr,omitempty"`
+	Codecs                    string                      `xml:"codecs,attr,omitempty"`
+	MaximumSAPPeriod          float64                     `xml:"maximumSAPPeriod,attr,omitempty"`
+	StartWithSAP              uint                        `xml:"startWithSAP,attr,omitempty"`
+	MaxPlayoutRate            float64                     `xml:"maxPlayoutRate,attr,omitempty"`
+	CodingDependency          bool                        `xml:"codingDependency,attr,omitempty"`
+	ScanType                  VideoScanType               `xml:"scanType,attr,omitempty"`
 }
 
 type SubsetType struct {
@@ -81,3 +81,77 @@ func TestNewDashClient(t *testing.T) {
 		t.Logf("================ %v =================", file)
 	}
 }
+
+func TestFifthEditionTypes(t *testing.T) {
+	mpd, err := dashreader.ReadMPDFromFile("test/static_5thedition.mpd")
+	if err != nil {
+		t.Fatalf("Error reading : %v", err)
+	}
+	if len(mpd.Items) != 0 || len(mpd.Period[0].Items) != 0 || len(mpd.Period[0].AdaptationSet[0].Items) != 0 {
+		t.Errorf("Unexpected Items MPD:%v Period:%v AdaptationSet:%v", mpd.Items, mpd.Period[0].Items, mpd.Period[0].AdaptationSet[0].Items)
+	}
+	if len(mpd.PatchLocation) != 1 || mpd.PatchLocation[0].Value != "patch.mpp" || mpd.PatchLocation[0].Ttl != 60 {
+		t.Errorf("PatchLocation %+v", mpd.PatchLocation)
+	}
+	if len(mpd.ServiceDescription) != 1 {
+		t.Fatalf("ServiceDescription %+v", mpd.ServiceDescription)
+	}
+	sd := mpd.ServiceDescription[0]
+	if len(sd.Scope) != 1 || sd.Latency.Target != 4000 || sd.Latency.Min != 2000 || sd.Latency.Max != 6000 ||
+		sd.PlaybackRate.Min != 0.96 || sd.PlaybackRate.Max != 1.04 {
+		t.Errorf("ServiceDescription %+v", sd)
+	}
+	if len(sd.OperatingQuality) != 1 || sd.OperatingQuality[0].Target != 4 ||
+		len(sd.OperatingBandwidth) != 1 || sd.OperatingBandwidth[0].MediaType != "video" || sd.OperatingBandwidth[0].Target != 2000000 {
+		t.Errorf("ServiceDescription operating %+v %+v", sd.OperatingQuality, sd.OperatingBandwidth)
+	}
+	if len(mpd.InitializationSet) != 1 {
+		t.Fatalf("InitializationSet %+v", mpd.InitializationSet)
+	}
+	initSet := mpd.InitializationSet[0]
+	if initSet.Id != 1 || initSet.InAllPeriods || initSet.MaxWidth != 1280 ||
+		initSet.Codecs != "avc1.64001f" || len(initSet.Role) != 1 {
+		t.Errorf("InitializationSet %+v", initSet)
+	}
+	steering := mpd.ContentSteering
+	if steering.Value != "https://steering.example.com/steer" || steering.DefaultServiceLocation != "cdn-a" ||
+		!steering.QueryBeforeStart || steering.ClientRequirement {
+		t.Errorf("ContentSteering %+v", steering)
+	}
+	period := mpd.Period[0]
+	if len(period.ServiceDescription) != 1 || period.ServiceDescription[0].Latency.Target != 3000 {
+		t.Errorf("Period ServiceDescription %+v", period.ServiceDescription)
+	}
+	//SegmentTemplate still read through the overlays
+	if period.SegmentTemplate.Duration != 2000 || period.SegmentTemplate.StartNumber != 1 {
+		t.Errorf("Period SegmentTemplate %+v", period.SegmentTemplate)
+	}
+	video := period.AdaptationSet[0]
+	if len(video.Label) != 2 || video.Label[0].Value != "Main camera" || video.Label[0].Id != 1 || video.Label[0].Lang != "en" ||
+		video.Label[1].Lang != "fr" || video.Label[1].Id != 0 {
+		t.Errorf("Label %+v", video.Label)
+	}
+	if len(video.ProducerReferenceTime) != 1 {
+		t.Fatalf("ProducerReferenceTime %+v", video.ProducerReferenceTime)
+	}
+	prt := video.ProducerReferenceTime[0]
+	if prt.Type != "captured" || prt.Inband || prt.WallClockTime != "2020-01-01T00:00:00Z" ||
+		prt.UTCTiming.SchemeIdUri != dashreader.UTCTimingHTTPISO {
+		t.Errorf("ProducerReferenceTime %+v", prt)
+	}
+	if len(video.Resync) != 1 || video.Resync[0].Type != 1 || video.Resync[0].DT != 500 || video.Resync[0].DImax != 0.5 || !video.Resync[0].Marker {
+		t.Errorf("Resync %+v", video.Resync)
+	}
+	rpPrt := video.Representation[0].ProducerReferenceTime
+	if len(rpPrt) != 1 || !rpPrt[0].Inband || rpPrt[0].PresentationTime != 1000 {
+		t.Errorf("Representation ProducerReferenceTime %+v", rpPrt)
+	}
+	if len(period.Preselection) != 1 {
+		t.Fatalf("Preselection %+v", period.Preselection)
+	}
+	presel := period.Preselection[0]
+	if presel.Id != "ps1" || len(presel.PreselectionComponents) != 2 || presel.PreselectionComponents[1] != "2" ||
+		presel.Order != "time-ordered" || presel.Lang != "en" || len(presel.Role) != 1 || len(presel.Label) != 1 {
+		t.Errorf("Preselection %+v", presel)
+	}
+}